@@ -0,0 +1,121 @@
+package v1
+
+import (
+	"net/http"
+	"swift-app/internal/auth"
+	"swift-app/internal/models"
+	"swift-app/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateAPIKey handles POST requests to issue a new API key.
+//
+// The plain-text key is returned only in this response; afterwards only its hash is stored.
+//
+// @Summary Create API key
+// @Description Issues a new API key with the given owner, scopes, optional expiry and per-key rate limit. Requires swift:admin.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param apiKey body models.CreateAPIKeyRequest true "API key to create"
+// @Success 201 {object} models.APIKeyCreatedResponse
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 429 {object} models.ProblemDetails
+// @Failure 504 {object} models.ProblemDetails
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/admin/api-keys [post]
+func CreateAPIKey(c *gin.Context, keyStore *auth.KeyStore) {
+	var request models.CreateAPIKeyRequest
+	if err := bindJSON(c, &request); err != nil {
+		respondError(c, err)
+		return
+	}
+	created, err := keyStore.Create(c.Request.Context(), request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// ListAPIKeys handles GET requests to list all API keys with their scopes, owner and usage.
+//
+// @Summary List API keys
+// @Description Lists API keys with their owner, scopes, last-used time, expiry and revocation state. Requires swift:admin.
+// @Tags API Keys
+// @Produce json
+// @Success 200 {object} models.APIKeysResponse
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 429 {object} models.ProblemDetails
+// @Failure 504 {object} models.ProblemDetails
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/admin/api-keys [get]
+func ListAPIKeys(c *gin.Context, keyStore *auth.KeyStore) {
+	keys, err := keyStore.List(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.APIKeysResponse{Keys: keys})
+}
+
+// RotateAPIKey handles POST requests to replace the secret of an API key.
+//
+// The old secret stops working immediately and the new one is returned only in this response.
+//
+// @Summary Rotate API key
+// @Description Replaces the secret of an active API key, keeping its owner, scopes and limits. Requires swift:admin.
+// @Tags API Keys
+// @Produce json
+// @Param id path string true "API key ID"
+// @Success 200 {object} models.APIKeyCreatedResponse
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 429 {object} models.ProblemDetails
+// @Failure 404 {object} models.ProblemDetails
+// @Failure 409 {object} models.ProblemDetails
+// @Failure 504 {object} models.ProblemDetails
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/admin/api-keys/{id}/rotate [post]
+func RotateAPIKey(c *gin.Context, keyStore *auth.KeyStore) {
+	rotated, err := keyStore.Rotate(c.Request.Context(), c.Param(utils.ParamAPIKeyID))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rotated)
+}
+
+// RevokeAPIKey handles DELETE requests to revoke an API key.
+//
+// Revoked keys are kept for auditing and listed with their revocation time.
+//
+// @Summary Revoke API key
+// @Description Permanently disables an API key. Requires swift:admin.
+// @Tags API Keys
+// @Produce json
+// @Param id path string true "API key ID"
+// @Success 200 {object} models.APIKey
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 429 {object} models.ProblemDetails
+// @Failure 404 {object} models.ProblemDetails
+// @Failure 504 {object} models.ProblemDetails
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/admin/api-keys/{id} [delete]
+func RevokeAPIKey(c *gin.Context, keyStore *auth.KeyStore) {
+	revoked, err := keyStore.Revoke(c.Request.Context(), c.Param(utils.ParamAPIKeyID))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, revoked)
+}
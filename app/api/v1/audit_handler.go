@@ -0,0 +1,100 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"swift-app/internal/audit"
+	"swift-app/internal/errors"
+	"swift-app/internal/models"
+	"swift-app/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Supported audit export formats.
+const (
+	auditFormatJSON      = "json"
+	auditFormatJSONLines = "jsonl"
+)
+
+// ListAuditEntries handles GET requests to query the audit trail of mutating API calls.
+//
+// Entries can be filtered by time range and actor. With format=jsonl the matching entries
+// are streamed as JSON lines, one entry per line, suitable for export.
+//
+// @Summary Query audit log
+// @Description Returns audit entries of mutating API calls, optionally filtered by time range and actor. Requires swift:admin.
+// @Tags Audit
+// @Produce json
+// @Produce application/x-ndjson
+// @Param from query string false "Start of the time range (RFC 3339 timestamp or YYYY-MM-DD)"
+// @Param to query string false "End of the time range (RFC 3339 timestamp or YYYY-MM-DD, inclusive)"
+// @Param actor query string false "Actor identity"
+// @Param limit query int false "Maximum number of entries"
+// @Param format query string false "Response format" Enums(json, jsonl)
+// @Success 200 {object} models.AuditEntriesResponse
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 429 {object} models.ProblemDetails
+// @Failure 504 {object} models.ProblemDetails
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/audit [get]
+func ListAuditEntries(c *gin.Context, auditStore *audit.Store) {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	switch c.DefaultQuery(utils.QueryFormat, auditFormatJSON) {
+	case auditFormatJSON:
+		entries, err := auditStore.Find(c.Request.Context(), filter)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, models.AuditEntriesResponse{Entries: entries})
+	case auditFormatJSONLines:
+		c.Header("Content-Type", "application/x-ndjson")
+		c.Header("Content-Disposition", `attachment; filename="audit.jsonl"`)
+		c.Status(http.StatusOK)
+		if err := auditStore.Export(c.Request.Context(), filter, c.Writer); err != nil {
+			_ = c.Error(err)
+		}
+	default:
+		respondError(c, errors.Wrap(errors.ErrBadRequest, "unsupported format '%s', expected json or jsonl", c.Query(utils.QueryFormat)).
+			WithCode(errors.CodeInvalidQuery))
+	}
+}
+
+func parseAuditFilter(c *gin.Context) (audit.Filter, error) {
+	filter := audit.Filter{Actor: c.Query(utils.QueryActor)}
+
+	if from := c.Query(utils.QueryFrom); from != "" {
+		t, err := utils.ParseRangeStart(from)
+		if err != nil {
+			return filter, err
+		}
+		filter.From = t
+	}
+	if to := c.Query(utils.QueryTo); to != "" {
+		t, err := utils.ParseAsOf(to)
+		if err != nil {
+			return filter, err
+		}
+		filter.To = t
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return filter, errors.Wrap(errors.ErrBadRequest, "'to' must not be before 'from'").WithCode(errors.CodeInvalidQuery)
+	}
+	if limit := c.Query(utils.QueryLimit); limit != "" {
+		n, err := strconv.ParseInt(limit, 10, 64)
+		if err != nil || n <= 0 {
+			return filter, errors.Wrap(errors.ErrBadRequest, "limit must be a positive integer").WithCode(errors.CodeInvalidQuery)
+		}
+		filter.Limit = n
+	}
+	return filter, nil
+}
@@ -0,0 +1,151 @@
+package v1
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"swift-app/internal/errors"
+	"swift-app/internal/utils"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// unknownFieldPattern extracts the field name from encoding/json's DisallowUnknownFields error.
+var unknownFieldPattern = regexp.MustCompile(`^json: unknown field "(.+)"$`)
+
+var registerValidator sync.Once
+
+// bindJSON decodes the request body into obj, rejecting fields the request model does not declare,
+// and checks its binding tags. Every invalid field is reported in a single error.
+func bindJSON(c *gin.Context, obj interface{}) error {
+	if c.Request == nil || c.Request.Body == nil {
+		return errors.Wrap(errors.ErrBadRequest, "request body is required").WithCode(errors.CodeInvalidBody)
+	}
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(obj); err != nil {
+		return decodeError(err)
+	}
+	return validateStruct(obj)
+}
+
+// decodeError turns a JSON decoding failure into a bad request error pointing at the offending field where possible.
+func decodeError(err error) error {
+	if match := unknownFieldPattern.FindStringSubmatch(err.Error()); match != nil {
+		field := match[1]
+		return errors.Collect(errors.Wrap(errors.ErrBadRequest, "%s is not a supported field", field).
+			WithCode(errors.CodeUnknownField).WithFields(jsonPointer(field)))
+	}
+	var typeErr *json.UnmarshalTypeError
+	if stderrors.As(err, &typeErr) && typeErr.Field != "" {
+		return errors.Collect(errors.Wrap(errors.ErrBadRequest, "%s must be a %s", typeErr.Field, typeErr.Type.String()).
+			WithCode(errors.CodeFieldFormat).WithFields(jsonPointer(typeErr.Field)))
+	}
+	if err == io.EOF {
+		return errors.Wrap(errors.ErrBadRequest, "request body is required").WithCode(errors.CodeInvalidBody)
+	}
+	return errors.Wrap(errors.ErrBadRequest, "Invalid input data or JSON format").
+		WithCode(errors.CodeInvalidBody).WithCause(err)
+}
+
+// validateStruct checks the binding tags of obj with Gin's validator.
+func validateStruct(obj interface{}) error {
+	registerValidator.Do(func() {
+		if engine, ok := binding.Validator.Engine().(*validator.Validate); ok {
+			engine.RegisterTagNameFunc(jsonFieldName)
+			_ = engine.RegisterValidation("swiftlen", func(fl validator.FieldLevel) bool {
+				return utils.ValidSwiftCodeLength(len(fl.Field().String()))
+			})
+		}
+	})
+
+	err := binding.Validator.ValidateStruct(obj)
+	if err == nil {
+		return nil
+	}
+	var fieldErrors validator.ValidationErrors
+	if !stderrors.As(err, &fieldErrors) {
+		return errors.Wrap(errors.ErrBadRequest, "Invalid input data or JSON format").
+			WithCode(errors.CodeInvalidBody).WithCause(err)
+	}
+
+	violations := make([]errors.Violation, 0, len(fieldErrors))
+	for _, fieldError := range fieldErrors {
+		violations = append(violations, violationFor(fieldError))
+	}
+	return errors.Invalid(violations...)
+}
+
+// violationFor describes a failed binding tag in the terms of the JSON request body.
+func violationFor(fieldError validator.FieldError) errors.Violation {
+	// The namespace starts with the struct name, e.g. "CreateAPIKeyRequest.scopes[1]".
+	path := fieldError.Namespace()
+	if i := strings.Index(path, "."); i >= 0 {
+		path = path[i+1:]
+	}
+	name := fieldError.Field()
+	param := fieldError.Param()
+	unit := ""
+	switch fieldError.Kind() {
+	case reflect.String:
+		unit = " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		unit = " items"
+	}
+
+	violation := errors.Violation{Field: jsonPointer(path), Code: errors.CodeFieldFormat}
+	switch fieldError.Tag() {
+	case "required":
+		violation.Code = errors.CodeFieldRequired
+		violation.Message = fmt.Sprintf("%s is required", name)
+	case "min":
+		violation.Code = errors.CodeFieldTooShort
+		violation.Message = fmt.Sprintf("%s must be at least %s%s", name, param, unit)
+	case "max":
+		violation.Code = errors.CodeFieldTooLong
+		violation.Message = fmt.Sprintf("%s must be at most %s%s", name, param, unit)
+	case "len":
+		violation.Code = errors.CodeFieldTooLong
+		if length, err := strconv.Atoi(param); err == nil && unit != "" && reflect.ValueOf(fieldError.Value()).Len() < length {
+			violation.Code = errors.CodeFieldTooShort
+		}
+		violation.Message = fmt.Sprintf("%s must be exactly %s%s", name, param, unit)
+	case "swiftlen":
+		// Same code as the domain check of the length, so both report it alike.
+		violation.Code = errors.CodeSwiftInvalidLength
+		violation.Message = fmt.Sprintf("%s must be 8 or 11 characters", name)
+	case "alpha":
+		violation.Message = fmt.Sprintf("%s must contain only letters", name)
+	case "alphanum":
+		violation.Message = fmt.Sprintf("%s must contain only letters and digits", name)
+	case "oneof":
+		violation.Code = errors.CodeFieldNotAllowed
+		violation.Message = fmt.Sprintf("%s must be one of: %s", name, strings.ReplaceAll(param, " ", ", "))
+	default:
+		violation.Message = fmt.Sprintf("%s is invalid", name)
+	}
+	return violation
+}
+
+// jsonFieldName names struct fields after their JSON keys in validation errors.
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+// jsonPointer converts a field path such as "scopes[1]" or "a.b" into a JSON pointer ("/scopes/1", "/a/b").
+func jsonPointer(path string) string {
+	path = strings.NewReplacer("[", ".", "]", "").Replace(path)
+	return "/" + strings.ReplaceAll(path, ".", "/")
+}
@@ -0,0 +1,94 @@
+// binding_test.go contains unit tests for strict JSON binding and field-level validation errors.
+package v1
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"swift-app/internal/errors"
+	"swift-app/internal/models"
+	"swift-app/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func bindBody(body string, obj interface{}) error {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("POST", "/", bytes.NewBufferString(body))
+	return bindJSON(c, obj)
+}
+
+func violationCodes(err error) map[string]string {
+	appErr, _ := errors.As(err)
+	codes := make(map[string]string)
+	for _, violation := range appErr.Violations {
+		codes[violation.Field] = violation.Code
+	}
+	return codes
+}
+
+func TestBindJSON_ReportsEveryInvalidField(t *testing.T) {
+	var request models.AddSwiftCodeRequest
+	err := bindBody(`{"swiftCode":"AB-1","countryISO2":"POL","isHeadquarter":true}`, &request)
+
+	assert.True(t, errors.HasCode(err, errors.CodeValidationFailed))
+	assert.Equal(t, map[string]string{
+		"/swiftCode":   errors.CodeSwiftInvalidLength,
+		"/bankName":    errors.CodeFieldRequired,
+		"/countryISO2": errors.CodeFieldTooLong,
+		"/countryName": errors.CodeFieldRequired,
+	}, violationCodes(err))
+}
+
+func TestBindJSON_SwiftCodeLengthMatchesDomainCheck(t *testing.T) {
+	for _, code := range []string{"AAAABBB", "AAAABBB12", "AAAABBB123", "AAAABBB1XXXX"} {
+		var request models.AddSwiftCodeRequest
+		err := bindBody(`{"swiftCode":"`+code+`","bankName":"Bank","countryISO2":"PL","countryName":"POLAND"}`, &request)
+		assert.Equal(t, map[string]string{"/swiftCode": errors.CodeSwiftInvalidLength}, violationCodes(err), code)
+		assert.True(t, errors.HasCode(utils.ValidateSwiftCode(code), errors.CodeSwiftInvalidLength), code)
+	}
+	for _, code := range []string{"AAAABBB1", "AAAABBB1001"} {
+		var request models.AddSwiftCodeRequest
+		assert.NoError(t, bindBody(`{"swiftCode":"`+code+`","bankName":"Bank","countryISO2":"PL","countryName":"POLAND"}`, &request), code)
+	}
+}
+
+func TestBindJSON_RejectsUnknownFields(t *testing.T) {
+	var request models.AddSwiftCodeRequest
+	err := bindBody(`{"swiftCode":"AAAABBB1XXX","bankName":"Bank","countryISO2":"PL","countryName":"POLAND","branches":[]}`, &request)
+
+	assert.True(t, errors.HasCode(err, errors.CodeUnknownField))
+	assert.Equal(t, map[string]string{"/branches": errors.CodeUnknownField}, violationCodes(err))
+}
+
+func TestBindJSON_ReportsTypeAndSyntaxErrors(t *testing.T) {
+	var request models.AddSwiftCodeRequest
+	err := bindBody(`{"isHeadquarter":"yes"}`, &request)
+	assert.Equal(t, map[string]string{"/isHeadquarter": errors.CodeFieldFormat}, violationCodes(err))
+
+	err = bindBody(`{"swiftCode":`, &request)
+	assert.True(t, errors.HasCode(err, errors.CodeInvalidBody))
+
+	err = bindBody(``, &request)
+	assert.True(t, errors.HasCode(err, errors.CodeInvalidBody))
+}
+
+func TestBindJSON_PointsIntoSlices(t *testing.T) {
+	var request models.CreateAPIKeyRequest
+	err := bindBody(`{"owner":"partner","scopes":["swift:read","swift:root"]}`, &request)
+
+	assert.Equal(t, map[string]string{"/scopes/1": errors.CodeFieldNotAllowed}, violationCodes(err))
+	appErr, _ := errors.As(err)
+	assert.Equal(t, "scopes[1] must be one of: swift:read, swift:write, swift:admin", appErr.Violations[0].Message)
+}
+
+func TestBindJSON_AcceptsValidBody(t *testing.T) {
+	var request models.AddSwiftCodeRequest
+	err := bindBody(`{"swiftCode":"AAAABBB1XXX","bankName":"Bank","countryISO2":"PL","countryName":"POLAND","isHeadquarter":true}`, &request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "AAAABBB1XXX", request.ToSwiftCode().SwiftCode)
+}
@@ -0,0 +1,25 @@
+package v1
+
+import (
+	"net/http"
+	"swift-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCacheStats handles GET requests to report lookup cache effectiveness.
+//
+// @Summary Get cache statistics
+// @Description Returns hit, miss, eviction and invalidation counters of the lookup cache. Requires swift:admin.
+// @Tags Cache
+// @Produce json
+// @Success 200 {object} models.CacheStats
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 429 {object} models.ProblemDetails
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/admin/cache [get]
+func GetCacheStats(c *gin.Context, swiftService *services.SwiftCodeService) {
+	c.JSON(http.StatusOK, swiftService.CacheStats())
+}
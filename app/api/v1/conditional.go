@@ -0,0 +1,91 @@
+package v1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"swift-app/internal/errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readCacheControl lets clients and private proxies store read responses but makes them revalidate
+// before every reuse, so a change shows up right away. Revalidation is cheap: unchanged data is
+// answered with 304.
+const readCacheControl = "private, no-cache"
+
+// computeETag returns a strong ETag derived from the JSON representation of body,
+// so equal content always yields the same tag regardless of when or where it was served.
+func computeETag(body interface{}) (string, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// respondCacheable writes body with ETag, Last-Modified and Cache-Control headers,
+// answering 304 Not Modified when the client's copy is still current.
+func respondCacheable(c *gin.Context, body interface{}, lastModified *time.Time) {
+	etag, err := computeETag(body)
+	if err != nil {
+		respondError(c, errors.Wrap(errors.ErrInternal, "error encoding response"))
+		return
+	}
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", readCacheControl)
+	if lastModified != nil {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(c.Request, etag, lastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.JSON(http.StatusOK, body)
+}
+
+// notModified evaluates If-None-Match, falling back to If-Modified-Since only when no ETag was sent.
+func notModified(r *http.Request, etag string, lastModified *time.Time) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		return matchesETag(ifNoneMatch, etag, true)
+	}
+	if ifModifiedSince := r.Header.Get("If-Modified-Since"); ifModifiedSince != "" && lastModified != nil {
+		since, err := http.ParseTime(ifModifiedSince)
+		return err == nil && !lastModified.Truncate(time.Second).After(since)
+	}
+	return false
+}
+
+// checkIfMatch enforces an If-Match precondition against the current ETag of a resource.
+// A request without If-Match always passes.
+func checkIfMatch(c *gin.Context, currentETag string) error {
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" || matchesETag(ifMatch, currentETag, false) {
+		return nil
+	}
+	return errors.Wrap(errors.ErrPreconditionFailed, "resource has changed, current ETag is %s", currentETag).
+		WithCode(errors.CodeETagMismatch)
+}
+
+// matchesETag reports whether a comma separated If-Match/If-None-Match header contains etag.
+// Weak comparison (ignoring a W/ prefix) is used for If-None-Match, strong comparison for If-Match.
+func matchesETag(header, etag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if weak {
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
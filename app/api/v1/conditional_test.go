@@ -0,0 +1,50 @@
+// conditional_test.go contains unit tests for ETag computation and conditional request evaluation.
+package v1
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"swift-app/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeETag_IsStable(t *testing.T) {
+	swift := models.SwiftCode{SwiftCode: "AAAABBB1XXX", BankName: "Test Bank", IsHeadquarter: true}
+
+	first, err := computeETag(swift)
+	assert.NoError(t, err)
+	second, err := computeETag(swift)
+	assert.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	swift.BankName = "Renamed Bank"
+	changed, err := computeETag(swift)
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, changed)
+}
+
+func TestMatchesETag(t *testing.T) {
+	assert.True(t, matchesETag(`"a", "b"`, `"b"`, false))
+	assert.True(t, matchesETag(`*`, `"b"`, false))
+	assert.True(t, matchesETag(`W/"b"`, `"b"`, true))
+	assert.False(t, matchesETag(`W/"b"`, `"b"`, false))
+	assert.False(t, matchesETag(`"a"`, `"b"`, true))
+}
+
+func TestNotModified(t *testing.T) {
+	lastModified := time.Date(2026, 3, 1, 12, 0, 0, 500, time.UTC)
+
+	req, _ := http.NewRequest("GET", "/", nil)
+	req.Header.Set("If-Modified-Since", lastModified.Format(http.TimeFormat))
+	assert.True(t, notModified(req, `"a"`, &lastModified))
+
+	req.Header.Set("If-None-Match", `"b"`)
+	assert.False(t, notModified(req, `"a"`, &lastModified), "If-None-Match takes precedence over If-Modified-Since")
+
+	req, _ = http.NewRequest("GET", "/", nil)
+	req.Header.Set("If-Modified-Since", lastModified.Add(-time.Hour).Format(http.TimeFormat))
+	assert.False(t, notModified(req, `"a"`, &lastModified))
+}
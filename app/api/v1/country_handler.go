@@ -0,0 +1,128 @@
+package v1
+
+import (
+	"net/http"
+	"swift-app/internal/models"
+	"swift-app/internal/services"
+	"swift-app/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListCountries handles GET requests to list the country reference data.
+//
+// Responses carry an ETag; a matching If-None-Match is answered with 304 Not Modified.
+//
+// @Summary List countries
+// @Description Returns every country with its ISO3 and numeric codes, currency, IBAN length and SEPA membership, and the version of the embedded table. Requires swift:read.
+// @Tags Countries
+// @Produce json
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Success 200 {object} models.CountriesResponse
+// @Success 304 {string} string "Not Modified"
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 429 {object} models.ProblemDetails
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/countries [get]
+func ListCountries(c *gin.Context, countryService *services.CountryService) {
+	response := countryService.ListCountries()
+	respondCacheable(c, response, response.UpdatedAt)
+}
+
+// GetCountry handles GET requests to retrieve one country by its ISO2 code.
+//
+// @Summary Get country
+// @Description Returns the reference data of a country. Overridden entries carry the time and actor of the override. Requires swift:read.
+// @Tags Countries
+// @Produce json
+// @Param iso2 path string true "Country ISO2 code"
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Success 200 {object} models.Country
+// @Success 304 {string} string "Not Modified"
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 404 {object} models.ProblemDetails
+// @Failure 429 {object} models.ProblemDetails
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/countries/{iso2} [get]
+func GetCountry(c *gin.Context, countryService *services.CountryService) {
+	country, err := countryService.GetCountry(c.Param(utils.ParamISO2))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	respondCacheable(c, country, country.UpdatedAt)
+}
+
+// OverrideCountry handles PUT requests to replace the reference data of a country.
+//
+// The body replaces the whole entry. Countries missing from the embedded table can be added the
+// same way. The override is stored and kept until it is removed.
+//
+// @Summary Override country
+// @Description Replaces or adds the reference data of a country, for example after a currency change. Requires swift:admin.
+// @Tags Countries
+// @Accept json
+// @Produce json
+// @Param iso2 path string true "Country ISO2 code"
+// @Param country body models.CountryRequest true "Country data"
+// @Success 200 {object} models.Country
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 429 {object} models.ProblemDetails
+// @Failure 504 {object} models.ProblemDetails
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/admin/countries/{iso2} [put]
+func OverrideCountry(c *gin.Context, countryService *services.CountryService) {
+	var request models.CountryRequest
+	if err := bindJSON(c, &request); err != nil {
+		respondError(c, err)
+		return
+	}
+	country, err := countryService.OverrideCountry(c.Request.Context(), c.Param(utils.ParamISO2), request, c.GetString(utils.ContextKeyActor))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, country)
+}
+
+// ResetCountry handles DELETE requests to remove the override of a country.
+//
+// The embedded entry is restored and returned. A country added through an override is removed,
+// which is answered with 204 No Content.
+//
+// @Summary Remove country override
+// @Description Restores the embedded reference data of a country. Requires swift:admin.
+// @Tags Countries
+// @Produce json
+// @Param iso2 path string true "Country ISO2 code"
+// @Success 200 {object} models.Country
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 404 {object} models.ProblemDetails
+// @Failure 429 {object} models.ProblemDetails
+// @Failure 504 {object} models.ProblemDetails
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/admin/countries/{iso2} [delete]
+func ResetCountry(c *gin.Context, countryService *services.CountryService) {
+	country, err := countryService.ResetCountry(c.Request.Context(), c.Param(utils.ParamISO2))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if country == nil {
+		c.Status(http.StatusNoContent)
+		return
+	}
+	c.JSON(http.StatusOK, country)
+}
@@ -0,0 +1,90 @@
+// export_handler_test.go contains tests for the export handler: the formats, the country filter and
+// the errors returned before anything is streamed.
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"swift-app/internal/models"
+	"swift-app/internal/services"
+	testutils "swift-app/internal/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func exportRequest(service *services.SwiftCodeService, query string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/v1/export?"+query, nil)
+	ExportSwiftCodes(c, service)
+	return w
+}
+
+func TestExportSwiftCodes(t *testing.T) {
+	clearCollection()
+	service := services.NewSwiftCodeService(testutils.Collection)
+
+	_, err := testutils.Collection.InsertMany(context.Background(), []interface{}{
+		bson.M{
+			"swiftCode": "AAAAPLPWXXX", "bankName": "TEST BANK", "address": "1 MAIN ST, WARSAW",
+			"countryISO2": "PL", "countryName": "POLAND", "isHeadquarter": true,
+			"branches": []bson.M{{
+				"swiftCode": "AAAAPLPWKRK", "bankName": "TEST BANK", "address": "2 SIDE ST",
+				"countryISO2": "PL", "isHeadquarter": false,
+			}},
+		},
+		bson.M{
+			"swiftCode": "BBBBDEFFXXX", "bankName": "OTHER BANK", "address": "3 RING ST",
+			"countryISO2": "DE", "countryName": "GERMANY", "isHeadquarter": true, "branches": []bson.M{},
+		},
+	})
+	assert.NoError(t, err)
+
+	w := exportRequest(service, "country=pl")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="swift-codes-PL.csv"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, `COUNTRY ISO2 CODE,SWIFT CODE,CODE TYPE,NAME,ADDRESS,TOWN NAME,COUNTRY NAME,TIME ZONE
+PL,AAAAPLPWXXX,BIC11,TEST BANK,"1 MAIN ST, WARSAW",,POLAND,
+PL,AAAAPLPWKRK,BIC11,TEST BANK,2 SIDE ST,,POLAND,
+`, w.Body.String())
+
+	w = exportRequest(service, "format=jsonl")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	decoder := json.NewDecoder(w.Body)
+	var codes []string
+	for decoder.More() {
+		var row models.SwiftBranch
+		assert.NoError(t, decoder.Decode(&row))
+		codes = append(codes, row.SwiftCode)
+	}
+	assert.Equal(t, []string{"AAAAPLPWXXX", "AAAAPLPWKRK", "BBBBDEFFXXX"}, codes)
+
+	w = exportRequest(service, "format=xlsx")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `attachment; filename="swift-codes.xlsx"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "PK", w.Body.String()[:2], "A workbook is a zip archive")
+}
+
+func TestExportSwiftCodes_InvalidQuery(t *testing.T) {
+	service := services.NewSwiftCodeService(testutils.Collection)
+
+	for query, code := range map[string]string{
+		"format=xml":  "INVALID_QUERY",
+		"country=POL": "COUNTRY_INVALID_ISO2",
+	} {
+		w := exportRequest(service, query)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		assert.Empty(t, w.Header().Get("Content-Disposition"), query)
+		var response models.ProblemDetails
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response), query)
+		assert.Equal(t, code, response.Code, query)
+	}
+}
@@ -0,0 +1,42 @@
+package v1
+
+import (
+	"net/http"
+	"swift-app/internal/health"
+	"swift-app/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Liveness handles GET requests to check that the process is running and serving requests.
+//
+// @Summary Liveness probe
+// @Description Reports that the process is alive. It does not check dependencies; see /readyz.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} models.HealthResponse
+// @Router /healthz [get]
+func Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, models.HealthResponse{Status: models.HealthStatusUp})
+}
+
+// Readiness handles GET requests to check whether the service can take traffic.
+//
+// Every dependency check is reported with its status and latency. The service is not ready
+// until the initial import has finished.
+//
+// @Summary Readiness probe
+// @Description Pings MongoDB, verifies its indexes, reports whether the initial import has finished and how many countries are loaded. Returns 503 while any check fails.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} models.HealthResponse
+// @Failure 503 {object} models.HealthResponse
+// @Router /readyz [get]
+func Readiness(c *gin.Context, checker *health.Checker) {
+	response := checker.Check(c.Request.Context())
+	status := http.StatusOK
+	if response.Status != models.HealthStatusReady {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, response)
+}
@@ -0,0 +1,13 @@
+package v1
+
+import (
+	"swift-app/internal/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondError attaches err to the request, so middleware such as the audit trail can see the outcome,
+// and writes it as problem details (or a legacy message response) with the status code carried by the error.
+func respondError(c *gin.Context, err error) {
+	problem.Respond(c, err)
+}
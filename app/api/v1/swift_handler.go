@@ -2,7 +2,9 @@ package v1
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
+	"swift-app/internal/audit"
 	"swift-app/internal/errors"
 	"swift-app/internal/models"
 	"swift-app/internal/services"
@@ -15,27 +17,97 @@ import (
 //
 // It returns the full details of a headquarter or branch.
 // If the code refers to a headquarter, its branches are also included.
+// With the asOf query parameter the code is reconstructed from its recorded history.
+// Responses carry an ETag; a matching If-None-Match is answered with 304 Not Modified.
 //
 // @Summary Get SWIFT code
-// @Description Returns a SWIFT code by its identifier (headquarter)
+// @Description Returns a SWIFT code by its identifier (headquarter). Requires swift:read.
 // @Tags SWIFT Codes
 // @Accept json
 // @Produce json
 // @Param swift-code path string true "SWIFT code"
+// @Param asOf query string false "Return the SWIFT code as it was at this time (RFC 3339 or YYYY-MM-DD)"
+// @Param If-None-Match header string false "ETag of a cached copy"
 // @Success 200 {object} models.SwiftCode
-// @Failure 404 {object} models.MessageResponse
+// @Success 304 {string} string "Not Modified"
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 404 {object} models.ProblemDetails
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 429 {object} models.ProblemDetails
+// @Failure 504 {object} models.ProblemDetails
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /v1/swift-codes/{swift-code} [get]
 func GetSwiftCode(c *gin.Context, swiftService *services.SwiftCodeService) {
 	swiftCode := strings.ToUpper(c.Param(utils.ParamSwiftCode))
 
-	swift, err := swiftService.GetSwiftCodeDetails(swiftCode)
+	var swift *models.SwiftCode
+	var err error
+	if asOf := c.Query(utils.QueryAsOf); asOf != "" {
+		at, parseErr := utils.ParseAsOf(asOf)
+		if parseErr != nil {
+			respondError(c, parseErr)
+			return
+		}
+		swift, err = swiftService.GetSwiftCodeDetailsAsOf(c.Request.Context(), swiftCode, at)
+	} else {
+		swift, err = swiftService.GetSwiftCodeDetails(c.Request.Context(), swiftCode)
+	}
 	if err != nil {
-		c.JSON(errors.GetStatusCode(err), models.MessageResponse{Message: err.Error()})
+		respondError(c, err)
+		return
+	}
+
+	respondCacheable(c, swiftCodeResponse(swift), swift.LastModified)
+}
+
+// LookupSwiftCodes handles POST requests to resolve many SWIFT codes in one call.
+//
+// Results are returned in request order, one per requested code. Found codes have the same
+// shape as the single-code endpoint; invalid or unknown codes do not fail the batch.
+//
+// @Summary Look up SWIFT codes in bulk
+// @Description Resolves up to 1000 SWIFT codes with one store query. Each result has status found_headquarter, found_branch, not_found or invalid. Requires swift:read.
+// @Tags SWIFT Codes
+// @Accept json
+// @Produce json
+// @Param lookup body models.LookupRequest true "SWIFT codes to resolve"
+// @Success 200 {object} models.LookupResponse
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 429 {object} models.ProblemDetails
+// @Failure 504 {object} models.ProblemDetails
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/swift-codes/lookup [post]
+func LookupSwiftCodes(c *gin.Context, swiftService *services.SwiftCodeService) {
+	var request models.LookupRequest
+	if err := bindJSON(c, &request); err != nil {
+		respondError(c, err)
 		return
 	}
 
+	results, err := swiftService.LookupSwiftCodes(c.Request.Context(), request.SwiftCodes)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	for i := range results {
+		if swift, ok := results[i].Details.(*models.SwiftCode); ok {
+			results[i].Details = swiftCodeResponse(swift)
+		}
+	}
+
+	c.JSON(http.StatusOK, models.LookupResponse{Results: results})
+}
+
+// swiftCodeResponse shapes a SWIFT code the way the single-code endpoint returns it:
+// a headquarter with its branches, or a branch without them.
+func swiftCodeResponse(swift *models.SwiftCode) interface{} {
 	if swift.IsHeadquarter {
-		c.JSON(http.StatusOK, models.SwiftCode{
+		return models.SwiftCode{
 			Address:       swift.Address,
 			BankName:      swift.BankName,
 			CountryISO2:   swift.CountryISO2,
@@ -43,39 +115,78 @@ func GetSwiftCode(c *gin.Context, swiftService *services.SwiftCodeService) {
 			IsHeadquarter: true,
 			SwiftCode:     swift.SwiftCode,
 			Branches:      swift.Branches,
-		})
-		return
+		}
 	}
 
-	c.JSON(http.StatusOK, models.SwiftBranch{
+	return models.SwiftBranch{
 		Address:       swift.Address,
 		BankName:      swift.BankName,
 		CountryISO2:   swift.CountryISO2,
 		CountryName:   swift.CountryName,
 		IsHeadquarter: false,
 		SwiftCode:     swift.SwiftCode,
-	})
+	}
+}
+
+// GetSwiftCodeHistory handles GET requests to list the recorded changes of a SWIFT code.
+//
+// Each entry carries before/after snapshots of the headquarter document. For a headquarter
+// the history also includes changes to its branches.
+//
+// @Summary Get SWIFT code history
+// @Description Returns the versioned change history of a SWIFT code. Requires swift:read.
+// @Tags SWIFT Codes
+// @Accept json
+// @Produce json
+// @Param swift-code path string true "SWIFT code"
+// @Success 200 {object} models.SwiftCodeHistoryResponse
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 404 {object} models.ProblemDetails
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 429 {object} models.ProblemDetails
+// @Failure 504 {object} models.ProblemDetails
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/swift-codes/{swift-code}/history [get]
+func GetSwiftCodeHistory(c *gin.Context, swiftService *services.SwiftCodeService) {
+	swiftCode := strings.ToUpper(c.Param(utils.ParamSwiftCode))
+	historyResponse, err := swiftService.GetSwiftCodeHistory(c.Request.Context(), swiftCode)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, historyResponse)
 }
 
 // GetSwiftCodesByCountry handles GET requests to retrieve all SWIFT codes for a given country.
 //
 // The country is identified using its ISO2 code. Both headquarters and branches are returned.
+// Responses carry an ETag; a matching If-None-Match is answered with 304 Not Modified.
 //
 // @Summary Get SWIFT codes by country
-// @Description Returns a list of SWIFT codes for a given country ISO2 code
+// @Description Returns a list of SWIFT codes for a given country ISO2 code. Requires swift:read.
 // @Tags SWIFT Codes
 // @Accept json
 // @Produce json
 // @Param countryISO2code path string true "Country ISO2 code"
+// @Param If-None-Match header string false "ETag of a cached copy"
 // @Success 200 {array} models.SwiftCode
-// @Failure 404 {object} models.MessageResponse
+// @Success 304 {string} string "Not Modified"
+// @Failure 404 {object} models.ProblemDetails
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 429 {object} models.ProblemDetails
+// @Failure 504 {object} models.ProblemDetails
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /v1/swift-codes/country/{countryISO2code} [get]
 func GetSwiftCodesByCountry(c *gin.Context, swiftService *services.SwiftCodeService) {
 	countryISO2 := strings.ToUpper(c.Param(utils.ParamCountryISO2))
 
-	swiftCodesResponse, err := swiftService.GetSwiftCodesByCountry(countryISO2)
+	swiftCodesResponse, err := swiftService.GetSwiftCodesByCountry(c.Request.Context(), countryISO2)
 	if err != nil {
-		c.JSON(errors.GetStatusCode(err), models.MessageResponse{Message: err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -85,34 +196,47 @@ func GetSwiftCodesByCountry(c *gin.Context, swiftService *services.SwiftCodeServ
 		SwiftCodes:  swiftCodesResponse.SwiftCodes,
 	}
 
-	c.JSON(http.StatusOK, response)
+	respondCacheable(c, response, swiftCodesResponse.LastModified)
 }
 
 // AddSwiftCode handles POST requests to add a new SWIFT code to the system.
 //
-// It can add both headquarters and branches. Input is validated from JSON.
+// It can add both headquarters and branches. Input is validated from JSON; unknown fields are
+// rejected and every invalid field is reported in one response.
 //
 // @Summary Add a SWIFT code
-// @Description Adds a new SWIFT code (headquarter or branch)
+// @Description Adds a new SWIFT code (headquarter or branch). Branches are added one at a time, so the body has no branches field. Requires swift:write.
 // @Tags SWIFT Codes
 // @Accept json
 // @Produce json
-// @Param swiftCode body models.SwiftCode true "SWIFT code object"
+// @Param swiftCode body models.AddSwiftCodeRequest true "SWIFT code object"
 // @Success 200 {object} models.MessageResponse
-// @Failure 400 {object} models.MessageResponse
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 409 {object} models.ProblemDetails
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 429 {object} models.ProblemDetails
+// @Failure 504 {object} models.ProblemDetails
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /v1/swift-codes/ [post]
 func AddSwiftCode(c *gin.Context, swiftService *services.SwiftCodeService) {
-	var swiftCodeRequest models.SwiftCode
-	if err := c.ShouldBindJSON(&swiftCodeRequest); err != nil {
-		c.JSON(errors.GetStatusCode(errors.ErrBadRequest), models.MessageResponse{
-			Message: "Invalid input data or JSON format",
-		})
+	var request models.AddSwiftCodeRequest
+	if err := bindJSON(c, &request); err != nil {
+		if errors.HasCode(err, errors.CodeValidationFailed) {
+			// Report failed domain checks (suffix, country) in the same response as the binding tag violations.
+			_, inputErr := utils.ValidateSwiftCodeInput(strings.ToUpper(request.SwiftCode),
+				strings.ToUpper(request.CountryISO2), strings.ToUpper(request.CountryName), request.IsHeadquarter)
+			err = errors.Collect(err, inputErr)
+		}
+		respondError(c, err)
 		return
 	}
+	audit.SetAffectedCodes(c, strings.ToUpper(request.SwiftCode))
 
-	message, err := swiftService.AddSwiftCode(&swiftCodeRequest)
+	message, err := swiftService.AddSwiftCode(c.Request.Context(), request.ToSwiftCode())
 	if err != nil {
-		c.JSON(errors.GetStatusCode(err), models.MessageResponse{Message: err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -122,25 +246,68 @@ func AddSwiftCode(c *gin.Context, swiftService *services.SwiftCodeService) {
 // DeleteSwiftCode handles DELETE requests to remove a SWIFT code from the database.
 //
 // If the provided code is a headquarter, all its branches are also removed.
+// With If-Match the code is only deleted while its current ETag matches.
+// With dryRun=true nothing is deleted and the response lists the codes that would be.
 //
 // @Summary Delete SWIFT code
-// @Description Deletes a headquarter SWIFT code and its branches or a single branch
+// @Description Deletes a headquarter SWIFT code and its branches or a single branch and lists the deleted codes. With dryRun=true only previews them. Requires swift:write.
 // @Tags SWIFT Codes
 // @Accept json
 // @Produce json
 // @Param swift-code path string true "SWIFT code"
-// @Success 200 {object} models.MessageResponse
-// @Failure 404 {object} models.MessageResponse
+// @Param dryRun query bool false "Only list the codes that would be deleted"
+// @Param If-Match header string false "ETag the client last saw"
+// @Success 200 {object} models.DeleteSwiftCodeResponse
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 404 {object} models.ProblemDetails
+// @Failure 409 {object} models.ProblemDetails
+// @Failure 412 {object} models.ProblemDetails
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 429 {object} models.ProblemDetails
+// @Failure 504 {object} models.ProblemDetails
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /v1/swift-codes/{swift-code} [delete]
 func DeleteSwiftCode(c *gin.Context, swiftService *services.SwiftCodeService) {
 	swiftCode := strings.ToUpper(c.Param(utils.ParamSwiftCode))
+	audit.SetAffectedCodes(c, swiftCode)
+
+	dryRun := false
+	if value := c.Query(utils.QueryDryRun); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			respondError(c, errors.Wrap(errors.ErrBadRequest, "invalid dryRun value %q, expected true or false", value).
+				WithCode(errors.CodeInvalidQuery))
+			return
+		}
+		dryRun = parsed
+	}
+
+	opts := services.DeleteOptions{DryRun: dryRun}
+	if c.GetHeader("If-Match") != "" {
+		opts.Precondition = func(current *models.SwiftCode) error {
+			etag, err := computeETag(swiftCodeResponse(current))
+			if err != nil {
+				return errors.Wrap(errors.ErrInternal, "error encoding SWIFT code %s", swiftCode)
+			}
+			return checkIfMatch(c, etag)
+		}
+	}
+	if !dryRun {
+		// The entry is written in the same transaction as the delete instead of by the audit middleware.
+		entry := audit.NewEntry(c)
+		entry.Status = http.StatusOK
+		entry.Outcome = models.AuditOutcomeSuccess
+		opts.Audit = &entry
+	}
 
-	message, err := swiftService.DeleteSwiftCode(swiftCode)
+	response, err := swiftService.DeleteSwiftCode(c.Request.Context(), swiftCode, opts)
 	if err != nil {
-		c.JSON(errors.GetStatusCode(err), models.MessageResponse{Message: err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, models.MessageResponse{Message: message})
-
+	audit.Exclude(c)
+	c.JSON(http.StatusOK, response)
 }
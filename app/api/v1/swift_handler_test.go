@@ -63,10 +63,12 @@ func TestGetSwiftCode_NotFound(t *testing.T) {
 	GetSwiftCode(c, service)
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
-	var response models.MessageResponse
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	var response models.ProblemDetails
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.Contains(t, response.Message, "cannot perform action with branch 'NONEXIST' because its headquarter 'NONEXISTXXX' is missing")
+	assert.Contains(t, response.Detail, "cannot perform action with branch 'NONEXIST' because its headquarter 'NONEXISTXXX' is missing")
+	assert.Equal(t, "HQ_NOT_FOUND", response.Code)
 
 }
 
@@ -111,7 +113,7 @@ func TestAddSwiftCode(t *testing.T) {
 	clearCollection()
 	service := services.NewSwiftCodeService(testutils.Collection)
 
-	swiftCode := models.SwiftCode{
+	swiftCode := models.AddSwiftCodeRequest{
 		SwiftCode:     "AAAABBB1XXX",
 		BankName:      "Test Bank",
 		CountryISO2:   "US",
@@ -0,0 +1,236 @@
+// Package cli implements the subcommands of the swift-app binary: serving the API, importing,
+// validating and exporting SWIFT data, printing statistics and migrating the database.
+// Every command accepts the configuration flags of the config package, such as --mongo.uri.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"swift-app/database"
+	"swift-app/initialization"
+	"swift-app/internal/config"
+	"swift-app/internal/logging"
+
+	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Exit codes returned by Run.
+const (
+	ExitOK      = 0
+	ExitFailure = 1
+	ExitUsage   = 2
+)
+
+// Output formats of the import, validate and stats commands.
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// command is a subcommand of the binary.
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(ctx context.Context, env *environment, args []string) error
+}
+
+var commands = []command{
+	{name: "serve", args: "[--import-on-start]", summary: "serve the HTTP API", run: runServe},
+	{name: "import", args: "[--mode merge|replace] [--dry-run] [--format text|json] [file]", summary: "import a CSV file into the database", run: runImport},
+	{name: "validate", args: "[--format text|json] file", summary: "validate a CSV file without importing it", run: runValidate},
+	{name: "export", args: "[--format csv|jsonl] [--country ISO2] [--out file]", summary: "export the stored SWIFT codes", run: runExport},
+	{name: "stats", args: "[--format text|json]", summary: "print the stored SWIFT codes per country", run: runStats},
+	{name: "migrate", args: "", summary: "create the database indexes", run: runMigrate},
+}
+
+// environment holds the running command and what it reads from and writes to.
+type environment struct {
+	command command
+	stdout  io.Writer
+	stderr  io.Writer
+	// lookupEnv reads environment variables, usually os.LookupEnv.
+	lookupEnv func(string) (string, bool)
+}
+
+// exitError ends a command with a specific exit code. Its message has already been printed.
+type exitError struct {
+	code int
+}
+
+func (e *exitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+// Run runs the command named by args[0] with the remaining arguments and returns the process exit code.
+// Variables from a .env file in the working directory are added to the environment first.
+func Run(ctx context.Context, args []string, stdout, stderr io.Writer, lookupEnv func(string) (string, bool)) int {
+	if len(args) == 0 {
+		printUsage(stderr)
+		return ExitUsage
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(stdout)
+		return ExitOK
+	}
+
+	cmd, ok := findCommand(args[0])
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n\n", args[0])
+		printUsage(stderr)
+		return ExitUsage
+	}
+	env := &environment{command: cmd, stdout: stdout, stderr: stderr, lookupEnv: lookupEnv}
+	// A missing .env file is fine: the settings can come from the environment, a config file or flags.
+	_ = godotenv.Load()
+
+	err := cmd.run(ctx, env, args[1:])
+	var exit *exitError
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, flag.ErrHelp):
+		return ExitOK
+	case errors.As(err, &exit):
+		return exit.code
+	}
+	var configErr *config.Error
+	if errors.As(err, &configErr) {
+		fmt.Fprintln(stderr, err)
+		return ExitUsage
+	}
+	fmt.Fprintf(stderr, "%s: %v\n", cmd.name, err)
+	return ExitFailure
+}
+
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: swift-app <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-9s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "swift-app <command> --help" for the flags of a command.`)
+}
+
+// flagSet creates the flag set of the running command, with the configuration flags registered.
+// Flag errors only point at --help, as the configuration flags make the full usage long.
+func (env *environment) flagSet() (*flag.FlagSet, *config.Flags) {
+	fs := flag.NewFlagSet(env.command.name, flag.ContinueOnError)
+	fs.SetOutput(env.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(env.stderr, "Run \"swift-app %s --help\" for usage.\n", env.command.name)
+	}
+	return fs, config.RegisterFlags(fs)
+}
+
+// printHelp prints the full usage of the running command.
+func (env *environment) printHelp(fs *flag.FlagSet) {
+	cmd := env.command
+	fmt.Fprintf(env.stdout, "Usage: %s\n\n%s.\n\nFlags:\n", strings.TrimSpace("swift-app "+cmd.name+" "+cmd.args), strings.ToUpper(cmd.summary[:1])+cmd.summary[1:])
+	fs.SetOutput(env.stdout)
+	fs.PrintDefaults()
+}
+
+// parse parses flags and returns the positional arguments. Unlike flag.FlagSet.Parse, flags may
+// follow positional arguments, as in "import codes.csv --dry-run". A parse error is a usage error.
+func (env *environment) parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if arg == "-h" || arg == "-help" || arg == "--h" || arg == "--help" {
+			env.printHelp(fs)
+			return nil, flag.ErrHelp
+		}
+	}
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, &exitError{code: ExitUsage}
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// usageError prints a usage problem and returns the usage exit error.
+func (env *environment) usageError(fs *flag.FlagSet, format string, a ...any) error {
+	fmt.Fprintf(env.stderr, format+"\n", a...)
+	fs.Usage()
+	return &exitError{code: ExitUsage}
+}
+
+// loadConfig loads the configuration from the parsed flags and the environment, and sets up logging
+// to logs. Commands other than serve log to stderr, so that their output on stdout stays
+// machine-readable. printed reports that --print-config printed the configuration instead.
+func (env *environment) loadConfig(flags *config.Flags, logs io.Writer) (cfg config.Config, printed bool, err error) {
+	cfg, err = config.Load(flags, env.lookupEnv)
+	if err != nil {
+		return cfg, false, err
+	}
+	if flags.PrintConfig {
+		return cfg, true, config.Write(env.stdout, cfg)
+	}
+	logger, err := logging.New(logs, cfg.Logging.Level, cfg.Logging.Format)
+	if err != nil {
+		return cfg, false, err
+	}
+	slog.SetDefault(logger)
+	return cfg, false, nil
+}
+
+// connect connects to the configured database, creating its indexes. The returned function closes
+// the connection.
+func connect(ctx context.Context, cfg config.Config, opts ...*options.ClientOptions) (func(), error) {
+	err := initialization.InitializeDatabase(ctx, cfg.Mongo.URI, cfg.Mongo.Database, cfg.Mongo.Collection, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		closeCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+		defer cancel()
+		if err := database.CloseMongoDB(closeCtx); err != nil {
+			slog.Error("failed to close database", "error", err)
+		}
+	}, nil
+}
+
+// parseFormat validates the --format flag of a command that prints text or JSON.
+func parseFormat(format string) (string, error) {
+	switch strings.ToLower(format) {
+	case formatText:
+		return formatText, nil
+	case formatJSON:
+		return formatJSON, nil
+	}
+	return "", fmt.Errorf("unknown output format %q, expected %s or %s", format, formatText, formatJSON)
+}
+
+// writeJSON prints v as indented JSON.
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,189 @@
+// cli_test.go contains unit tests for the command line: dispatching, flag parsing, exit codes and
+// the commands that run without a database.
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"swift-app/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func run(t *testing.T, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	noEnv := func(string) (string, bool) { return "", false }
+	code := Run(context.Background(), args, &stdout, &stderr, noEnv)
+	return code, stdout.String(), stderr.String()
+}
+
+func writeCSV(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "codes.csv")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestRun_Usage(t *testing.T) {
+	code, _, stderr := run(t)
+	assert.Equal(t, ExitUsage, code)
+	assert.Contains(t, stderr, "Commands:")
+
+	code, stdout, _ := run(t, "help")
+	assert.Equal(t, ExitOK, code)
+	for _, cmd := range commands {
+		assert.Contains(t, stdout, cmd.name)
+	}
+
+	code, _, stderr = run(t, "bogus")
+	assert.Equal(t, ExitUsage, code)
+	assert.Contains(t, stderr, `unknown command "bogus"`)
+
+	code, stdout, _ = run(t, "import", "--help")
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout, "Usage: swift-app import")
+	assert.Contains(t, stdout, "-dry-run")
+	assert.Contains(t, stdout, "-mongo.uri", "Every command should accept the configuration flags")
+
+	code, _, stderr = run(t, "stats", "--nope")
+	assert.Equal(t, ExitUsage, code)
+	assert.Contains(t, stderr, `Run "swift-app stats --help" for usage.`)
+}
+
+func TestRun_Validate(t *testing.T) {
+	valid := writeCSV(t, `SWIFT CODE,COUNTRY ISO2 CODE,NAME,ADDRESS,COUNTRY NAME
+AAAABBB1XXX,US,First Bank,123 First St,United States
+`)
+	code, stdout, _ := run(t, "validate", valid)
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout, "1 records, 1 valid, 0 duplicate, 0 invalid")
+
+	invalid := writeCSV(t, `SWIFT CODE,COUNTRY ISO2 CODE,NAME,ADDRESS,COUNTRY NAME
+AAAABBB1XXX,US,First Bank,123 First St,United States
+AAAABBB1123,U1,Second Bank,456 Second St,United States
+`)
+	code, stdout, _ = run(t, "validate", invalid, "--format", "json")
+	assert.Equal(t, ExitFailure, code, "Invalid records should fail the command")
+	var result validateResult
+	require.NoError(t, json.Unmarshal([]byte(stdout), &result))
+	assert.Equal(t, invalid, result.File)
+	assert.Equal(t, 1, result.Valid)
+	if assert.Len(t, result.Errors, 1) {
+		assert.Equal(t, 3, result.Errors[0].Row)
+	}
+
+	bicPlus := writeCSV(t, "BIC8\tBRANCH BIC\tINSTITUTION NAME\tCITY\tCOUNTRY NAME\tISO COUNTRY CODE\n"+
+		"TESTPLPW\tXXX\tTest Bank\tWarszawa\tPoland\tPL\n")
+	code, stdout, _ = run(t, "validate", bicPlus)
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout, "(bicplus): 1 records, 1 valid", "The format should be detected")
+
+	aliases := writeCSV(t, `SWIFT CODE,COUNTRY ISO2 CODE,NAME,ADDRESS,COUNTRY NAME
+AAAABBB1XXX,DE,First Bank,Hauptstraße 1,Deutschland
+AAAABBB2XXX,CZ,Second Bank,Václavské náměstí 1,CZECHIA
+AAAABBB3XXX,CI,Third Bank,Plateau,COTE D'IVOIRE
+`)
+	code, stdout, _ = run(t, "validate", aliases)
+	assert.Equal(t, ExitOK, code, "Aliases should not fail validation")
+	assert.Contains(t, stdout, "3 records, 3 valid, 0 duplicate, 0 invalid, 2 warnings")
+	assert.Contains(t, stdout, "country name 'DEUTSCHLAND' is the de short name of DE and is stored as 'GERMANY'")
+
+	code, _, stderr := run(t, "validate", bicPlus, "--file-format", "fixed")
+	assert.Equal(t, ExitUsage, code)
+	assert.Contains(t, stderr, "unknown import format")
+
+	profile := filepath.Join(t.TempDir(), "bank.yaml")
+	require.NoError(t, os.WriteFile(profile, []byte(`fields:
+  swiftCode:   {headers: [BIC], required: true}
+  countryISO2: {headers: [KRAJ], required: true}
+  bankName:    {headers: [NAZWA]}
+  countryName: {default: POLAND}
+`), 0o600))
+	mapped := writeCSV(t, "BIC;KRAJ;NAZWA\nTESTPLPWXXX;PL;Test Bank\n")
+	code, stdout, _ = run(t, "validate", mapped, "--profile", profile)
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout, "(csv): 1 records, 1 valid")
+
+	code, _, stderr = run(t, "validate", valid, "--profile", profile)
+	assert.Equal(t, ExitFailure, code)
+	assert.Contains(t, stderr, `missing required columns in profile bank: swiftCode (column "BIC"), countryISO2 (column "KRAJ")`)
+
+	code, _, stderr = run(t, "validate", filepath.Join(t.TempDir(), "missing.csv"))
+	assert.Equal(t, ExitFailure, code)
+	assert.Contains(t, stderr, "validate: ")
+
+	code, _, stderr = run(t, "validate")
+	assert.Equal(t, ExitUsage, code)
+	assert.Contains(t, stderr, "validate takes one file")
+}
+
+func TestRun_Config(t *testing.T) {
+	code, stdout, _ := run(t, "stats", "--print-config", "--mongo.database", "reportsDB")
+	assert.Equal(t, ExitOK, code, "Printing the configuration should not connect to the database")
+	assert.Contains(t, stdout, "database: reportsDB")
+
+	code, _, stderr := run(t, "import", "codes.csv", "--http.port", "eighty")
+	assert.Equal(t, ExitUsage, code)
+	assert.Contains(t, stderr, "invalid configuration")
+	assert.Contains(t, stderr, "http.port (flag --http.port)")
+
+	code, _, stderr = run(t, "import", "--mode", "append")
+	assert.Equal(t, ExitUsage, code)
+	assert.Contains(t, stderr, "unknown import mode")
+}
+
+func TestParse_FlagsAfterArguments(t *testing.T) {
+	cmd, _ := findCommand("import")
+	env := &environment{command: cmd, stdout: &bytes.Buffer{}, stderr: &bytes.Buffer{}}
+	fs, _ := env.flagSet()
+	dryRun := fs.Bool("dry-run", false, "")
+	mode := fs.String("mode", "", "")
+
+	positional, err := env.parse(fs, []string{"--mode", "replace", "codes.csv", "--dry-run"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"codes.csv"}, positional)
+	assert.True(t, *dryRun)
+	assert.Equal(t, "replace", *mode)
+}
+
+func TestWriteImportText(t *testing.T) {
+	var out bytes.Buffer
+	err := writeImportText(&out, importResult{
+		File:   "codes.csv",
+		Mode:   "replace",
+		DryRun: true,
+		Summary: &models.ImportSummary{
+			HQRemoved: 3, HQAdded: 2, BranchesAdded: 4, BranchesSkipped: 2, BranchesDuplicate: 1, BranchesMissingHQ: 1,
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `Dry run of importing codes.csv (mode replace), nothing was written:
+  headquarters removed  3
+  headquarters added    2
+  headquarters skipped  0
+  branches added        4
+  branches skipped      2 (1 duplicate, 1 without headquarter)
+`, out.String())
+}
+
+func TestWriteStatsText(t *testing.T) {
+	var out bytes.Buffer
+	err := writeStatsText(&out, statsResult{
+		Countries: []models.CountryTotals{
+			{CountryISO2: "DE", Headquarters: 12, Branches: 140},
+			{CountryISO2: "PL", Headquarters: 3, Branches: 7},
+		},
+		Headquarters: 15,
+		Branches:     147,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `COUNTRY  HEADQUARTERS  BRANCHES
+DE       12            140
+PL       3             7
+TOTAL    15            147
+`, out.String())
+}
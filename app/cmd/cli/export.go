@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"swift-app/database"
+	"swift-app/internal/export"
+	"swift-app/internal/utils"
+)
+
+// runExport writes every stored SWIFT code to stdout, or to the --out file. The CSV format can be
+// imported again.
+func runExport(ctx context.Context, env *environment, args []string) error {
+	fs, flags := env.flagSet()
+	format := fs.String("format", string(export.FormatCSV), "export format: csv, jsonl or xlsx")
+	country := fs.String("country", "", "only export the SWIFT codes of this ISO2 country code")
+	out := fs.String("out", "", "file to write to instead of stdout")
+	positional, err := env.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return env.usageError(fs, "export takes no arguments, got %q", positional[0])
+	}
+	exportFormat, err := export.ParseFormat(*format)
+	if err != nil {
+		return env.usageError(fs, "%v", err)
+	}
+	if *country != "" {
+		*country = strings.ToUpper(*country)
+		if err := utils.ValidateCountryISO2(*country); err != nil {
+			return env.usageError(fs, "invalid --country: %v", err)
+		}
+	}
+	cfg, printed, err := env.loadConfig(flags, env.stderr)
+	if err != nil || printed {
+		return err
+	}
+
+	disconnect, err := connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer disconnect()
+
+	if *out == "" {
+		return writeExport(ctx, env.stdout, exportFormat, *country)
+	}
+	file, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := writeExport(ctx, file, exportFormat, *country); err != nil {
+		file.Close()
+		return err
+	}
+	// A failed final write may only show up when closing.
+	return file.Close()
+}
+
+func writeExport(ctx context.Context, w io.Writer, format export.Format, country string) error {
+	written, err := export.Write(ctx, database.GetCollection(), w, format, export.Filter{CountryISO2: country})
+	if err != nil {
+		return fmt.Errorf("export failed after %d SWIFT codes: %w", written, err)
+	}
+	slog.Info("export complete", slog.Int("swift_codes", written), slog.String("format", string(format)))
+	return nil
+}
@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"swift-app/database"
+	"swift-app/initialization"
+	"swift-app/internal/models"
+	"swift-app/internal/services"
+	parser "swift-app/pkg/csv"
+	"swift-app/pkg/importer"
+	"text/tabwriter"
+)
+
+// importResult is the JSON output of the import command.
+type importResult struct {
+	File    string                `json:"file"`
+	Mode    string                `json:"mode"`
+	DryRun  bool                  `json:"dryRun"`
+	Summary *models.ImportSummary `json:"summary"`
+}
+
+// runImport imports a CSV or BIC directory file, import.csvPath when none is given, and prints the import summary.
+// A running server keeps serving cached lookups until they expire after cache.ttl (30s by default).
+func runImport(ctx context.Context, env *environment, args []string) error {
+	fs, flags := env.flagSet()
+	mode := fs.String("mode", initialization.ModeMerge, "merge keeps stored codes and adds new ones; replace swaps every stored code for the imported ones")
+	dryRun := fs.Bool("dry-run", false, "validate the file and print what the import would do, without writing anything")
+	format := fs.String("format", formatText, "output format: text or json")
+	fileFormat := fileFormatFlag(fs)
+	profilePath := profileFlag(fs)
+	positional, err := env.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 1 {
+		return env.usageError(fs, "import takes one file, got %d arguments", len(positional))
+	}
+	if _, err := initialization.ParseMode(*mode); err != nil {
+		return env.usageError(fs, "%v", err)
+	}
+	inputFormat, err := importer.ParseFormat(*fileFormat)
+	if err != nil {
+		return env.usageError(fs, "%v", err)
+	}
+	output, err := parseFormat(*format)
+	if err != nil {
+		return env.usageError(fs, "%v", err)
+	}
+	cfg, printed, err := env.loadConfig(flags, env.stderr)
+	if err != nil || printed {
+		return err
+	}
+	file := cfg.Import.CSVPath
+	if len(positional) == 1 {
+		file = positional[0]
+	}
+	profile, err := loadProfile(*profilePath, cfg.Import.Profile)
+	if err != nil {
+		return err
+	}
+
+	disconnect, err := connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer disconnect()
+	// Records are validated against the country data with the overrides made through the API.
+	if err := services.NewCountryService(services.CountryCollectionFor(database.GetCollection())).LoadOverrides(ctx); err != nil {
+		return err
+	}
+
+	summary, err := initialization.ImportFile(ctx, file, initialization.ImportOptions{Mode: *mode, DryRun: *dryRun, Format: inputFormat, Profile: profile})
+	if err != nil {
+		return err
+	}
+	result := importResult{File: file, Mode: *mode, DryRun: *dryRun, Summary: summary}
+	if output == formatJSON {
+		return writeJSON(env.stdout, result)
+	}
+	return writeImportText(env.stdout, result)
+}
+
+func writeImportText(w io.Writer, result importResult) error {
+	if result.DryRun {
+		fmt.Fprintf(w, "Dry run of importing %s (mode %s), nothing was written:\n", result.File, result.Mode)
+	} else {
+		fmt.Fprintf(w, "Imported %s (mode %s):\n", result.File, result.Mode)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	s := result.Summary
+	if result.Mode == initialization.ModeReplace {
+		fmt.Fprintf(tw, "  headquarters removed\t%d\n", s.HQRemoved)
+	}
+	fmt.Fprintf(tw, "  headquarters added\t%d\n", s.HQAdded)
+	fmt.Fprintf(tw, "  headquarters skipped\t%d\n", s.HQSkipped)
+	fmt.Fprintf(tw, "  branches added\t%d\n", s.BranchesAdded)
+	fmt.Fprintf(tw, "  branches skipped\t%d (%d duplicate, %d without headquarter)\n", s.BranchesSkipped, s.BranchesDuplicate, s.BranchesMissingHQ)
+	return tw.Flush()
+}
+
+// fileFormatFlag defines the --file-format flag selecting the format of the imported file.
+func fileFormatFlag(fs *flag.FlagSet) *string {
+	names := make([]string, 0, len(importer.Formats()))
+	for _, format := range importer.Formats() {
+		names = append(names, string(format))
+	}
+	return fs.String("file-format", string(importer.FormatAuto), "format of the file: "+strings.Join(names, ", ")+"; auto detects it")
+}
+
+// profileFlag defines the --profile flag selecting the column mapping profile of a CSV file.
+func profileFlag(fs *flag.FlagSet) *string {
+	return fs.String("profile", "", "YAML or JSON profile mapping the columns of a CSV file (default import.profile)")
+}
+
+// loadProfile loads the profile at path, or at configured when path is empty. It returns nil when
+// neither is set, so the default layout is read.
+func loadProfile(path, configured string) (*parser.Profile, error) {
+	if path == "" {
+		path = configured
+	}
+	if path == "" {
+		return nil, nil
+	}
+	return parser.LoadProfile(path)
+}
+
+// validateResult is the JSON output of the validate command.
+type validateResult struct {
+	File       string          `json:"file"`
+	FileFormat importer.Format `json:"fileFormat"`
+	*parser.Report
+}
+
+// runValidate parses and validates a CSV or BIC directory file without connecting to the database.
+// It fails when any record is invalid, so it can guard files in CI; warnings do not fail it.
+func runValidate(_ context.Context, env *environment, args []string) error {
+	fs, flags := env.flagSet()
+	format := fs.String("format", formatText, "output format: text or json")
+	fileFormat := fileFormatFlag(fs)
+	profilePath := profileFlag(fs)
+	positional, err := env.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return env.usageError(fs, "validate takes one file")
+	}
+	output, err := parseFormat(*format)
+	if err != nil {
+		return env.usageError(fs, "%v", err)
+	}
+	inputFormat, err := importer.ParseFormat(*fileFormat)
+	if err != nil {
+		return env.usageError(fs, "%v", err)
+	}
+	cfg, printed, err := env.loadConfig(flags, env.stderr)
+	if err != nil || printed {
+		return err
+	}
+	profile, err := loadProfile(*profilePath, cfg.Import.Profile)
+	if err != nil {
+		return err
+	}
+
+	report, detected, err := importer.ValidateFile(positional[0], importer.Options{Format: inputFormat, Profile: profile})
+	if err != nil {
+		return err
+	}
+	result := validateResult{File: positional[0], FileFormat: detected, Report: report}
+	if output == formatJSON {
+		err = writeJSON(env.stdout, result)
+	} else {
+		err = writeValidateText(env.stdout, result)
+	}
+	if err != nil {
+		return err
+	}
+	if len(report.Errors) > 0 {
+		return &exitError{code: ExitFailure}
+	}
+	return nil
+}
+
+func writeValidateText(w io.Writer, result validateResult) error {
+	fmt.Fprintf(w, "%s (%s): %d records, %d valid, %d duplicate, %d invalid, %d warnings\n",
+		result.File, result.FileFormat, result.Records, result.Valid, result.Duplicates, len(result.Errors), len(result.Warnings))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, recordErr := range result.Errors {
+		fmt.Fprintf(tw, "  row %d\t%s\t%s\n", recordErr.Row, recordErr.SwiftCode, recordErr.Message)
+	}
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(tw, "  row %d\t%s\twarning: %s\n", warning.Row, warning.SwiftCode, warning.Message)
+	}
+	return tw.Flush()
+}
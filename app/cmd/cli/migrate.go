@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"swift-app/database"
+)
+
+// runMigrate creates the indexes of the SWIFT collection and of the collections next to it, so that
+// they exist before the first server starts, and seeds history for codes stored without any.
+// It is safe to run repeatedly.
+func runMigrate(ctx context.Context, env *environment, args []string) error {
+	fs, flags := env.flagSet()
+	positional, err := env.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return env.usageError(fs, "migrate takes no arguments, got %q", positional[0])
+	}
+	cfg, printed, err := env.loadConfig(flags, env.stderr)
+	if err != nil || printed {
+		return err
+	}
+
+	disconnect, err := connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer disconnect()
+
+	if err := database.EnsureIndexes(ctx); err != nil {
+		return err
+	}
+	count, err := database.CheckIndexes(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(env.stdout, "Indexes are up to date: %s.%s has %d indexes, and its history, audit, API key and quota collections are indexed.\n",
+		cfg.Mongo.Database, cfg.Mongo.Collection, count)
+
+	seeded, err := database.SeedHistory(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(env.stdout, "History seeded for %d headquarters stored without any.\n", seeded)
+	return nil
+}
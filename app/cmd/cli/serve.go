@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"swift-app/cmd/server"
+	"swift-app/database"
+	"swift-app/initialization"
+	"swift-app/internal/metrics"
+	"swift-app/internal/models"
+	"swift-app/internal/tracing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// runServe serves the API until ctx is cancelled. With --import-on-start the configured CSV file is
+// imported once the server is listening, and /readyz fails until the import has finished.
+func runServe(ctx context.Context, env *environment, args []string) error {
+	fs, flags := env.flagSet()
+	importOnStart := fs.Bool("import-on-start", false, "import import.csvPath once the server is listening")
+	positional, err := env.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return env.usageError(fs, "serve takes no arguments, got %q", positional[0])
+	}
+	// The server logs to stdout, where container runtimes collect them.
+	cfg, printed, err := env.loadConfig(flags, env.stdout)
+	if err != nil || printed {
+		return err
+	}
+	// A broken profile fails the start rather than the import in the background.
+	profile, err := loadProfile("", cfg.Import.Profile)
+	if err != nil {
+		return err
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    cfg.Tracing.Exporter,
+		ServiceName: cfg.Tracing.ServiceName,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to configure tracing: %w", err)
+	}
+	defer func() {
+		// Flushed after the database is closed, so spans recorded while closing are exported too.
+		flushCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			slog.Error("failed to flush traces", "error", err)
+		}
+	}()
+
+	appMetrics := metrics.New(prometheus.NewRegistry())
+	disconnect, err := connect(context.Background(), cfg,
+		options.Client().SetMonitor(database.CommandMonitors(appMetrics.CommandMonitor(), tracing.CommandMonitor())))
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer disconnect()
+
+	var importData server.ImportFunc
+	if *importOnStart {
+		importData = func(ctx context.Context) (*models.ImportSummary, error) {
+			return initialization.ImportFile(ctx, cfg.Import.CSVPath, initialization.ImportOptions{Profile: profile})
+		}
+	}
+	return server.StartServer(ctx, cfg, appMetrics, importData)
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"swift-app/database"
+	"swift-app/internal/models"
+	"swift-app/internal/services"
+	"text/tabwriter"
+)
+
+// statsResult is the JSON output of the stats command.
+type statsResult struct {
+	Countries    []models.CountryTotals `json:"countries"`
+	Headquarters int                    `json:"headquarters"`
+	Branches     int                    `json:"branches"`
+}
+
+// runStats prints the number of stored headquarters and branches of every country.
+func runStats(ctx context.Context, env *environment, args []string) error {
+	fs, flags := env.flagSet()
+	format := fs.String("format", formatText, "output format: text or json")
+	positional, err := env.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return env.usageError(fs, "stats takes no arguments, got %q", positional[0])
+	}
+	output, err := parseFormat(*format)
+	if err != nil {
+		return env.usageError(fs, "%v", err)
+	}
+	cfg, printed, err := env.loadConfig(flags, env.stderr)
+	if err != nil || printed {
+		return err
+	}
+
+	disconnect, err := connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer disconnect()
+
+	totals, err := services.NewSwiftCodeService(database.GetCollection()).CountryTotals(ctx)
+	if err != nil {
+		return err
+	}
+	result := statsResult{Countries: totals}
+	for _, country := range totals {
+		result.Headquarters += country.Headquarters
+		result.Branches += country.Branches
+	}
+	if output == formatJSON {
+		return writeJSON(env.stdout, result)
+	}
+	return writeStatsText(env.stdout, result)
+}
+
+func writeStatsText(w io.Writer, result statsResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "COUNTRY\tHEADQUARTERS\tBRANCHES")
+	for _, country := range result.Countries {
+		fmt.Fprintf(tw, "%s\t%d\t%d\n", country.CountryISO2, country.Headquarters, country.Branches)
+	}
+	fmt.Fprintf(tw, "TOTAL\t%d\t%d\n", result.Headquarters, result.Branches)
+	return tw.Flush()
+}
@@ -5,35 +5,182 @@ package router
 
 import (
 	v1 "swift-app/api/v1"
+	"swift-app/internal/audit"
+	"swift-app/internal/auth"
 	"swift-app/internal/errors"
-	"swift-app/internal/models"
+	"swift-app/internal/health"
+	"swift-app/internal/logging"
+	"swift-app/internal/metrics"
+	"swift-app/internal/problem"
+	"swift-app/internal/ratelimit"
+	"swift-app/internal/requestid"
 	"swift-app/internal/services"
+	"swift-app/internal/tracing"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRoutes(r *gin.Engine, swiftService *services.SwiftCodeService) {
-	api := r.Group("/v1/swift-codes")
+// Option customizes the routes registered by SetupRoutes.
+type Option func(*routeOptions)
+
+type routeOptions struct {
+	guard       *auth.Guard
+	limiter     *ratelimit.Limiter
+	errorFormat problem.Format
+	metrics     *metrics.Metrics
+	checker     *health.Checker
+}
+
+// WithAuth protects the API routes with the given guard. Without it the routes are left open,
+// which is only intended for tests and explicitly unauthenticated setups.
+func WithAuth(guard *auth.Guard) Option {
+	return func(o *routeOptions) {
+		o.guard = guard
+	}
+}
+
+// WithRateLimit throttles API clients with the given limiter. Without it requests are not limited.
+func WithRateLimit(limiter *ratelimit.Limiter) Option {
+	return func(o *routeOptions) {
+		o.limiter = limiter
+	}
+}
+
+// WithErrorFormat selects how errors are written. Without it errors are RFC 7807 problem details.
+func WithErrorFormat(format problem.Format) Option {
+	return func(o *routeOptions) {
+		o.errorFormat = format
+	}
+}
+
+// WithMetrics records request metrics and serves them at /metrics, outside the authenticated API.
+func WithMetrics(m *metrics.Metrics) Option {
+	return func(o *routeOptions) {
+		o.metrics = m
+	}
+}
+
+// WithHealth reports the given readiness checks at /readyz. Without it /readyz has no checks and is always ready.
+func WithHealth(checker *health.Checker) Option {
+	return func(o *routeOptions) {
+		o.checker = checker
+	}
+}
+
+func SetupRoutes(r *gin.Engine, swiftService *services.SwiftCodeService, opts ...Option) {
+	options := routeOptions{
+		guard:       auth.NewGuard(),
+		limiter:     ratelimit.New(ratelimit.Config{}, nil),
+		errorFormat: problem.FormatProblem,
+		checker:     health.NewChecker(health.DefaultTimeout),
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	guard := options.guard
+	limiter := options.limiter
+
+	auditStore := audit.NewStore(audit.CollectionFor(swiftService.DB))
+	keyStore := auth.NewKeyStore(auth.CollectionFor(swiftService.DB))
+	countryService := services.NewCountryService(services.CountryCollectionFor(swiftService.DB))
+
+	r.Use(requestid.Middleware(), tracing.Middleware(), logging.Middleware(), problem.Middleware(options.errorFormat))
+	if options.metrics != nil {
+		r.Use(options.metrics.Middleware())
+		r.GET("/metrics", gin.WrapH(options.metrics.Handler()))
+	}
+
+	// Probes are answered without credentials or rate limits, like /metrics.
+	r.GET("/healthz", v1.Liveness)
+	r.GET("/readyz", func(c *gin.Context) {
+		v1.Readiness(c, options.checker)
+	})
+
+	// The per-IP limit runs before authentication so that failed logins are throttled as well;
+	// the per-client limits of each route run once the caller is known.
+	v1Group := r.Group("/v1")
+	v1Group.Use(audit.Middleware(auditStore), limiter.LimitIP(), guard.Authenticate())
+
+	api := v1Group.Group("/swift-codes")
 	{
-		api.GET("/:swift-code", func(c *gin.Context) {
+		api.GET("/:swift-code", guard.Require(auth.ScopeRead), limiter.Limit(ratelimit.ClassRead), func(c *gin.Context) {
 			v1.GetSwiftCode(c, swiftService)
 		})
 
-		api.GET("/country/:countryISO2code", func(c *gin.Context) {
+		api.GET("/:swift-code/history", guard.Require(auth.ScopeRead), limiter.Limit(ratelimit.ClassRead), func(c *gin.Context) {
+			v1.GetSwiftCodeHistory(c, swiftService)
+		})
+
+		api.GET("/country/:countryISO2code", guard.Require(auth.ScopeRead), limiter.Limit(ratelimit.ClassRead), func(c *gin.Context) {
 			v1.GetSwiftCodesByCountry(c, swiftService)
 		})
 
-		api.POST("/", func(c *gin.Context) {
+		api.POST("/lookup", audit.Skip(), guard.Require(auth.ScopeRead), limiter.Limit(ratelimit.ClassRead), func(c *gin.Context) {
+			v1.LookupSwiftCodes(c, swiftService)
+		})
+
+		api.POST("/", guard.Require(auth.ScopeWrite), limiter.Limit(ratelimit.ClassWrite), func(c *gin.Context) {
 			v1.AddSwiftCode(c, swiftService)
 		})
 
-		api.DELETE("/:swift-code", func(c *gin.Context) {
+		api.DELETE("/:swift-code", guard.Require(auth.ScopeWrite), limiter.Limit(ratelimit.ClassWrite), func(c *gin.Context) {
 			v1.DeleteSwiftCode(c, swiftService)
 		})
 	}
 
+	// Exports share the import limit: each one reads the whole collection.
+	v1Group.GET("/export", guard.Require(auth.ScopeRead), limiter.Limit(ratelimit.ClassImport), func(c *gin.Context) {
+		v1.ExportSwiftCodes(c, swiftService)
+	})
+
+	countries := v1Group.Group("/countries", guard.Require(auth.ScopeRead), limiter.Limit(ratelimit.ClassRead))
+	{
+		countries.GET("", func(c *gin.Context) {
+			v1.ListCountries(c, countryService)
+		})
+
+		countries.GET("/:iso2", func(c *gin.Context) {
+			v1.GetCountry(c, countryService)
+		})
+	}
+
+	v1Group.GET("/audit", guard.Require(auth.ScopeAdmin), limiter.Limit(ratelimit.ClassRead), func(c *gin.Context) {
+		v1.ListAuditEntries(c, auditStore)
+	})
+
+	admin := v1Group.Group("/admin", guard.Require(auth.ScopeAdmin), limiter.Limit(ratelimit.ClassWrite))
+	{
+		admin.POST("/api-keys", func(c *gin.Context) {
+			v1.CreateAPIKey(c, keyStore)
+		})
+
+		admin.GET("/api-keys", func(c *gin.Context) {
+			v1.ListAPIKeys(c, keyStore)
+		})
+
+		admin.POST("/api-keys/:id/rotate", func(c *gin.Context) {
+			v1.RotateAPIKey(c, keyStore)
+		})
+
+		admin.DELETE("/api-keys/:id", func(c *gin.Context) {
+			v1.RevokeAPIKey(c, keyStore)
+		})
+
+		admin.GET("/cache", func(c *gin.Context) {
+			v1.GetCacheStats(c, swiftService)
+		})
+
+		admin.PUT("/countries/:iso2", func(c *gin.Context) {
+			v1.OverrideCountry(c, countryService)
+		})
+
+		admin.DELETE("/countries/:iso2", func(c *gin.Context) {
+			v1.ResetCountry(c, countryService)
+		})
+	}
+
 	r.NoRoute(func(c *gin.Context) {
-		err := errors.Wrap(errors.ErrNotFound, "endpoint not found: %s. Please try again", c.Request.URL.Path)
-		c.JSON(errors.GetStatusCode(err), models.MessageResponse{Message: err.Error()})
+		problem.Respond(c, errors.Wrap(errors.ErrNotFound, "endpoint not found: %s. Please try again", c.Request.URL.Path).
+			WithCode(errors.CodeNoRoute))
 	})
 }
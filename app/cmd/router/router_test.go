@@ -11,11 +11,17 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"swift-app/internal/auth"
+	"swift-app/internal/health"
+	"swift-app/internal/metrics"
 	"swift-app/internal/models"
+	"swift-app/internal/problem"
+	"swift-app/internal/ratelimit"
 	"swift-app/internal/services"
 	testutils "swift-app/internal/testutils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson"
 )
@@ -64,10 +70,12 @@ func TestGetSwiftCode_NotFound(t *testing.T) {
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
 
-	var response models.MessageResponse
+	var response models.ProblemDetails
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.Contains(t, response.Message, "headquarter not found: NONEXISTXXX")
+	assert.Contains(t, response.Detail, "headquarter not found: NONEXISTXXX")
+	assert.Equal(t, "HQ_NOT_FOUND", response.Code)
+	assert.Equal(t, "/v1/swift-codes/NONEXISTXXX", response.Instance)
 }
 
 func TestAddSwiftCode(t *testing.T) {
@@ -75,7 +83,7 @@ func TestAddSwiftCode(t *testing.T) {
 
 	r := setupRouter()
 
-	swiftCode := models.SwiftCode{
+	swiftCode := models.AddSwiftCodeRequest{
 		SwiftCode:     "AAAABBB1XXX",
 		BankName:      "Test Bank",
 		CountryISO2:   "US",
@@ -122,3 +130,614 @@ func TestDeleteSwiftCode(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "deleted hadquarter XYZBANK1XXX and its branches", response.Message)
 }
+
+func TestDeleteSwiftCode_DryRun(t *testing.T) {
+	_, _ = testutils.Collection.DeleteMany(context.Background(), bson.M{})
+	auditCollection := testutils.Collection.Database().Collection(testutils.Collection.Name() + "_audit")
+	_, _ = auditCollection.DeleteMany(context.Background(), bson.M{})
+
+	_, err := testutils.Collection.InsertOne(context.Background(), bson.M{
+		"swiftCode":     "DRYRPLPWXXX",
+		"bankName":      "Dry Bank",
+		"countryISO2":   "PL",
+		"countryName":   "POLAND",
+		"isHeadquarter": true,
+		"branches": []bson.M{
+			{"swiftCode": "DRYRPLPW001", "bankName": "Dry Branch", "countryISO2": "PL", "isHeadquarter": false},
+		},
+	})
+	assert.NoError(t, err)
+
+	r := setupRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/v1/swift-codes/DRYRPLPWXXX?dryRun=maybe", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("DELETE", "/v1/swift-codes/DRYRPLPWXXX?dryRun=true", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var preview models.DeleteSwiftCodeResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &preview))
+	assert.True(t, preview.DryRun)
+	assert.Equal(t, []string{"DRYRPLPWXXX", "DRYRPLPW001"}, preview.DeletedCodes)
+
+	count, err := testutils.Collection.CountDocuments(context.Background(), bson.M{"swiftCode": "DRYRPLPWXXX"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count, "a dry run must not delete anything")
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("DELETE", "/v1/swift-codes/DRYRPLPWXXX", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.DeleteSwiftCodeResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.False(t, response.DryRun)
+	assert.Equal(t, preview.DeletedCodes, response.DeletedCodes)
+
+	var entries []models.AuditEntry
+	cursor, err := auditCollection.Find(context.Background(), bson.M{"outcome": models.AuditOutcomeSuccess})
+	assert.NoError(t, err)
+	assert.NoError(t, cursor.All(context.Background(), &entries))
+	assert.Len(t, entries, 1, "only the real delete is audited, exactly once")
+	if len(entries) == 1 {
+		assert.Equal(t, response.DeletedCodes, entries[0].SwiftCodes)
+	}
+}
+
+func TestLookupSwiftCodes(t *testing.T) {
+	_, _ = testutils.Collection.DeleteMany(context.Background(), bson.M{})
+
+	_, err := testutils.Collection.InsertOne(context.Background(), bson.M{
+		"swiftCode":     "AAAABBB1XXX",
+		"bankName":      "Test Bank",
+		"address":       "123 Test St",
+		"countryISO2":   "US",
+		"countryName":   "UNITED STATES",
+		"isHeadquarter": true,
+		"branches": []bson.M{
+			{"swiftCode": "AAAABBB1001", "bankName": "Test Bank Branch", "address": "1 Branch St", "countryISO2": "US", "isHeadquarter": false},
+		},
+	})
+	assert.NoError(t, err)
+
+	r := setupRouter()
+	w := httptest.NewRecorder()
+	body := `{"swiftCodes": ["AAAABBB1001", "AAAABBB1XXX", "NOPE", "ZZZZYYY1XXX"]}`
+	req, _ := http.NewRequest("POST", "/v1/swift-codes/lookup", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Results []struct {
+			SwiftCode string                 `json:"swiftCode"`
+			Status    string                 `json:"status"`
+			Details   map[string]interface{} `json:"details"`
+		} `json:"results"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Results, 4)
+	assert.Equal(t, models.LookupStatusBranch, response.Results[0].Status)
+	assert.Equal(t, "UNITED STATES", response.Results[0].Details["countryName"])
+	assert.NotContains(t, response.Results[0].Details, "branches")
+	assert.Equal(t, models.LookupStatusHeadquarter, response.Results[1].Status)
+	assert.Contains(t, response.Results[1].Details, "branches")
+	assert.Equal(t, models.LookupStatusInvalid, response.Results[2].Status)
+	assert.Equal(t, models.LookupStatusNotFound, response.Results[3].Status)
+	assert.Nil(t, response.Results[3].Details)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/v1/swift-codes/lookup", bytes.NewBufferString(`{"swiftCodes": []}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCacheStats(t *testing.T) {
+	_, _ = testutils.Collection.DeleteMany(context.Background(), bson.M{})
+
+	_, err := testutils.Collection.InsertOne(context.Background(), bson.M{
+		"swiftCode":     "AAAABBB1XXX",
+		"bankName":      "Test Bank",
+		"address":       "123 Test St",
+		"countryISO2":   "US",
+		"countryName":   "UNITED STATES",
+		"isHeadquarter": true,
+	})
+	assert.NoError(t, err)
+
+	r := setupRouter()
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/v1/swift-codes/AAAABBB1XXX", nil)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/admin/cache", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var stats models.CacheStats
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+	assert.True(t, stats.Enabled)
+	assert.Equal(t, int64(1), stats.Hits)
+	assert.Equal(t, int64(1), stats.Misses)
+}
+
+func TestConditionalRequests(t *testing.T) {
+	_, _ = testutils.Collection.DeleteMany(context.Background(), bson.M{})
+
+	r := setupRouter()
+	jsonData, _ := json.Marshal(models.AddSwiftCodeRequest{
+		SwiftCode:     "ETAGPLPWXXX",
+		BankName:      "ETag Bank",
+		CountryISO2:   "PL",
+		CountryName:   "POLAND",
+		Address:       "1 ETag St",
+		IsHeadquarter: true,
+	})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/v1/swift-codes/", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/v1/swift-codes/country/PL", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	countryETag := w.Header().Get("ETag")
+	assert.NotEmpty(t, countryETag)
+	assert.NotEmpty(t, w.Header().Get("Last-Modified"))
+	assert.Equal(t, "private, no-cache", w.Header().Get("Cache-Control"))
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/v1/swift-codes/country/PL", nil)
+	req.Header.Set("If-None-Match", countryETag)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/v1/swift-codes/ETAGPLPWXXX", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	codeETag := w.Header().Get("ETag")
+
+	jsonData, _ = json.Marshal(models.AddSwiftCodeRequest{
+		SwiftCode:   "ETAGPLPW001",
+		BankName:    "ETag Bank Branch",
+		CountryISO2: "PL",
+		CountryName: "POLAND",
+		Address:     "2 ETag St",
+	})
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/v1/swift-codes/", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/v1/swift-codes/country/PL", nil)
+	req.Header.Set("If-None-Match", countryETag)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, "adding a branch should change the country ETag")
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("DELETE", "/v1/swift-codes/ETAGPLPWXXX", nil)
+	req.Header.Set("If-Match", codeETag)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code, "the headquarter changed since its ETag was read")
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/v1/swift-codes/ETAGPLPWXXX", nil)
+	r.ServeHTTP(w, req)
+	codeETag = w.Header().Get("ETag")
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("DELETE", "/v1/swift-codes/ETAGPLPWXXX", nil)
+	req.Header.Set("If-Match", codeETag)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestGetSwiftCodeHistoryAndAsOf(t *testing.T) {
+	_, _ = testutils.Collection.DeleteMany(context.Background(), bson.M{})
+
+	r := setupRouter()
+
+	swiftCode := models.AddSwiftCodeRequest{
+		SwiftCode:     "HISTUSNYXXX",
+		BankName:      "History Bank",
+		CountryISO2:   "US",
+		CountryName:   "United States",
+		Address:       "1 Wall St",
+		IsHeadquarter: true,
+	}
+	jsonData, _ := json.Marshal(swiftCode)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/v1/swift-codes/", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/v1/swift-codes/HISTUSNYXXX/history", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var historyResponse models.SwiftCodeHistoryResponse
+	err := json.Unmarshal(w.Body.Bytes(), &historyResponse)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, historyResponse.Entries)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/v1/swift-codes/HISTUSNYXXX?asOf=2000-01-31", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/v1/swift-codes/HISTUSNYXXX?asOf=yesterday", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAuditLog(t *testing.T) {
+	_, _ = testutils.Collection.DeleteMany(context.Background(), bson.M{})
+	_, _ = testutils.Collection.Database().Collection(testutils.Collection.Name()+"_audit").DeleteMany(context.Background(), bson.M{})
+
+	r := setupRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/v1/swift-codes/AUDTBANKXXX", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/v1/audit?actor=anonymous", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.AuditEntriesResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response.Entries, 1)
+	assert.Equal(t, []string{"AUDTBANKXXX"}, response.Entries[0].SwiftCodes)
+	assert.Equal(t, models.AuditOutcomeFailure, response.Entries[0].Outcome)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/v1/audit?format=jsonl", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/v1/audit?from=2026-02-01&to=2026-01-01", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRoutesRequireScopes(t *testing.T) {
+	_, _ = testutils.Collection.DeleteMany(context.Background(), bson.M{})
+
+	keys, err := auth.ParseStaticKeys("reader|read-key|swift:read;writer|write-key|swift:write")
+	assert.NoError(t, err)
+
+	r := gin.New()
+	SetupRoutes(r, services.NewSwiftCodeService(testutils.Collection), WithAuth(auth.NewGuard(auth.NewStaticKeys(keys))))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/v1/swift-codes/XYZBANK1XXX", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("DELETE", "/v1/swift-codes/XYZBANK1XXX", nil)
+	req.Header.Set(auth.APIKeyHeader, "read-key")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("DELETE", "/v1/swift-codes/XYZBANK1XXX", nil)
+	req.Header.Set(auth.APIKeyHeader, "write-key")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code, "an authorized delete should reach the handler")
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/v1/audit", nil)
+	req.Header.Set(auth.APIKeyHeader, "write-key")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code, "the audit log requires swift:admin")
+}
+
+func TestRateLimit(t *testing.T) {
+	_, _ = testutils.Collection.DeleteMany(context.Background(), bson.M{})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	limiter := ratelimit.New(ratelimit.Config{Read: ratelimit.Limit{PerMinute: 2}}, nil)
+	SetupRoutes(r, services.NewSwiftCodeService(testutils.Collection), WithRateLimit(limiter))
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/v1/swift-codes/AAAABBB1XXX", nil)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "2", w.Header().Get(ratelimit.HeaderLimit))
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/swift-codes/AAAABBB1XXX", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	var response models.ProblemDetails
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Contains(t, response.Detail, "rate limit exceeded")
+	assert.Equal(t, "RATE_LIMITED", response.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/v1/swift-codes/", bytes.NewBufferString("{}"))
+	r.ServeHTTP(w, req)
+	assert.NotEqual(t, http.StatusTooManyRequests, w.Code)
+}
+
+func TestAPIKeyManagement(t *testing.T) {
+	_, _ = auth.CollectionFor(testutils.Collection).DeleteMany(context.Background(), bson.M{})
+
+	keys, err := auth.ParseStaticKeys("admin|admin-key|swift:admin")
+	assert.NoError(t, err)
+	swiftService := services.NewSwiftCodeService(testutils.Collection)
+	guard := auth.NewGuard(auth.NewStaticKeys(keys), auth.NewKeyStore(auth.CollectionFor(testutils.Collection)))
+
+	r := gin.New()
+	SetupRoutes(r, swiftService, WithAuth(guard))
+
+	body, _ := json.Marshal(models.CreateAPIKeyRequest{Owner: "partner-a", Scopes: []string{auth.ScopeRead}})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/v1/admin/api-keys", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(auth.APIKeyHeader, "admin-key")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var created models.APIKeyCreatedResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.NotEmpty(t, created.Key)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/v1/admin/api-keys", nil)
+	req.Header.Set(auth.APIKeyHeader, created.Key)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code, "a read-only key must not manage keys")
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/v1/admin/api-keys", nil)
+	req.Header.Set(auth.APIKeyHeader, "admin-key")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), created.Key, "listing must never expose keys")
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("DELETE", "/v1/admin/api-keys/"+created.ID, nil)
+	req.Header.Set(auth.APIKeyHeader, "admin-key")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/v1/swift-codes/AAAABBB1XXX", nil)
+	req.Header.Set(auth.APIKeyHeader, created.Key)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "a revoked key must be rejected")
+}
+
+func TestErrorFormats(t *testing.T) {
+	r := setupRouter()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/v1/swift-codes", bytes.NewBufferString(`{"swiftCode":"ABCDEFGHIJ","bankName":"Bank","countryISO2":"PL","countryName":"POLAND","isHeadquarter":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+	var details models.ProblemDetails
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &details))
+	assert.Equal(t, "SWIFT_INVALID_LENGTH", details.Code)
+	assert.Equal(t, []string{"/swiftCode"}, details.Fields)
+	assert.Equal(t, http.StatusBadRequest, details.Status)
+	assert.Equal(t, "urn:swift-app:problem:swift-invalid-length", details.Type)
+	assert.NotEmpty(t, details.RequestID)
+
+	legacy := gin.New()
+	SetupRoutes(legacy, services.NewSwiftCodeService(testutils.Collection), WithErrorFormat(problem.FormatLegacy))
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/v1/unknown", nil)
+	legacy.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	var message map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &message))
+	assert.Equal(t, map[string]interface{}{"message": "endpoint not found: /v1/unknown. Please try again"}, message)
+}
+
+func TestAddSwiftCode_ValidationErrors(t *testing.T) {
+	r := setupRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/v1/swift-codes/", bytes.NewBufferString(
+		`{"swiftCode":"AAAABBB1XXX","countryISO2":"PL","countryName":"GERMANY","isHeadquarter":false}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var details models.ProblemDetails
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &details))
+	assert.Equal(t, "VALIDATION_FAILED", details.Code)
+	codes := make(map[string]string)
+	for _, fieldError := range details.Errors {
+		codes[fieldError.Field] = fieldError.Code
+	}
+	assert.Equal(t, map[string]string{
+		"/bankName":    "FIELD_REQUIRED",
+		"/swiftCode":   "SWIFT_BRANCH_SUFFIX_RESERVED",
+		"/countryName": "COUNTRY_NAME_MISMATCH",
+	}, codes)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/v1/swift-codes/", bytes.NewBufferString(
+		`{"swiftCode":"AAAABBB1XXX","bankName":"Bank","countryISO2":"PL","countryName":"POLAND","isHeadquarter":true,"branches":[]}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &details))
+	assert.Equal(t, "UNKNOWN_FIELD", details.Code)
+	assert.Equal(t, []string{"/branches"}, details.Fields)
+}
+
+func TestMetrics(t *testing.T) {
+	_, _ = testutils.Collection.DeleteMany(context.Background(), bson.M{})
+	_, err := testutils.Collection.InsertOne(context.Background(), bson.M{
+		"swiftCode":     "AAAAPLP1XXX",
+		"bankName":      "Test Bank",
+		"countryISO2":   "PL",
+		"countryName":   "POLAND",
+		"isHeadquarter": true,
+		"branches":      bson.A{bson.M{"swiftCode": "AAAAPLP1ABC", "countryISO2": "PL"}},
+	})
+	assert.NoError(t, err)
+
+	appMetrics := metrics.New(prometheus.NewRegistry())
+	swiftService := services.NewSwiftCodeService(testutils.Collection)
+	assert.NoError(t, appMetrics.RegisterCountryTotals(swiftService.CountryTotals))
+	r := gin.New()
+	SetupRoutes(r, swiftService, WithMetrics(appMetrics))
+
+	for _, path := range []string{"/v1/swift-codes/AAAAPLP1XXX", "/v1/swift-codes/NONEXISTXXX", "/unknown"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		r.ServeHTTP(w, req)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/metrics", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, `swift_http_requests_total{method="GET",route="/v1/swift-codes/:swift-code",status="200"} 1`)
+	assert.Contains(t, body, `swift_http_requests_total{method="GET",route="/v1/swift-codes/:swift-code",status="404"} 1`)
+	assert.Contains(t, body, `swift_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, body, `swift_http_request_duration_seconds_bucket{method="GET",route="/v1/swift-codes/:swift-code",status="200",le="+Inf"} 1`)
+	assert.Contains(t, body, `swift_codes{country_iso2="PL",type="headquarter"} 1`)
+	assert.Contains(t, body, `swift_codes{country_iso2="PL",type="branch"} 1`)
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	imported := health.NewFlag("initial import has not finished")
+	checker := health.NewChecker(health.DefaultTimeout)
+	checker.Register("import", imported.Check)
+
+	// Probes stay open even when the API requires credentials.
+	keys, err := auth.ParseStaticKeys("reader|read-key|swift:read")
+	assert.NoError(t, err)
+	r := gin.New()
+	SetupRoutes(r, services.NewSwiftCodeService(testutils.Collection), WithAuth(auth.NewGuard(auth.NewStaticKeys(keys))), WithHealth(checker))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/healthz", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"up"}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/readyz", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	var response models.HealthResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, models.HealthStatusNotReady, response.Status)
+	assert.Equal(t, models.HealthStatusDown, response.Checks["import"].Status)
+
+	imported.Set()
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/readyz", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, models.HealthStatusReady, response.Status)
+}
+
+func TestCountries(t *testing.T) {
+	_, _ = services.CountryCollectionFor(testutils.Collection).DeleteMany(context.Background(), bson.M{})
+
+	keys, err := auth.ParseStaticKeys("reader|read-key|swift:read;admin|admin-key|swift:admin")
+	assert.NoError(t, err)
+	r := gin.New()
+	SetupRoutes(r, services.NewSwiftCodeService(testutils.Collection), WithAuth(auth.NewGuard(auth.NewStaticKeys(keys))))
+	request := func(method, path, key, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(auth.APIKeyHeader, key)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request("GET", "/v1/countries", "read-key", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var list models.CountriesResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.NotEmpty(t, list.Version)
+	assert.Greater(t, len(list.Countries), 200)
+
+	w = request("GET", "/v1/countries/pl", "read-key", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var country models.Country
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &country))
+	assert.NotEmpty(t, country.Aliases)
+	country.Aliases = nil
+	assert.Equal(t, models.Country{ISO2: "PL", ISO3: "POL", Numeric: "616", Name: "POLAND", Currency: "PLN", IBANLength: 28, SEPA: true}, country)
+
+	w = request("GET", "/v1/countries/XK", "read-key", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	kosovo := `{"iso3":"XKX","name":"Kosovo","currency":"EUR","ibanLength":20}`
+	w = request("PUT", "/v1/admin/countries/XK", "read-key", kosovo)
+	assert.Equal(t, http.StatusForbidden, w.Code, "overrides require swift:admin")
+
+	w = request("PUT", "/v1/admin/countries/XK", "admin-key", `{"name":"Kosovo","ibanLength":99}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = request("PUT", "/v1/admin/countries/XK", "admin-key", kosovo)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = request("GET", "/v1/countries/XK", "read-key", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &country))
+	assert.Equal(t, "KOSOVO", country.Name)
+	assert.Equal(t, "admin", country.UpdatedBy)
+	count, err := services.CountryCollectionFor(testutils.Collection).CountDocuments(context.Background(), bson.M{"_id": "XK"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count, "overrides should be stored")
+
+	w = request("DELETE", "/v1/admin/countries/XK", "admin-key", "")
+	assert.Equal(t, http.StatusNoContent, w.Code, "a country added by an override is removed with it")
+	w = request("DELETE", "/v1/admin/countries/XK", "admin-key", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = request("PUT", "/v1/admin/countries/PL", "admin-key", `{"iso3":"POL","numeric":"616","name":"Poland","currency":"EUR","ibanLength":28,"sepa":true}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = request("DELETE", "/v1/admin/countries/PL", "admin-key", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &country))
+	assert.Equal(t, "PLN", country.Currency, "removing an override restores the embedded entry")
+}
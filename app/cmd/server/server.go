@@ -3,35 +3,223 @@
 package server
 
 import (
+	"context"
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
+	"net"
+	"net/http"
 	"swift-app/cmd/router"
 	"swift-app/database"
+	"swift-app/internal/auth"
+	"swift-app/internal/cache"
+	"swift-app/internal/config"
+	"swift-app/internal/countries"
+	"swift-app/internal/errors"
+	"swift-app/internal/health"
+	"swift-app/internal/metrics"
+	"swift-app/internal/models"
+	"swift-app/internal/problem"
+	"swift-app/internal/ratelimit"
 	"swift-app/internal/services"
+	"swift-app/internal/timeouts"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+// ImportFunc imports SWIFT data into the database. It must stop once ctx is cancelled.
+type ImportFunc func(ctx context.Context) (*models.ImportSummary, error)
+
 // Function initializes and runs the HTTP server, setting up routes and services for handling SWIFT code API requests.
+// Request metrics and the stored SWIFT code totals are reported on appMetrics.
+// If importData is set, it runs once the server is listening and /readyz fails until it has finished.
+//
+// StartServer returns once ctx is cancelled and the server has shut down: the running import is cancelled,
+// new connections are refused and in-flight requests get cfg.HTTP.ShutdownTimeout to finish. It also returns
+// when the server cannot listen or the import fails. Closing the database is left to the caller, which
+// can do it safely once StartServer has returned.
+func StartServer(ctx context.Context, cfg config.Config, appMetrics *metrics.Metrics, importData ImportFunc) error {
+	timeouts.Configure(cfg.Timeouts.Operations())
 
-func StartServer() {
 	gin.SetMode(gin.ReleaseMode)
-	r := gin.Default()
+	r := gin.New()
+	// X-Forwarded-For is only honored from the configured proxies; otherwise any client could pick
+	// the IP its rate limits and audit entries are recorded under.
+	if err := r.SetTrustedProxies(cfg.HTTP.Proxies()); err != nil {
+		return fmt.Errorf("failed to configure trusted proxies: %w", err)
+	}
+	r.Use(gin.CustomRecoveryWithWriter(nil, recoverPanic))
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	swiftService := services.NewSwiftCodeService(database.GetCollection())
+	swiftService.Cache = cache.New(cache.Config{Size: cfg.Cache.Size, TTL: cfg.Cache.TTL})
+	if err := services.NewCountryService(services.CountryCollectionFor(swiftService.DB)).LoadOverrides(ctx); err != nil {
+		return fmt.Errorf("failed to load country overrides: %w", err)
+	}
+
+	guard, err := newAuthGuard(cfg.Auth)
+	if err != nil {
+		return fmt.Errorf("failed to configure authentication: %w", err)
+	}
+	limiter := newRateLimiter(cfg.RateLimit)
+	errorFormat, err := problem.ParseFormat(cfg.HTTP.ErrorFormat)
+	if err != nil {
+		return fmt.Errorf("failed to configure error responses: %w", err)
+	}
+	if err := appMetrics.RegisterCountryTotals(swiftService.CountryTotals); err != nil {
+		return fmt.Errorf("failed to configure metrics: %w", err)
+	}
+	imported := health.NewFlag("initial import has not finished")
+	router.SetupRoutes(r, swiftService, router.WithAuth(guard), router.WithRateLimit(limiter), router.WithErrorFormat(errorFormat),
+		router.WithMetrics(appMetrics), router.WithHealth(newHealthChecker(imported)))
 
-	router.SetupRoutes(r, swiftService)
+	srv := &http.Server{Addr: cfg.HTTP.Address(), Handler: r}
+	listener, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to start server: %w", err)
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(listener)
+	}()
+	slog.Info("server running", "address", "http://"+listener.Addr().String())
 
-	host := os.Getenv("HOST")
-	port := os.Getenv("PORT")
+	importCtx, cancelImport := context.WithCancel(ctx)
+	defer cancelImport()
+	var importDone chan error
+	if importData == nil {
+		imported.Set()
+	} else {
+		importDone = make(chan error, 1)
+		go func() {
+			importDone <- runImport(importCtx, importData, swiftService, appMetrics, imported)
+		}()
+	}
+
+	var runErr error
+	for running := true; running; {
+		select {
+		case <-ctx.Done():
+			slog.Info("shutdown requested, draining in-flight requests", slog.Duration("timeout", cfg.HTTP.ShutdownTimeout))
+			running = false
+		case err := <-serveErr:
+			// Serve only returns before Shutdown is called when the server failed.
+			return fmt.Errorf("server stopped: %w", err)
+		case err := <-importDone:
+			importDone = nil
+			if err != nil {
+				runErr = err
+				running = false
+			}
+		}
+	}
+
+	cancelImport()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("in-flight requests did not finish in time, closing their connections", "error", err)
+		_ = srv.Close()
+	}
+	if importDone != nil {
+		if err := <-importDone; err != nil && runErr == nil {
+			runErr = err
+		}
+	}
+	slog.Info("server stopped")
+	return runErr
+}
 
-	address := fmt.Sprintf("%s:%s", host, port)
-	fmt.Printf("Server running on http://%s\n", address)
+// runImport imports data in the background while the server only answers probes as not ready.
+// A failed import is returned so the process stops, as the service cannot be trusted with partially
+// imported data. An import cancelled by shutdown is only logged.
+func runImport(ctx context.Context, importData ImportFunc, swiftService *services.SwiftCodeService, appMetrics *metrics.Metrics, imported *health.Flag) error {
+	start := time.Now()
+	summary, err := importData(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			slog.Warn("data import cancelled by shutdown", "error", err)
+			return nil
+		}
+		return fmt.Errorf("failed to import data: %w", err)
+	}
+	appMetrics.ObserveImport(time.Since(start), summary)
+	// Lookups cached while the import was running may miss the imported codes.
+	swiftService.InvalidateCache()
+	imported.Set()
+	return nil
+}
+
+// newHealthChecker builds the readiness checks: MongoDB is reachable, its indexes exist, the initial
+// import has finished and the country table is loaded.
+func newHealthChecker(imported *health.Flag) *health.Checker {
+	checker := health.NewChecker(health.DefaultTimeout)
+	checker.Register("mongo", func(ctx context.Context) (map[string]interface{}, error) {
+		return nil, database.Ping(ctx)
+	})
+	checker.Register("indexes", func(ctx context.Context) (map[string]interface{}, error) {
+		count, err := database.CheckIndexes(ctx)
+		return map[string]interface{}{"count": count}, err
+	})
+	checker.Register("import", imported.Check)
+	checker.Register("countries", func(context.Context) (map[string]interface{}, error) {
+		count := countries.Default().Len()
+		if count == 0 {
+			return map[string]interface{}{"count": 0}, fmt.Errorf("country table is empty")
+		}
+		return map[string]interface{}{"count": count, "version": countries.DatasetVersion}, nil
+	})
+	return checker
+}
+
+// recoverPanic logs a panic raised by a handler and answers with an internal error.
+func recoverPanic(c *gin.Context, recovered any) {
+	slog.ErrorContext(c.Request.Context(), "panic while handling request", "panic", recovered, "route", c.FullPath())
+	problem.Abort(c, errors.ErrInternal)
+}
+
+// newAuthGuard builds the authentication guard from the auth settings.
+// Static keys come from auth.apiKeys, JWTs are verified against the auth.jwksPath file and
+// keys issued at runtime are looked up in the API key collection next to the SWIFT collection.
+func newAuthGuard(settings config.Auth) (*auth.Guard, error) {
+	if settings.Disabled {
+		slog.Warn("authentication is disabled, all API routes are open")
+		return auth.NewGuard(), nil
+	}
+
+	var providers []auth.Provider
+	if settings.APIKeys != "" {
+		keys, err := auth.ParseStaticKeys(settings.APIKeys)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, auth.NewStaticKeys(keys))
+	}
+	if settings.JWKSPath != "" {
+		verifier, err := auth.LoadJWKS(settings.JWKSPath, settings.JWTIssuer, settings.JWTAudience)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, verifier)
+	}
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("no credentials configured, set auth.apiKeys or auth.jwksPath (or auth.disabled)")
+	}
+
+	providers = append(providers, auth.NewKeyStore(auth.CollectionFor(database.GetCollection())))
+	return auth.NewGuard(providers...), nil
+}
 
-	if err := r.Run(":" + port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+// newRateLimiter builds the rate limiter from the rate limit settings.
+// Limits are requests per minute per client; daily quotas are tracked in the quota collection.
+func newRateLimiter(settings config.RateLimit) *ratelimit.Limiter {
+	limits := ratelimit.Config{
+		Read:       ratelimit.Limit{PerMinute: settings.ReadPerMinute},
+		Write:      ratelimit.Limit{PerMinute: settings.WritePerMinute},
+		Import:     ratelimit.Limit{PerMinute: settings.ImportPerMinute},
+		IP:         ratelimit.Limit{PerMinute: settings.IPPerMinute},
+		DailyQuota: settings.DailyQuota,
 	}
+	return ratelimit.New(limits, ratelimit.NewMongoQuotas(ratelimit.CollectionFor(database.GetCollection())))
 }
@@ -6,165 +6,541 @@ package database
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"strings"
+	"swift-app/internal/audit"
+	"swift-app/internal/auth"
+	"swift-app/internal/history"
 	"swift-app/internal/models"
+	"swift-app/internal/ratelimit"
+	"swift-app/internal/timeouts"
 	"swift-app/internal/utils"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var client *mongo.Client
 var collection *mongo.Collection
+var historyRecorder *history.Recorder
 var isConnected bool
 
+// swiftIndexes are the indexes of the SWIFT collection; readiness checks that they exist.
+var swiftIndexes = []mongo.IndexModel{
+	{
+		Keys:    bson.D{{Key: utils.FieldSwiftCode, Value: 1}},
+		Options: options.Index().SetUnique(true),
+	},
+	{
+		Keys:    bson.D{{Key: utils.FieldCountryISO2, Value: 1}},
+		Options: options.Index(),
+	},
+}
+
 // InitMongoDB establishes a connection to the MongoDB instance,
 // initializes the target collection, and creates indexes.
-func InitMongoDB(uri string, dbName string, collectionName string) error {
+// Additional client options, such as a command monitor, are applied on top of the URI.
+func InitMongoDB(ctx context.Context, uri string, dbName string, collectionName string, opts ...*options.ClientOptions) error {
 	if isConnected {
 		return nil
 	}
 
 	var err error
-	client, err = mongo.Connect(context.Background(), options.Client().ApplyURI(uri))
+	client, err = mongo.Connect(ctx, append([]*options.ClientOptions{options.Client().ApplyURI(uri)}, opts...)...)
 	if err != nil {
 		return fmt.Errorf("failed to connect to MongoDB: %v", err)
 	}
 
-	err = client.Ping(context.Background(), nil)
+	err = client.Ping(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to ping MongoDB: %v", err)
 	}
 
 	collection = client.Database(dbName).Collection(collectionName)
 
-	indexModels := []mongo.IndexModel{
-		{
-			Keys:    bson.M{utils.FieldSwiftCode: 1},
-			Options: options.Index().SetUnique(true),
-		},
-		{
-			Keys:    bson.M{utils.FieldCountryISO2: 1},
-			Options: options.Index(),
-		},
-	}
-	_, err = collection.Indexes().CreateMany(context.Background(), indexModels)
-	if err != nil {
-		return fmt.Errorf("failed to create unique index: %v", err)
+	historyRecorder = history.NewRecorder(history.CollectionFor(collection))
+	if err := EnsureIndexes(ctx); err != nil {
+		return err
 	}
 
 	isConnected = true
 	return nil
 }
 
-func IsCollectionEmpty() (bool, error) {
-	count, err := collection.CountDocuments(context.Background(), bson.M{})
+// EnsureIndexes creates the indexes of the SWIFT collection and of the history, audit, API key and
+// quota collections next to it. Indexes that already exist are left as they are.
+func EnsureIndexes(ctx context.Context) error {
+	if collection == nil {
+		return fmt.Errorf("MongoDB collection is not initialized")
+	}
+	if _, err := collection.Indexes().CreateMany(ctx, swiftIndexes); err != nil {
+		return fmt.Errorf("failed to create unique index: %v", err)
+	}
+	if err := history.NewRecorder(history.CollectionFor(collection)).EnsureIndexes(ctx); err != nil {
+		return err
+	}
+	if err := audit.NewStore(audit.CollectionFor(collection)).EnsureIndexes(ctx); err != nil {
+		return err
+	}
+	if err := auth.NewKeyStore(auth.CollectionFor(collection)).EnsureIndexes(ctx); err != nil {
+		return err
+	}
+	return ratelimit.NewMongoQuotas(ratelimit.CollectionFor(collection)).EnsureIndexes(ctx)
+}
+
+// SeedHistory records baseline history entries for stored headquarters that have none, see
+// history.Recorder.Seed. It returns the number of seeded headquarters.
+func SeedHistory(ctx context.Context) (int, error) {
+	if collection == nil {
+		return 0, fmt.Errorf("MongoDB collection is not initialized")
+	}
+	return history.NewRecorder(history.CollectionFor(collection)).Seed(ctx, collection)
+}
+
+// IsCollectionEmpty reports whether the SWIFT collection holds no documents.
+func IsCollectionEmpty(ctx context.Context) (bool, error) {
+	ctx, cancel := timeouts.Read(ctx)
+	defer cancel()
+	count, err := collection.CountDocuments(ctx, bson.M{})
 	if err != nil {
 		return false, fmt.Errorf("failed to count documents: %v", err)
 	}
 	return count == 0, nil
 }
 
-func CloseMongoDB() error {
+// CloseMongoDB disconnects the client, waiting for in-progress operations until ctx is done.
+func CloseMongoDB(ctx context.Context) error {
 	if client == nil {
 		return fmt.Errorf("MongoDB client is not initialized")
 	}
 
-	err := client.Disconnect(context.Background())
+	err := client.Disconnect(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to close MongoDB connection: %v", err)
 	}
+	isConnected = false
 
-	log.Println("MongoDB connection closed successfully")
+	slog.Info("MongoDB connection closed")
 	return nil
 }
 
 func GetCollection() *mongo.Collection {
 	return collection
 }
-func SaveHeadquarters(hqList []models.SwiftCode) (models.ImportSummary, error) {
-	summary := models.ImportSummary{}
 
-	for _, hq := range hqList {
-		filter := bson.M{utils.FieldSwiftCode: hq.SwiftCode}
-		count, err := collection.CountDocuments(context.Background(), filter)
-		if err != nil {
-			return summary, fmt.Errorf("error checking HQ existence: %v", err)
-		}
-
-		if count == 0 {
-			_, err := collection.InsertOne(context.Background(), bson.M{
-				utils.FieldSwiftCode:     hq.SwiftCode,
-				utils.FieldBankName:      hq.BankName,
-				utils.FieldAddress:       hq.Address,
-				utils.FieldCountryISO2:   hq.CountryISO2,
-				utils.FieldCountryName:   hq.CountryName,
-				utils.FieldIsHeadquarter: true,
-				utils.FieldBranches:      []interface{}{},
-			})
-			if err != nil {
-				return summary, fmt.Errorf("failed to insert HQ: %v", err)
-			}
-			summary.HQAdded++
-		} else {
-			summary.HQSkipped++
+// Ping checks that the MongoDB server is reachable.
+func Ping(ctx context.Context) error {
+	if client == nil {
+		return fmt.Errorf("MongoDB client is not initialized")
+	}
+	return client.Ping(ctx, nil)
+}
+
+// CheckIndexes verifies that the indexes of the SWIFT collection exist and returns how many indexes it has.
+func CheckIndexes(ctx context.Context) (int, error) {
+	if collection == nil {
+		return 0, fmt.Errorf("MongoDB collection is not initialized")
+	}
+	cursor, err := collection.Indexes().List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list indexes: %v", err)
+	}
+	var indexes []struct {
+		Key bson.D `bson:"key"`
+	}
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return 0, fmt.Errorf("failed to decode indexes: %v", err)
+	}
+
+	existing := make(map[string]bool, len(indexes))
+	for _, index := range indexes {
+		existing[indexKey(index.Key)] = true
+	}
+	var missing []string
+	for _, model := range swiftIndexes {
+		if key := indexKey(model.Keys.(bson.D)); !existing[key] {
+			missing = append(missing, key)
 		}
 	}
+	if len(missing) > 0 {
+		return len(indexes), fmt.Errorf("missing indexes: %s", strings.Join(missing, ", "))
+	}
+	return len(indexes), nil
+}
 
-	return summary, nil
+// indexKey renders index keys as "field_1", the way MongoDB names indexes by default.
+func indexKey(keys bson.D) string {
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s_%v", key.Key, key.Value))
+	}
+	return strings.Join(parts, "_")
 }
 
-func SaveBranches(branches []models.SwiftCode) (models.ImportSummary, error) {
+// ImportBatchSize is the number of records saved under one import batch timeout.
+const ImportBatchSize = 500
+
+// maxBranchAttempts bounds how often adding a branch is retried while API writes to its headquarter
+// keep getting in first.
+const maxBranchAttempts = 100
+
+// ImportTarget is a collection that an import saves SWIFT codes into: the SWIFT collection itself, or
+// a staging collection that replaces it once the import is complete (see StageImport).
+type ImportTarget struct {
+	coll *mongo.Collection
+	// history records the saved codes; it is nil for a staging collection, whose codes are recorded
+	// when it replaces the SWIFT collection.
+	history *history.Recorder
+}
+
+// Live returns the import target that saves into the SWIFT collection.
+func Live() *ImportTarget {
+	return &ImportTarget{coll: collection, history: historyRecorder}
+}
+
+// SaveHeadquarters inserts the headquarters that are not stored yet and counts the skipped ones.
+// Every ImportBatchSize records get their own import batch timeout. Once ctx is cancelled it stops
+// with ctx's error, returning the counts so far.
+func SaveHeadquarters(ctx context.Context, hqList []models.SwiftCode) (models.ImportSummary, error) {
+	return Live().SaveHeadquarters(ctx, hqList)
+}
+
+// SaveHeadquarters works like the package-level SaveHeadquarters, saving into t.
+func (t *ImportTarget) SaveHeadquarters(ctx context.Context, hqList []models.SwiftCode) (models.ImportSummary, error) {
 	summary := models.ImportSummary{}
+	err := inBatches(ctx, len(hqList), func(ctx context.Context, i int) error {
+		return t.saveHeadquarter(ctx, hqList[i], &summary)
+	})
+	return summary, err
+}
 
-	for _, branch := range branches {
-		hqCode := branch.SwiftCode[:8] + "XXX"
-		filter := bson.M{utils.FieldSwiftCode: hqCode, utils.FieldIsHeadquarter: true}
+func (t *ImportTarget) saveHeadquarter(ctx context.Context, hq models.SwiftCode, summary *models.ImportSummary) error {
+	filter := bson.M{utils.FieldSwiftCode: hq.SwiftCode}
+	count, err := t.coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("error checking HQ existence: %w", err)
+	}
+	if count > 0 {
+		summary.HQSkipped++
+		return nil
+	}
 
-		var hq bson.M
-		err := collection.FindOne(context.Background(), filter).Decode(&hq)
-		if err != nil {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	_, err = t.coll.InsertOne(ctx, bson.M{
+		utils.FieldSwiftCode:     hq.SwiftCode,
+		utils.FieldBankName:      hq.BankName,
+		utils.FieldAddress:       hq.Address,
+		utils.FieldCountryISO2:   hq.CountryISO2,
+		utils.FieldCountryName:   hq.CountryName,
+		utils.FieldIsHeadquarter: true,
+		utils.FieldBranches:      []interface{}{},
+		utils.FieldLastModified:  now,
+		utils.FieldVersion:       int64(1),
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		summary.HQSkipped++
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to insert HQ: %w", err)
+	}
+	t.recordHistory(ctx, hq.SwiftCode, models.HistoryOperationCreate, nil, &models.SwiftCode{
+		Address:       hq.Address,
+		BankName:      hq.BankName,
+		CountryISO2:   hq.CountryISO2,
+		CountryName:   hq.CountryName,
+		IsHeadquarter: true,
+		SwiftCode:     hq.SwiftCode,
+		Branches:      []models.SwiftBranch{},
+		LastModified:  &now,
+		Version:       1,
+	})
+	summary.HQAdded++
+	return nil
+}
+
+// SaveBranches adds branches to their stored headquarters, skipping duplicates and branches without one.
+// Every ImportBatchSize records get their own import batch timeout. Once ctx is cancelled it stops
+// with ctx's error, returning the counts so far.
+func SaveBranches(ctx context.Context, branches []models.SwiftCode) (models.ImportSummary, error) {
+	return Live().SaveBranches(ctx, branches)
+}
+
+// SaveBranches works like the package-level SaveBranches, saving into t.
+func (t *ImportTarget) SaveBranches(ctx context.Context, branches []models.SwiftCode) (models.ImportSummary, error) {
+	summary := models.ImportSummary{}
+	err := inBatches(ctx, len(branches), func(ctx context.Context, i int) error {
+		return t.saveBranch(ctx, branches[i], &summary)
+	})
+	return summary, err
+}
+
+// saveBranch pushes a branch onto the headquarter version it read, like the API does, so the history
+// entry's before snapshot is exactly the document that was changed. When the API changed the
+// headquarter in between, it is read again and the push retried.
+func (t *ImportTarget) saveBranch(ctx context.Context, branch models.SwiftCode, summary *models.ImportSummary) error {
+	hqCode := branch.SwiftCode[:8] + "XXX"
+	filter := bson.M{utils.FieldSwiftCode: hqCode, utils.FieldIsHeadquarter: true}
+
+	for attempt := 1; ; attempt++ {
+		var hq models.SwiftCode
+		err := t.coll.FindOne(ctx, filter).Decode(&hq)
+		if err == mongo.ErrNoDocuments {
 			summary.BranchesMissingHQ++
 			summary.BranchesSkipped++
-			continue
+			return nil
 		}
-
-		branchesField, ok := hq[utils.FieldBranches].(primitive.A)
-		if !ok {
-			branchesField = primitive.A{}
+		if err != nil {
+			return fmt.Errorf("error finding HQ: %w", err)
 		}
 
-		duplicate := false
-		for _, existing := range branchesField {
-			if bmap, ok := existing.(bson.M); ok {
-				if bmap[utils.FieldSwiftCode] == branch.SwiftCode {
-					duplicate = true
-					break
-				}
+		for _, existing := range hq.Branches {
+			if existing.SwiftCode == branch.SwiftCode {
+				summary.BranchesDuplicate++
+				summary.BranchesSkipped++
+				return nil
 			}
 		}
-		if duplicate {
-			summary.BranchesDuplicate++
-			summary.BranchesSkipped++
+
+		update := bson.M{
+			"$push": bson.M{utils.FieldBranches: bson.M{
+				utils.FieldSwiftCode:     branch.SwiftCode,
+				utils.FieldBankName:      branch.BankName,
+				utils.FieldAddress:       branch.Address,
+				utils.FieldCountryISO2:   branch.CountryISO2,
+				utils.FieldIsHeadquarter: false,
+			}},
+			"$set": bson.M{utils.FieldLastModified: time.Now().UTC().Truncate(time.Millisecond)},
+			"$inc": bson.M{utils.FieldVersion: 1},
+		}
+		pushFilter := bson.M{
+			utils.FieldSwiftCode:     hqCode,
+			utils.FieldIsHeadquarter: true,
+			utils.FieldVersion:       versionFilter(hq.Version),
+		}
+		var updated models.SwiftCode
+		err = t.coll.FindOneAndUpdate(ctx, pushFilter, update,
+			options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&updated)
+		if err == mongo.ErrNoDocuments {
+			if attempt == maxBranchAttempts {
+				return fmt.Errorf("headquarter %s kept changing while adding branch %s", hqCode, branch.SwiftCode)
+			}
 			continue
 		}
+		if err != nil {
+			return fmt.Errorf("failed to add branch: %w", err)
+		}
+		t.recordHistory(ctx, branch.SwiftCode, models.HistoryOperationCreate, &hq, &updated)
+		summary.BranchesAdded++
+		return nil
+	}
+}
+
+// versionFilter matches a headquarter version as read. Documents stored before versions were
+// counted have none.
+func versionFilter(version int64) interface{} {
+	if version == 0 {
+		return bson.M{"$exists": false}
+	}
+	return version
+}
+
+// CountHeadquarters returns the number of stored headquarters. Branches are stored inside them.
+func CountHeadquarters(ctx context.Context) (int, error) {
+	ctx, cancel := timeouts.Read(ctx)
+	defer cancel()
+	count, err := collection.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count documents: %w", err)
+	}
+	return int(count), nil
+}
+
+// StageImport creates an empty staging collection next to the SWIFT collection, with the same indexes,
+// for an import that replaces every stored SWIFT code. The stored codes stay in place, and keep being
+// served, until ReplaceWith renames the staging collection over them. A staging collection left behind
+// by an interrupted import is dropped first.
+func StageImport(ctx context.Context) (*ImportTarget, error) {
+	if collection == nil {
+		return nil, fmt.Errorf("MongoDB collection is not initialized")
+	}
+	ctx, cancel := timeouts.Write(ctx)
+	defer cancel()
+	staging := collection.Database().Collection(collection.Name() + utils.StagingCollectionSuffix)
+	if err := staging.Drop(ctx); err != nil {
+		return nil, fmt.Errorf("failed to drop staging collection: %w", err)
+	}
+	if _, err := staging.Indexes().CreateMany(ctx, swiftIndexes); err != nil {
+		return nil, fmt.Errorf("failed to create staging indexes: %w", err)
+	}
+	return &ImportTarget{coll: staging}, nil
+}
+
+// Drop removes a staging collection that will not replace the SWIFT collection. It is not cancelled
+// with ctx, so an import that was cancelled can still clean up.
+func (t *ImportTarget) Drop(ctx context.Context) error {
+	ctx, cancel := timeouts.Write(context.WithoutCancel(ctx))
+	defer cancel()
+	if err := t.coll.Drop(ctx); err != nil {
+		return fmt.Errorf("failed to drop staging collection: %w", err)
+	}
+	return nil
+}
+
+// ReplaceWith renames the staging collection over the SWIFT collection in one step, replacing every
+// stored SWIFT code with the staged ones. Only once the rename succeeded, a delete history entry is
+// recorded for every replaced code and a create entry for every staged one, so the history and as-of
+// lookups follow the stored data. It returns the number of replaced headquarters.
+func ReplaceWith(ctx context.Context, staging *ImportTarget) (int, error) {
+	replaced, err := findHeadquarters(ctx, collection)
+	if err != nil {
+		return 0, err
+	}
+	staged, err := findHeadquarters(ctx, staging.coll)
+	if err != nil {
+		return 0, err
+	}
+
+	dbName := collection.Database().Name()
+	renameCtx, cancel := timeouts.Write(ctx)
+	defer cancel()
+	err = client.Database("admin").RunCommand(renameCtx, bson.D{
+		{Key: "renameCollection", Value: dbName + "." + staging.coll.Name()},
+		{Key: "to", Value: dbName + "." + collection.Name()},
+		{Key: "dropTarget", Value: true},
+	}).Err()
+	if err != nil {
+		return 0, fmt.Errorf("failed to replace SWIFT codes with the staged ones: %w", err)
+	}
+	slog.InfoContext(ctx, "stored SWIFT codes replaced", slog.Int("headquarters", len(replaced)), slog.Int("staged", len(staged)))
+
+	// The codes are replaced now, so an import cancelled from here on must still record that.
+	ctx = context.WithoutCancel(ctx)
+	target := Live()
+	for i := range replaced {
+		hq := &replaced[i]
+		target.recordHistory(ctx, hq.SwiftCode, models.HistoryOperationDelete, hq, nil)
+		for _, branch := range hq.Branches {
+			target.recordHistory(ctx, branch.SwiftCode, models.HistoryOperationDelete, hq, nil)
+		}
+	}
+	for i := range staged {
+		hq := &staged[i]
+		target.recordHistory(ctx, hq.SwiftCode, models.HistoryOperationCreate, nil, hq)
+		for _, branch := range hq.Branches {
+			target.recordHistory(ctx, branch.SwiftCode, models.HistoryOperationCreate, nil, hq)
+		}
+	}
+	return len(replaced), nil
+}
 
-		update := bson.M{"$push": bson.M{utils.FieldBranches: bson.M{
-			utils.FieldSwiftCode:     branch.SwiftCode,
-			utils.FieldBankName:      branch.BankName,
-			utils.FieldAddress:       branch.Address,
-			utils.FieldCountryISO2:   branch.CountryISO2,
-			utils.FieldIsHeadquarter: false,
-		}}}
+// findHeadquarters reads every headquarter stored in coll with its branches.
+func findHeadquarters(ctx context.Context, coll *mongo.Collection) ([]models.SwiftCode, error) {
+	ctx, cancel := timeouts.ImportBatch(ctx)
+	defer cancel()
+	cursor, err := coll.Find(ctx, bson.M{utils.FieldIsHeadquarter: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stored SWIFT codes: %w", err)
+	}
+	var headquarters []models.SwiftCode
+	if err := cursor.All(ctx, &headquarters); err != nil {
+		return nil, fmt.Errorf("failed to decode stored SWIFT codes: %w", err)
+	}
+	return headquarters, nil
+}
+
+// PreviewImport counts what saving hqList and then branches would do, without writing anything.
+// Branches of headquarters that would be added count as added. With assumeEmpty the stored data is
+// ignored, as it would be removed before a replacing import.
+func PreviewImport(ctx context.Context, hqList, branches []models.SwiftCode, assumeEmpty bool) (models.ImportSummary, error) {
+	summary := models.ImportSummary{}
+	added := make(map[string]bool, len(hqList))
+	err := inBatches(ctx, len(hqList), func(ctx context.Context, i int) error {
+		hq := hqList[i]
+		if !assumeEmpty {
+			count, err := collection.CountDocuments(ctx, bson.M{utils.FieldSwiftCode: hq.SwiftCode})
+			if err != nil {
+				return fmt.Errorf("error checking HQ existence: %w", err)
+			}
+			if count > 0 {
+				summary.HQSkipped++
+				return nil
+			}
+		}
+		added[hq.SwiftCode] = true
+		summary.HQAdded++
+		return nil
+	})
+	if err != nil {
+		return summary, err
+	}
 
-		_, err = collection.UpdateOne(context.Background(), filter, update)
+	err = inBatches(ctx, len(branches), func(ctx context.Context, i int) error {
+		branch := branches[i]
+		hqCode := branch.SwiftCode[:8] + "XXX"
+		if added[hqCode] {
+			summary.BranchesAdded++
+			return nil
+		}
+		var hq models.SwiftCode
+		err := mongo.ErrNoDocuments
+		if !assumeEmpty {
+			err = collection.FindOne(ctx, bson.M{utils.FieldSwiftCode: hqCode, utils.FieldIsHeadquarter: true}).Decode(&hq)
+		}
+		if err == mongo.ErrNoDocuments {
+			summary.BranchesMissingHQ++
+			summary.BranchesSkipped++
+			return nil
+		}
 		if err != nil {
-			return summary, fmt.Errorf("failed to add branch: %v", err)
+			return fmt.Errorf("error finding HQ: %w", err)
+		}
+		for _, existing := range hq.Branches {
+			if existing.SwiftCode == branch.SwiftCode {
+				summary.BranchesDuplicate++
+				summary.BranchesSkipped++
+				return nil
+			}
 		}
 		summary.BranchesAdded++
+		return nil
+	})
+	return summary, err
+}
+
+// inBatches calls save for records 0 to n-1, in batches of ImportBatchSize records that each get
+// the import batch timeout. It stops at the first error, or with ctx's error once ctx is cancelled.
+func inBatches(ctx context.Context, n int, save func(ctx context.Context, i int) error) error {
+	for start := 0; start < n; start += ImportBatchSize {
+		if err := saveBatch(ctx, start, min(start+ImportBatchSize, n), save); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	return summary, nil
+func saveBatch(ctx context.Context, start, end int, save func(ctx context.Context, i int) error) error {
+	batchCtx, cancel := timeouts.ImportBatch(ctx)
+	defer cancel()
+	for i := start; i < end; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := save(batchCtx, i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordHistory stores a history entry for a change made by the importer in t.
+func (t *ImportTarget) recordHistory(ctx context.Context, swiftCode, operation string, before, after *models.SwiftCode) {
+	if t.history == nil {
+		return
+	}
+	if err := t.history.Record(ctx, swiftCode, operation, models.HistorySourceImport, before, after); err != nil {
+		slog.WarnContext(ctx, "failed to record import history", "swift_code", swiftCode, "error", err)
+	}
 }
@@ -26,7 +26,7 @@ func TestInitMongoDB(t *testing.T) {
 	uri, err := testutils.MongoContainer.ConnectionString(context.Background())
 	assert.NoError(t, err, "Failed to retrieve MongoDB URI")
 
-	err = InitMongoDB(uri, "swiftDB_test", "swiftCodes")
+	err = InitMongoDB(context.Background(), uri, "swiftDB_test", "swiftCodes")
 	assert.NoError(t, err, "InitMongoDB should not return an error")
 
 	assert.NotNil(t, collection, "Collection should not be nil")
@@ -48,10 +48,31 @@ func TestInitMongoDB(t *testing.T) {
 	assert.Contains(t, indexNames, "swiftCode_1", "Index 'swiftCode_1' should exist")
 }
 
+func TestPingAndCheckIndexes(t *testing.T) {
+	uri, err := testutils.MongoContainer.ConnectionString(context.Background())
+	assert.NoError(t, err, "Failed to retrieve MongoDB URI")
+	assert.NoError(t, InitMongoDB(context.Background(), uri, "swiftDB_test", "swiftCodes"))
+
+	assert.NoError(t, Ping(context.Background()), "Ping should reach the server")
+
+	count, err := CheckIndexes(context.Background())
+	assert.NoError(t, err, "All indexes should exist after InitMongoDB")
+	assert.GreaterOrEqual(t, count, len(swiftIndexes))
+
+	_, err = collection.Indexes().DropOne(context.Background(), "countryISO2_1")
+	assert.NoError(t, err)
+	defer func() {
+		_, _ = collection.Indexes().CreateMany(context.Background(), swiftIndexes)
+	}()
+
+	_, err = CheckIndexes(context.Background())
+	assert.ErrorContains(t, err, "countryISO2_1")
+}
+
 func TestIsCollectionEmpty(t *testing.T) {
 	clearCollection()
 
-	empty, err := IsCollectionEmpty()
+	empty, err := IsCollectionEmpty(context.Background())
 	assert.NoError(t, err, "IsCollectionEmpty should not return an error")
 	assert.True(t, empty, "Collection should be empty")
 }
@@ -70,7 +91,7 @@ func TestSaveHeadquarters(t *testing.T) {
 		},
 	}
 
-	summary, err := SaveHeadquarters(hqs)
+	summary, err := SaveHeadquarters(context.Background(), hqs)
 	assert.NoError(t, err, "SaveHeadquarters should not return an error")
 
 	count, err := testutils.Collection.CountDocuments(context.Background(), bson.M{"isHeadquarter": true})
@@ -93,7 +114,7 @@ func TestSaveBranches(t *testing.T) {
 		IsHeadquarter: true,
 	}
 
-	_, err := SaveHeadquarters([]models.SwiftCode{hq})
+	_, err := SaveHeadquarters(context.Background(), []models.SwiftCode{hq})
 	assert.NoError(t, err, "SaveHeadquarters should not return an error")
 
 	count, err := testutils.Collection.CountDocuments(context.Background(), bson.M{"isHeadquarter": true})
@@ -109,7 +130,7 @@ func TestSaveBranches(t *testing.T) {
 		IsHeadquarter: false,
 	}
 
-	summary, err := SaveBranches([]models.SwiftCode{branch})
+	summary, err := SaveBranches(context.Background(), []models.SwiftCode{branch})
 	assert.NoError(t, err, "SaveBranches should not return an error")
 
 	var result bson.M
@@ -143,3 +164,158 @@ func TestSaveBranches(t *testing.T) {
 	assert.Equal(t, 0, summary.BranchesMissingHQ, "Expected 0 missing HQ")
 	assert.Equal(t, 0, summary.BranchesSkipped, "Expected 0 skipped branches")
 }
+
+func TestSaveBranches_VersionsHeadquarter(t *testing.T) {
+	clearCollection()
+	_, _ = historyRecorder.DB.DeleteMany(context.Background(), bson.M{})
+
+	// A headquarter stored before versions were counted has no version field.
+	_, err := testutils.Collection.InsertOne(context.Background(), bson.M{
+		"swiftCode": "VERBANK1XXX", "bankName": "Ver Bank", "countryISO2": "PL", "countryName": "POLAND",
+		"isHeadquarter": true, "branches": []bson.M{},
+	})
+	assert.NoError(t, err)
+
+	summary, err := SaveBranches(context.Background(), []models.SwiftCode{
+		{SwiftCode: "VERBANK1AAA", BankName: "Ver Branch", CountryISO2: "PL"},
+		{SwiftCode: "VERBANK1BBB", BankName: "Ver Branch", CountryISO2: "PL"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, summary.BranchesAdded)
+
+	var hq models.SwiftCode
+	assert.NoError(t, testutils.Collection.FindOne(context.Background(), bson.M{"swiftCode": "VERBANK1XXX"}).Decode(&hq))
+	assert.Equal(t, int64(2), hq.Version)
+
+	entries, err := historyRecorder.List(context.Background(), "VERBANK1XXX")
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 2) {
+		for _, entry := range entries {
+			assert.Equal(t, entry.Before.Version+1, entry.After.Version, "each branch is pushed onto the version recorded as before")
+		}
+	}
+}
+
+func TestPreviewImport(t *testing.T) {
+	clearCollection()
+
+	stored := models.SwiftCode{SwiftCode: "PRVBANK1XXX", BankName: "Stored Bank", CountryISO2: "PL", CountryName: "Poland", IsHeadquarter: true}
+	_, err := SaveHeadquarters(context.Background(), []models.SwiftCode{stored})
+	assert.NoError(t, err)
+	_, err = SaveBranches(context.Background(), []models.SwiftCode{{SwiftCode: "PRVBANK1AAA", CountryISO2: "PL"}})
+	assert.NoError(t, err)
+
+	hqs := []models.SwiftCode{stored, {SwiftCode: "NEWBANK1XXX", BankName: "New Bank", CountryISO2: "PL", CountryName: "Poland", IsHeadquarter: true}}
+	branches := []models.SwiftCode{
+		{SwiftCode: "PRVBANK1AAA", CountryISO2: "PL"},
+		{SwiftCode: "PRVBANK1BBB", CountryISO2: "PL"},
+		{SwiftCode: "NEWBANK1AAA", CountryISO2: "PL"},
+		{SwiftCode: "NOHQBNK1AAA", CountryISO2: "PL"},
+	}
+
+	summary, err := PreviewImport(context.Background(), hqs, branches, false)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ImportSummary{
+		HQAdded:           1,
+		HQSkipped:         1,
+		BranchesAdded:     2,
+		BranchesDuplicate: 1,
+		BranchesMissingHQ: 1,
+		BranchesSkipped:   2,
+	}, summary)
+
+	count, err := CountHeadquarters(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count, "A preview should not write anything")
+
+	summary, err = PreviewImport(context.Background(), hqs, branches, true)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ImportSummary{
+		HQAdded:           2,
+		BranchesAdded:     3,
+		BranchesMissingHQ: 1,
+		BranchesSkipped:   1,
+	}, summary, "Assuming an empty store, every branch of an imported HQ is added")
+}
+
+func TestReplaceWith(t *testing.T) {
+	clearCollection()
+	_, _ = historyRecorder.DB.DeleteMany(context.Background(), bson.M{})
+
+	_, err := SaveHeadquarters(context.Background(), []models.SwiftCode{
+		{SwiftCode: "DELBANK1XXX", CountryISO2: "PL", IsHeadquarter: true},
+		{SwiftCode: "DELBANK2XXX", CountryISO2: "PL", IsHeadquarter: true},
+	})
+	assert.NoError(t, err)
+
+	staging, err := StageImport(context.Background())
+	assert.NoError(t, err)
+	_, err = staging.SaveHeadquarters(context.Background(), []models.SwiftCode{
+		{SwiftCode: "DELBANK1XXX", CountryISO2: "PL", IsHeadquarter: true},
+		{SwiftCode: "NEWBANK1XXX", CountryISO2: "PL", IsHeadquarter: true},
+	})
+	assert.NoError(t, err)
+	_, err = staging.SaveBranches(context.Background(), []models.SwiftCode{{SwiftCode: "NEWBANK1AAA", CountryISO2: "PL"}})
+	assert.NoError(t, err)
+
+	count, err := CountHeadquarters(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count, "Staged codes are not served before they replace the stored ones")
+	entries, err := historyRecorder.List(context.Background(), "NEWBANK1XXX")
+	assert.NoError(t, err)
+	assert.Empty(t, entries, "Staged codes get no history before they replace the stored ones")
+
+	removed, err := ReplaceWith(context.Background(), staging)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, removed)
+
+	var stored []models.SwiftCode
+	cursor, err := testutils.Collection.Find(context.Background(), bson.M{})
+	assert.NoError(t, err)
+	assert.NoError(t, cursor.All(context.Background(), &stored))
+	codes := make([]string, 0, len(stored))
+	for _, hq := range stored {
+		codes = append(codes, hq.SwiftCode)
+	}
+	assert.ElementsMatch(t, []string{"DELBANK1XXX", "NEWBANK1XXX"}, codes)
+	count, err = CheckIndexes(context.Background())
+	assert.NoError(t, err, "The staged collection brings the indexes along")
+	assert.NotZero(t, count)
+
+	entries, err = historyRecorder.List(context.Background(), "DELBANK1XXX")
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 3) {
+		assert.Equal(t, models.HistoryOperationDelete, entries[1].Operation)
+		assert.Equal(t, models.HistorySourceImport, entries[1].Source)
+		assert.Nil(t, entries[1].After)
+		assert.Equal(t, models.HistoryOperationCreate, entries[2].Operation)
+	}
+	_, err = historyRecorder.AsOf(context.Background(), "DELBANK2XXX", time.Now())
+	assert.Error(t, err, "A replaced code no longer exists as of now")
+	branch, err := historyRecorder.AsOf(context.Background(), "NEWBANK1AAA", time.Now())
+	assert.NoError(t, err, "A staged branch exists as of now")
+	assert.Equal(t, "NEWBANK1AAA", branch.SwiftCode)
+}
+
+func TestStageImport_DropKeepsStoredCodes(t *testing.T) {
+	clearCollection()
+	_, _ = historyRecorder.DB.DeleteMany(context.Background(), bson.M{})
+
+	_, err := SaveHeadquarters(context.Background(), []models.SwiftCode{{SwiftCode: "KEPBANK1XXX", CountryISO2: "PL", IsHeadquarter: true}})
+	assert.NoError(t, err)
+
+	staging, err := StageImport(context.Background())
+	assert.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = staging.SaveHeadquarters(ctx, []models.SwiftCode{{SwiftCode: "NEWBANK2XXX", CountryISO2: "PL", IsHeadquarter: true}})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NoError(t, staging.Drop(ctx), "Dropping is not cancelled with the import")
+
+	count, err := CountHeadquarters(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count, "An import that never replaced the stored codes leaves them in place")
+	names, err := testutils.Collection.Database().ListCollectionNames(context.Background(), bson.M{"name": staging.coll.Name()})
+	assert.NoError(t, err)
+	assert.Empty(t, names)
+}
@@ -1,26 +1,95 @@
 package initialization
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"swift-app/database"
+	"swift-app/internal/logging"
 	"swift-app/internal/models"
+	"swift-app/internal/tracing"
 	parser "swift-app/pkg/csv"
+	"swift-app/pkg/importer"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // InitializeDatabase connects to MongoDB and initializes the target collection.
-func InitializeDatabase(uri, dbName, collectionName string) error {
-	err := database.InitMongoDB(uri, dbName, collectionName)
+func InitializeDatabase(ctx context.Context, uri, dbName, collectionName string, opts ...*options.ClientOptions) error {
+	err := database.InitMongoDB(ctx, uri, dbName, collectionName, opts...)
 	if err != nil {
 		return fmt.Errorf("failed to initialize MongoDB: %v", err)
 	}
 	return nil
 }
 
-// ImportData loads SWIFT codes from a CSV file and imports them into the database, managing headquarters and branches separately.
-func ImportData(csvPath string) (*models.ImportSummary, error) {
-	swiftCodes, err := parser.LoadSwiftCodes(csvPath)
+// Import modes.
+const (
+	// ModeMerge keeps the stored SWIFT codes and adds the new ones.
+	ModeMerge = "merge"
+	// ModeReplace replaces every stored SWIFT code with the imported ones.
+	ModeReplace = "replace"
+)
+
+// ImportOptions changes how ImportFile imports a file.
+type ImportOptions struct {
+	// Mode is ModeMerge (the default when empty) or ModeReplace.
+	Mode string
+	// DryRun parses and validates the file and counts what the import would do, without writing anything.
+	DryRun bool
+	// Format is the format of the file; it is detected when empty or importer.FormatAuto.
+	Format importer.Format
+	// Profile maps the columns of a CSV file; nil reads the default layout.
+	Profile *parser.Profile
+}
+
+// ParseMode validates an import mode, defaulting to ModeMerge when it is empty.
+func ParseMode(mode string) (string, error) {
+	switch mode {
+	case "", ModeMerge:
+		return ModeMerge, nil
+	case ModeReplace:
+		return ModeReplace, nil
+	}
+	return "", fmt.Errorf("unknown import mode %q, expected %s or %s", mode, ModeMerge, ModeReplace)
+}
+
+// ImportData loads SWIFT codes from a file in any supported format and merges them into the database.
+func ImportData(ctx context.Context, path string) (*models.ImportSummary, error) {
+	return ImportFile(ctx, path, ImportOptions{})
+}
+
+// ImportFile loads SWIFT codes from a CSV or BIC directory file and imports them into the database, managing headquarters and branches separately.
+// The duration of each phase and the resulting counters are logged, and every phase is traced as a child of the import span.
+// Cancelling ctx stops the import before its next record; the returned error then wraps ctx's error.
+func ImportFile(ctx context.Context, path string, opts ImportOptions) (_ *models.ImportSummary, err error) {
+	mode, err := ParseMode(opts.Mode)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load swift codes: %v", err)
+		return nil, err
+	}
+	ctx, span := tracing.Start(ctx, "ImportData", attribute.String("import.source", path),
+		attribute.String("import.mode", mode), attribute.Bool("import.dry_run", opts.DryRun))
+	defer tracing.End(span, &err)
+
+	start := time.Now()
+	var swiftCodes []models.SwiftCode
+	var format importer.Format
+	err = importPhase(ctx, "parse", func(context.Context) (int, error) {
+		codes, detected, err := importer.LoadFile(path, importer.Options{Format: opts.Format, Profile: opts.Profile})
+		if err != nil {
+			return 0, fmt.Errorf("failed to load swift codes: %v", err)
+		}
+		span.SetAttributes(attribute.String("import.format", string(detected)))
+		if opts.Profile != nil {
+			span.SetAttributes(attribute.String("import.profile", opts.Profile.Name))
+		}
+		swiftCodes, format = codes, detected
+		return len(codes), nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	var hqList, branchList []models.SwiftCode
@@ -32,17 +101,75 @@ func ImportData(csvPath string) (*models.ImportSummary, error) {
 		}
 	}
 
-	hqSummary, err := database.SaveHeadquarters(hqList)
+	if opts.DryRun {
+		summary, err := previewImport(ctx, mode, hqList, branchList)
+		if err != nil {
+			return nil, err
+		}
+		slog.InfoContext(ctx, "data import dry run complete", slog.String("source", path), slog.String("format", string(format)), slog.String("mode", mode),
+			logging.Duration(time.Since(start)), slog.Any("summary", summary))
+		return summary, nil
+	}
+
+	// A replacing import saves into a staging collection that replaces the stored codes only once every
+	// record is saved, so an import that fails or is cancelled leaves them as they were.
+	target := database.Live()
+	if mode == ModeReplace {
+		target, err = database.StageImport(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err != nil {
+				if dropErr := target.Drop(ctx); dropErr != nil {
+					slog.WarnContext(ctx, "failed to drop staging collection", "error", dropErr)
+				}
+			}
+		}()
+	}
+
+	var hqSummary, branchSummary models.ImportSummary
+	err = importPhase(ctx, "headquarters", func(ctx context.Context) (int, error) {
+		saved, err := target.SaveHeadquarters(ctx, hqList)
+		if err != nil {
+			return 0, fmt.Errorf("failed to save HQs: %w", err)
+		}
+		hqSummary = saved
+		return len(hqList), nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to save HQs: %v", err)
+		return nil, err
 	}
 
-	branchSummary, err := database.SaveBranches(branchList)
+	err = importPhase(ctx, "branches", func(ctx context.Context) (int, error) {
+		saved, err := target.SaveBranches(ctx, branchList)
+		if err != nil {
+			return 0, fmt.Errorf("failed to save branches: %w", err)
+		}
+		branchSummary = saved
+		return len(branchList), nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to save branches: %v", err)
+		return nil, err
+	}
+
+	var removed int
+	if mode == ModeReplace {
+		err = importPhase(ctx, "replace", func(ctx context.Context) (int, error) {
+			count, err := database.ReplaceWith(ctx, target)
+			if err != nil {
+				return 0, err
+			}
+			removed = count
+			return count, nil
+		})
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	summary := &models.ImportSummary{
+		HQRemoved:         removed,
 		HQAdded:           hqSummary.HQAdded,
 		HQSkipped:         hqSummary.HQSkipped,
 		BranchesAdded:     branchSummary.BranchesAdded,
@@ -51,5 +178,47 @@ func ImportData(csvPath string) (*models.ImportSummary, error) {
 		BranchesSkipped:   branchSummary.BranchesSkipped,
 	}
 
+	slog.InfoContext(ctx, "data import complete", slog.String("source", path), slog.String("format", string(format)), slog.String("mode", mode), logging.Duration(time.Since(start)), slog.Any("summary", summary))
+	return summary, nil
+}
+
+// previewImport counts what importing the parsed records in mode would do, without writing anything.
+func previewImport(ctx context.Context, mode string, hqList, branchList []models.SwiftCode) (*models.ImportSummary, error) {
+	summary := &models.ImportSummary{}
+	if mode == ModeReplace {
+		count, err := database.CountHeadquarters(ctx)
+		if err != nil {
+			return nil, err
+		}
+		summary.HQRemoved = count
+	}
+	err := importPhase(ctx, "preview", func(ctx context.Context) (int, error) {
+		preview, err := database.PreviewImport(ctx, hqList, branchList, mode == ModeReplace)
+		if err != nil {
+			return 0, fmt.Errorf("failed to preview import: %w", err)
+		}
+		preview.HQRemoved = summary.HQRemoved
+		*summary = preview
+		return len(hqList) + len(branchList), nil
+	})
+	if err != nil {
+		return nil, err
+	}
 	return summary, nil
 }
+
+// importPhase runs one import phase in its own span and logs how long it took. run returns the
+// number of records the phase handled.
+func importPhase(ctx context.Context, phase string, run func(ctx context.Context) (int, error)) (err error) {
+	ctx, span := tracing.Start(ctx, "import "+phase, attribute.String("import.phase", phase))
+	defer tracing.End(span, &err)
+
+	start := time.Now()
+	records, err := run(ctx)
+	if err != nil {
+		return err
+	}
+	span.SetAttributes(attribute.Int("import.records", records))
+	slog.InfoContext(ctx, "import phase complete", slog.String("phase", phase), logging.Duration(time.Since(start)), slog.Int("records", records))
+	return nil
+}
@@ -20,7 +20,7 @@ func TestInitializeDatabase_WithTestContainer(t *testing.T) {
 	uri, err := testutils.MongoContainer.ConnectionString(ctx)
 	assert.NoError(t, err)
 
-	err = InitializeDatabase(uri, "swiftTestDB", "swiftCodes")
+	err = InitializeDatabase(ctx, uri, "swiftTestDB", "swiftCodes")
 	assert.NoError(t, err)
 
 	assert.NotNil(t, database.GetCollection())
@@ -32,13 +32,13 @@ func TestImportData_WithTestContainer(t *testing.T) {
 	uri, err := testutils.MongoContainer.ConnectionString(ctx)
 	assert.NoError(t, err)
 
-	err = InitializeDatabase(uri, "swiftTestDB", "swiftCodes")
+	err = InitializeDatabase(ctx, uri, "swiftTestDB", "swiftCodes")
 	assert.NoError(t, err)
 
 	_, currentFilePath, _, _ := runtime.Caller(0)
 	testCSV := filepath.Join(filepath.Dir(currentFilePath), "test_data", "swift_test.csv")
 
-	summary, err := ImportData(testCSV)
+	summary, err := ImportData(context.Background(), testCSV)
 	assert.NoError(t, err)
 
 	assert.GreaterOrEqual(t, summary.HQAdded, 1)
@@ -51,6 +51,89 @@ func TestImportData_WithTestContainer(t *testing.T) {
 	t.Cleanup(func() {
 		collection := database.GetCollection()
 		_, _ = collection.DeleteMany(ctx, struct{}{})
-		_ = database.CloseMongoDB()
+		_ = database.CloseMongoDB(ctx)
 	})
 }
+
+func TestImportData_Cancelled(t *testing.T) {
+	uri, err := testutils.MongoContainer.ConnectionString(context.Background())
+	assert.NoError(t, err)
+	assert.NoError(t, InitializeDatabase(context.Background(), uri, "swiftTestDB", "swiftCodes"))
+
+	_, currentFilePath, _, _ := runtime.Caller(0)
+	testCSV := filepath.Join(filepath.Dir(currentFilePath), "test_data", "swift_test.csv")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = ImportData(ctx, testCSV)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	count, err := database.GetCollection().CountDocuments(context.Background(), struct{}{})
+	assert.NoError(t, err)
+	assert.Zero(t, count, "A cancelled import should not write any record")
+}
+
+func TestImportFile_DryRunAndReplace(t *testing.T) {
+	ctx := context.Background()
+	uri, err := testutils.MongoContainer.ConnectionString(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, InitializeDatabase(ctx, uri, "swiftTestDB", "swiftCodes"))
+	t.Cleanup(func() {
+		_, _ = database.GetCollection().DeleteMany(ctx, struct{}{})
+	})
+
+	_, currentFilePath, _, _ := runtime.Caller(0)
+	testCSV := filepath.Join(filepath.Dir(currentFilePath), "test_data", "swift_test.csv")
+
+	summary, err := ImportFile(ctx, testCSV, ImportOptions{DryRun: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.HQAdded)
+	assert.Equal(t, 1, summary.BranchesMissingHQ)
+	count, err := database.CountHeadquarters(ctx)
+	assert.NoError(t, err)
+	assert.Zero(t, count, "A dry run should not write anything")
+
+	_, err = ImportFile(ctx, testCSV, ImportOptions{})
+	assert.NoError(t, err)
+
+	summary, err = ImportFile(ctx, testCSV, ImportOptions{Mode: ModeReplace, DryRun: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.HQRemoved)
+	assert.Equal(t, 1, summary.HQAdded, "After replacing, the stored HQ is added again")
+
+	summary, err = ImportFile(ctx, testCSV, ImportOptions{Mode: ModeReplace})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.HQRemoved)
+	assert.Equal(t, 1, summary.HQAdded)
+	assert.Equal(t, 0, summary.HQSkipped)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = ImportFile(cancelled, testCSV, ImportOptions{Mode: ModeReplace})
+	assert.Error(t, err)
+	count, err = database.CountHeadquarters(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count, "A cancelled replacing import keeps the stored codes")
+
+	_, err = ImportFile(ctx, testCSV, ImportOptions{Mode: "append"})
+	assert.ErrorContains(t, err, "unknown import mode")
+}
+
+func TestImportData_VendorFormat(t *testing.T) {
+	ctx := context.Background()
+	uri, err := testutils.MongoContainer.ConnectionString(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, InitializeDatabase(ctx, uri, "swiftTestDB", "swiftCodes"))
+	t.Cleanup(func() {
+		_, _ = database.GetCollection().DeleteMany(ctx, struct{}{})
+	})
+
+	_, currentFilePath, _, _ := runtime.Caller(0)
+	fixture := filepath.Join(filepath.Dir(currentFilePath), "..", "pkg", "importer", "testdata", "iso20022.xml")
+
+	summary, err := ImportData(ctx, fixture)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, summary.HQAdded)
+	assert.Equal(t, 1, summary.BranchesAdded)
+}
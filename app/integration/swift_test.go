@@ -42,10 +42,10 @@ func TestSaveAndRetrieveSwiftCode(t *testing.T) {
 		Address:       "123 Test St",
 		IsHeadquarter: true,
 	}
-	_, err := service.AddSwiftCode(swiftCode)
+	_, err := service.AddSwiftCode(context.Background(), swiftCode)
 	assert.NoError(t, err, "Failed to add SWIFT code")
 
-	result, err := service.GetSwiftCodeDetails("AAAABBB1XXX")
+	result, err := service.GetSwiftCodeDetails(context.Background(), "AAAABBB1XXX")
 	assert.NoError(t, err, "Failed to retrieve SWIFT code")
 	assert.Equal(t, "Test Bank", result.BankName, "Expected bank name 'Test Bank'")
 }
@@ -55,7 +55,7 @@ func TestAddSwiftCodeEndpoint(t *testing.T) {
 
 	r := setupRouter()
 
-	swiftCode := models.SwiftCode{
+	swiftCode := models.AddSwiftCodeRequest{
 		SwiftCode:     "AAAABBB1XXX",
 		BankName:      "Test Bank",
 		CountryISO2:   "US",
@@ -0,0 +1,229 @@
+// Package audit keeps an append-only trail of mutating API calls, recording who made the call,
+// from where, which SWIFT codes it affected and how it ended.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"swift-app/internal/errors"
+	"swift-app/internal/models"
+	"swift-app/internal/requestid"
+	"swift-app/internal/timeouts"
+	"swift-app/internal/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AnonymousActor is recorded when no authenticated actor is attached to the request.
+const AnonymousActor = "anonymous"
+
+// Store persists audit entries. It only supports appending and querying.
+type Store struct {
+	DB *mongo.Collection
+}
+
+// Filter narrows down audit queries. Zero values are ignored.
+type Filter struct {
+	From  time.Time
+	To    time.Time
+	Actor string
+	Limit int64
+}
+
+// NewStore creates a Store backed by the given audit collection.
+func NewStore(db *mongo.Collection) *Store {
+	return &Store{DB: db}
+}
+
+// CollectionFor returns the audit collection that lives next to the given SWIFT collection.
+func CollectionFor(swiftCollection *mongo.Collection) *mongo.Collection {
+	return swiftCollection.Database().Collection(swiftCollection.Name() + utils.AuditCollectionSuffix)
+}
+
+// EnsureIndexes creates the indexes used by audit queries.
+func (s *Store) EnsureIndexes(ctx context.Context) error {
+	_, err := s.DB.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: utils.FieldTimestamp, Value: 1}}},
+		{Keys: bson.D{{Key: utils.FieldActor, Value: 1}, {Key: utils.FieldTimestamp, Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create audit indexes: %v", err)
+	}
+	return nil
+}
+
+// Record appends an entry to the audit trail.
+func (s *Store) Record(ctx context.Context, entry models.AuditEntry) error {
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now().UTC().Truncate(time.Millisecond)
+	}
+	if entry.SwiftCodes == nil {
+		entry.SwiftCodes = []string{}
+	}
+	ctx, cancel := timeouts.Write(ctx)
+	defer cancel()
+	if _, err := s.DB.InsertOne(ctx, entry); err != nil {
+		return fmt.Errorf("failed to record audit entry: %v", err)
+	}
+	return nil
+}
+
+// Find returns entries matching the filter, oldest first.
+func (s *Store) Find(ctx context.Context, filter Filter) ([]models.AuditEntry, error) {
+	ctx, cancel := timeouts.Read(ctx)
+	defer cancel()
+	cursor, err := s.find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := []models.AuditEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, errors.Wrap(errors.ErrInternal, "error decoding audit entries").WithCause(err)
+	}
+	return entries, nil
+}
+
+// Export streams entries matching the filter to w as JSON lines, oldest first.
+// Only the query is bounded by the read timeout; streaming the entries ends when ctx does.
+func (s *Store) Export(ctx context.Context, filter Filter, w io.Writer) error {
+	cursor, err := s.find(ctx, filter)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(context.WithoutCancel(ctx))
+
+	encoder := json.NewEncoder(w)
+	for cursor.Next(ctx) {
+		var entry models.AuditEntry
+		if err := cursor.Decode(&entry); err != nil {
+			return errors.Wrap(errors.ErrInternal, "error decoding audit entry").WithCause(err)
+		}
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return errors.Wrap(errors.ErrInternal, "error reading audit entries").WithCause(err)
+	}
+	return nil
+}
+
+func (s *Store) find(ctx context.Context, filter Filter) (*mongo.Cursor, error) {
+	query := bson.M{}
+	timeRange := bson.M{}
+	if !filter.From.IsZero() {
+		timeRange["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		timeRange["$lte"] = filter.To
+	}
+	if len(timeRange) > 0 {
+		query[utils.FieldTimestamp] = timeRange
+	}
+	if filter.Actor != "" {
+		query[utils.FieldActor] = filter.Actor
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: utils.FieldTimestamp, Value: 1}, {Key: "_id", Value: 1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(filter.Limit)
+	}
+	ctx, cancel := timeouts.Read(ctx)
+	defer cancel()
+	cursor, err := s.DB.Find(ctx, query, opts)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrInternal, "error retrieving audit entries").WithCause(err)
+	}
+	return cursor, nil
+}
+
+// SetAffectedCodes attaches the SWIFT codes affected by the current request to its audit entry.
+func SetAffectedCodes(c *gin.Context, swiftCodes ...string) {
+	c.Set(utils.ContextKeyAffectedCodes, swiftCodes)
+}
+
+// Skip is route middleware that excludes a read-only route using a non-GET method, such as
+// a batch lookup, from the audit trail.
+func Skip() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		Exclude(c)
+		c.Next()
+	}
+}
+
+// Exclude keeps the current request out of the audit trail written by Middleware, because it
+// changed nothing (e.g. a dry run) or its entry was already recorded together with the change.
+func Exclude(c *gin.Context) {
+	c.Set(utils.ContextKeyAuditSkip, true)
+}
+
+// NewEntry describes the current request as an audit entry: who sent it, from where, and which
+// SWIFT codes it affects. The outcome fields are left for the caller to fill in. The client IP is
+// taken from X-Forwarded-For only when the engine trusts the connecting proxy (http.trustedProxies).
+func NewEntry(c *gin.Context) models.AuditEntry {
+	actor := c.GetString(utils.ContextKeyActor)
+	if actor == "" {
+		actor = AnonymousActor
+	}
+	swiftCodes, _ := c.Value(utils.ContextKeyAffectedCodes).([]string)
+
+	return models.AuditEntry{
+		Actor:      actor,
+		ClientIP:   c.ClientIP(),
+		RequestID:  requestid.Get(c),
+		Method:     c.Request.Method,
+		Route:      c.FullPath(),
+		SwiftCodes: swiftCodes,
+	}
+}
+
+// errorSummary returns the code and message a client was given for err. The cause of an AppError,
+// and the text of any other error, may name hosts or driver internals, so they stay out of the trail.
+func errorSummary(err error) (code, message string) {
+	if appErr, ok := errors.As(err); ok {
+		return appErr.Code, appErr.Message
+	}
+	return errors.ErrInternal.Code, errors.ErrInternal.Message
+}
+
+// Middleware records an audit entry for every mutating request once its handler has finished.
+// Read-only requests (GET, HEAD, OPTIONS) and excluded requests are not audited.
+func Middleware(store *Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		c.Next()
+		if c.GetBool(utils.ContextKeyAuditSkip) {
+			return
+		}
+
+		entry := NewEntry(c)
+		entry.Status = c.Writer.Status()
+		entry.Outcome = models.AuditOutcomeSuccess
+		if entry.Status >= http.StatusBadRequest {
+			entry.Outcome = models.AuditOutcomeFailure
+		}
+		if last := c.Errors.Last(); last != nil {
+			entry.ErrorCode, entry.Error = errorSummary(last.Err)
+		}
+
+		// The entry is recorded even when the client has gone away.
+		if err := store.Record(context.WithoutCancel(c.Request.Context()), entry); err != nil {
+			slog.WarnContext(c.Request.Context(), "failed to record audit entry", "error", err)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// audit_test.go contains integration tests for the audit store and the audit middleware.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"swift-app/internal/errors"
+	"swift-app/internal/models"
+	"swift-app/internal/requestid"
+	testutils "swift-app/internal/testutils"
+	"swift-app/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func newStore() *Store {
+	store := NewStore(CollectionFor(testutils.Collection))
+	_, _ = store.DB.DeleteMany(context.Background(), bson.M{})
+	return store
+}
+
+func TestMiddleware_RecordsMutations(t *testing.T) {
+	store := newStore()
+
+	r := gin.New()
+	r.Use(requestid.Middleware(), func(c *gin.Context) {
+		c.Set(utils.ContextKeyActor, "alice")
+	}, Middleware(store))
+	r.GET("/codes/:code", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	r.POST("/codes/lookup", Skip(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	r.DELETE("/codes/:code", func(c *gin.Context) {
+		SetAffectedCodes(c, c.Param("code"))
+		err := errors.Wrap(errors.ErrNotFound, "headquarter %s not found, cannot delete", c.Param("code")).
+			WithCause(fmt.Errorf("server selection error: mongo-0.internal:27017"))
+		_ = c.Error(err)
+		c.JSON(errors.GetStatusCode(err), models.MessageResponse{Message: err.Error()})
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/codes/AAAABBB1XXX", nil)
+	r.ServeHTTP(w, req)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/codes/lookup", nil)
+	r.ServeHTTP(w, req)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("DELETE", "/codes/AAAABBB1XXX", nil)
+	req.Header.Set(requestid.Header, "req-1")
+	r.ServeHTTP(w, req)
+
+	entries, err := store.Find(context.Background(), Filter{})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1, "only the mutating request should be audited")
+
+	entry := entries[0]
+	assert.Equal(t, "alice", entry.Actor)
+	assert.Equal(t, "req-1", entry.RequestID)
+	assert.Equal(t, http.MethodDelete, entry.Method)
+	assert.Equal(t, "/codes/:code", entry.Route)
+	assert.Equal(t, []string{"AAAABBB1XXX"}, entry.SwiftCodes)
+	assert.Equal(t, http.StatusNotFound, entry.Status)
+	assert.Equal(t, models.AuditOutcomeFailure, entry.Outcome)
+	assert.Equal(t, "headquarter AAAABBB1XXX not found, cannot delete", entry.Error)
+	assert.Equal(t, errors.CodeNotFound, entry.ErrorCode)
+	assert.NotContains(t, entry.Error, "mongo-0.internal", "the cause is logged, not audited")
+}
+
+func TestErrorSummary(t *testing.T) {
+	code, message := errorSummary(fmt.Errorf("failed to query SWIFT codes: %w", assert.AnError))
+	assert.Equal(t, errors.CodeInternal, code)
+	assert.Equal(t, "internal server error", message)
+}
+
+func TestNewEntry_IgnoresSpoofedForwardedFor(t *testing.T) {
+	r := gin.New()
+	// The server trusts no proxy unless http.trustedProxies is set.
+	assert.NoError(t, r.SetTrustedProxies(nil))
+	var entry models.AuditEntry
+	r.DELETE("/codes/:code", func(c *gin.Context) {
+		entry = NewEntry(c)
+	})
+
+	req, _ := http.NewRequest("DELETE", "/codes/AAAABBB1XXX", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, "192.0.2.1", entry.ClientIP, "the recorded IP should not come from a client header")
+
+	assert.NoError(t, r.SetTrustedProxies([]string{"192.0.2.0/24"}))
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, "203.0.113.7", entry.ClientIP, "a trusted proxy should forward the client IP")
+}
+
+func TestFindAndExport_Filters(t *testing.T) {
+	store := newStore()
+
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	assert.NoError(t, store.Record(context.Background(), models.AuditEntry{Timestamp: base, Actor: "alice", Method: http.MethodPost}))
+	assert.NoError(t, store.Record(context.Background(), models.AuditEntry{Timestamp: base.Add(time.Hour), Actor: "bob", Method: http.MethodDelete}))
+	assert.NoError(t, store.Record(context.Background(), models.AuditEntry{Timestamp: base.Add(48 * time.Hour), Actor: "alice", Method: http.MethodDelete}))
+
+	entries, err := store.Find(context.Background(), Filter{Actor: "alice"})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	entries, err = store.Find(context.Background(), Filter{From: base.Add(30 * time.Minute), To: base.Add(24 * time.Hour)})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "bob", entries[0].Actor)
+
+	var buf bytes.Buffer
+	assert.NoError(t, store.Export(context.Background(), Filter{}, &buf))
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 3)
+
+	var first models.AuditEntry
+	assert.NoError(t, json.Unmarshal(lines[0], &first))
+	assert.Equal(t, "alice", first.Actor)
+}
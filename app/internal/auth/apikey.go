@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"swift-app/internal/errors"
+)
+
+// APIKeyHeader is the header carrying an API key. Keys are also accepted as "Authorization: ApiKey <key>".
+const APIKeyHeader = "X-API-Key"
+
+// hashPrefix marks a configured key that is given as its SHA-256 hash rather than in plain text.
+const hashPrefix = "sha256:"
+
+var errInvalidAPIKey = errors.New("invalid API key", http.StatusUnauthorized).WithCode(errors.CodeInvalidCredentials)
+
+// HashAPIKey returns the hex-encoded SHA-256 hash under which an API key is stored.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// apiKeyFromRequest extracts an API key from the X-API-Key or Authorization header.
+func apiKeyFromRequest(r *http.Request) string {
+	if key := strings.TrimSpace(r.Header.Get(APIKeyHeader)); key != "" {
+		return key
+	}
+	scheme, value, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if ok && strings.EqualFold(scheme, "ApiKey") {
+		return strings.TrimSpace(value)
+	}
+	return ""
+}
+
+// StaticKey is an API key provided through configuration.
+type StaticKey struct {
+	Owner   string
+	KeyHash string
+	Scopes  []string
+}
+
+// StaticKeys authenticates requests against API keys provided through configuration.
+type StaticKeys struct {
+	keys []StaticKey
+}
+
+// NewStaticKeys creates a provider for the given configured keys.
+func NewStaticKeys(keys []StaticKey) *StaticKeys {
+	return &StaticKeys{keys: keys}
+}
+
+// ParseStaticKeys parses API keys from configuration. Entries are separated by ';' and have the form
+// "owner|key|scope1,scope2". The key may be given in plain text or as "sha256:<hex hash>".
+func ParseStaticKeys(value string) ([]StaticKey, error) {
+	var keys []StaticKey
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, "|")
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid API key entry %q, expected owner|key|scopes", redactEntry(parts))
+		}
+		scopes, err := ParseScopes(parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid API key entry for %s: %v", parts[0], err)
+		}
+
+		keyHash := HashAPIKey(parts[1])
+		if strings.HasPrefix(parts[1], hashPrefix) {
+			keyHash = strings.ToLower(strings.TrimPrefix(parts[1], hashPrefix))
+		}
+		keys = append(keys, StaticKey{Owner: parts[0], KeyHash: keyHash, Scopes: scopes})
+	}
+	return keys, nil
+}
+
+// ParseScopes parses a comma or space separated list of known scopes.
+func ParseScopes(value string) ([]string, error) {
+	fields := strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' })
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("at least one scope is required")
+	}
+	for _, scope := range fields {
+		if _, ok := scopeRank[scope]; !ok {
+			return nil, fmt.Errorf("unknown scope %q", scope)
+		}
+	}
+	return fields, nil
+}
+
+func redactEntry(parts []string) string {
+	if len(parts) > 1 {
+		parts[1] = "***"
+	}
+	return strings.Join(parts, "|")
+}
+
+// Authenticate implements Provider. A key that matches no configured key is reported as
+// ErrNoCredentials so that the key store can still be consulted.
+func (s *StaticKeys) Authenticate(r *http.Request) (*Principal, error) {
+	key := apiKeyFromRequest(r)
+	if key == "" {
+		return nil, ErrNoCredentials
+	}
+	hash := HashAPIKey(key)
+	for _, static := range s.keys {
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(static.KeyHash)) == 1 {
+			return &Principal{Subject: static.Owner, Scopes: static.Scopes, Method: MethodAPIKey}, nil
+		}
+	}
+	return nil, ErrNoCredentials
+}
@@ -0,0 +1,110 @@
+// auth_test.go contains unit tests for scope checks, static API keys and the authentication guard.
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"swift-app/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrincipalHasScope(t *testing.T) {
+	reader := &Principal{Scopes: []string{ScopeRead}}
+	assert.True(t, reader.HasScope(ScopeRead))
+	assert.False(t, reader.HasScope(ScopeWrite))
+
+	admin := &Principal{Scopes: []string{ScopeAdmin}}
+	assert.True(t, admin.HasScope(ScopeRead), "admin should imply read")
+	assert.True(t, admin.HasScope(ScopeWrite), "admin should imply write")
+	assert.True(t, admin.HasScope(ScopeAdmin))
+}
+
+func TestParseStaticKeys(t *testing.T) {
+	keys, err := ParseStaticKeys("ci|secret-1|swift:read; ops|sha256:" + HashAPIKey("secret-2") + "|swift:read,swift:write")
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+	assert.Equal(t, HashAPIKey("secret-1"), keys[0].KeyHash)
+	assert.Equal(t, HashAPIKey("secret-2"), keys[1].KeyHash)
+	assert.Equal(t, []string{ScopeRead, ScopeWrite}, keys[1].Scopes)
+
+	_, err = ParseStaticKeys("ci|secret-1")
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret-1", "errors must not leak the key")
+
+	_, err = ParseStaticKeys("ci|secret-1|swift:everything")
+	assert.Error(t, err)
+}
+
+func setupRouter(guard *Guard) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(guard.Authenticate())
+	r.GET("/read", guard.Require(ScopeRead), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString(utils.ContextKeyActor))
+	})
+	r.DELETE("/write", guard.Require(ScopeWrite), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func TestGuard(t *testing.T) {
+	keys, err := ParseStaticKeys("reader|read-key|swift:read;writer|write-key|swift:write")
+	assert.NoError(t, err)
+	r := setupRouter(NewGuard(NewStaticKeys(keys)))
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		header string
+		value  string
+		status int
+	}{
+		{"no credentials", "GET", "/read", "", "", http.StatusUnauthorized},
+		{"unknown key", "GET", "/read", APIKeyHeader, "nope", http.StatusUnauthorized},
+		{"reader reads", "GET", "/read", APIKeyHeader, "read-key", http.StatusOK},
+		{"reader cannot write", "DELETE", "/write", APIKeyHeader, "read-key", http.StatusForbidden},
+		{"writer writes", "DELETE", "/write", "Authorization", "ApiKey write-key", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusUnauthorized {
+				assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestGuard_RecordsActor(t *testing.T) {
+	keys, _ := ParseStaticKeys("reader|read-key|swift:read")
+	r := setupRouter(NewGuard(NewStaticKeys(keys)))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/read", nil)
+	req.Header.Set(APIKeyHeader, "read-key")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, "reader", w.Body.String())
+}
+
+func TestGuard_Disabled(t *testing.T) {
+	r := setupRouter(NewGuard())
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/write", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"swift-app/internal/errors"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwk is a single key of a JSON Web Key Set. Only RSA keys (RS256) and symmetric keys (HS256) are supported.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	K   string `json:"k"`
+}
+
+type verificationKey struct {
+	alg string
+	key interface{}
+}
+
+// JWTVerifier authenticates requests carrying an HS256 or RS256 bearer token verified against a local JWKS file.
+// Scopes are read from the "scope" claim (space separated) or the "scp" claim (array).
+type JWTVerifier struct {
+	keys     map[string]verificationKey
+	issuer   string
+	audience string
+}
+
+// tokenClaims are the claims read from a bearer token.
+type tokenClaims struct {
+	jwt.RegisteredClaims
+	Scope string   `json:"scope"`
+	Scp   []string `json:"scp"`
+}
+
+// LoadJWKS reads a JSON Web Key Set from disk and creates a verifier for it.
+// Issuer and audience are checked only when non-empty.
+func LoadJWKS(path, issuer, audience string) (*JWTVerifier, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS file: %v", err)
+	}
+	return ParseJWKS(data, issuer, audience)
+}
+
+// ParseJWKS creates a verifier from a JSON Web Key Set document.
+func ParseJWKS(data []byte, issuer, audience string) (*JWTVerifier, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("invalid JWKS document: %v", err)
+	}
+
+	verifier := &JWTVerifier{keys: make(map[string]verificationKey), issuer: issuer, audience: audience}
+	for _, key := range set.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		parsed, err := parseJWK(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWKS key %q: %v", key.Kid, err)
+		}
+		verifier.keys[key.Kid] = parsed
+	}
+	if len(verifier.keys) == 0 {
+		return nil, fmt.Errorf("JWKS document contains no usable signing keys")
+	}
+	return verifier, nil
+}
+
+func parseJWK(key jwk) (verificationKey, error) {
+	switch key.Kty {
+	case "RSA":
+		if key.Alg != "" && key.Alg != jwt.SigningMethodRS256.Alg() {
+			return verificationKey{}, fmt.Errorf("unsupported algorithm %s", key.Alg)
+		}
+		n, err := base64.RawURLEncoding.DecodeString(key.N)
+		if err != nil {
+			return verificationKey{}, fmt.Errorf("invalid modulus: %v", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(key.E)
+		if err != nil {
+			return verificationKey{}, fmt.Errorf("invalid exponent: %v", err)
+		}
+		publicKey := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		return verificationKey{alg: jwt.SigningMethodRS256.Alg(), key: publicKey}, nil
+	case "oct":
+		if key.Alg != "" && key.Alg != jwt.SigningMethodHS256.Alg() {
+			return verificationKey{}, fmt.Errorf("unsupported algorithm %s", key.Alg)
+		}
+		secret, err := base64.RawURLEncoding.DecodeString(key.K)
+		if err != nil || len(secret) == 0 {
+			return verificationKey{}, fmt.Errorf("invalid symmetric key")
+		}
+		return verificationKey{alg: jwt.SigningMethodHS256.Alg(), key: secret}, nil
+	default:
+		return verificationKey{}, fmt.Errorf("unsupported key type %s", key.Kty)
+	}
+}
+
+// Authenticate implements Provider.
+func (v *JWTVerifier) Authenticate(r *http.Request) (*Principal, error) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return nil, ErrNoCredentials
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	}
+	if v.issuer != "" {
+		options = append(options, jwt.WithIssuer(v.issuer))
+	}
+	if v.audience != "" {
+		options = append(options, jwt.WithAudience(v.audience))
+	}
+
+	var claims tokenClaims
+	_, err := jwt.ParseWithClaims(strings.TrimSpace(token), &claims, v.keyFor, options...)
+	if err != nil {
+		return nil, errors.New("invalid bearer token: "+err.Error(), http.StatusUnauthorized).WithCode(errors.CodeInvalidCredentials)
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("invalid bearer token: missing subject", http.StatusUnauthorized).WithCode(errors.CodeInvalidCredentials)
+	}
+
+	scopes := claims.Scp
+	if claims.Scope != "" {
+		scopes = strings.Fields(claims.Scope)
+	}
+	return &Principal{Subject: claims.Subject, Scopes: scopes, Method: MethodJWT}, nil
+}
+
+// keyFor selects the verification key by the token's "kid" header and makes sure the key
+// is used only with its own algorithm, so an RSA public key can never verify an HS256 token.
+func (v *JWTVerifier) keyFor(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := v.keys[kid]
+	if !ok && kid == "" && len(v.keys) == 1 {
+		for _, only := range v.keys {
+			key, ok = only, true
+		}
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if token.Method.Alg() != key.alg {
+		return nil, fmt.Errorf("algorithm %s does not match key %q", token.Method.Alg(), kid)
+	}
+	return key.key, nil
+}
@@ -0,0 +1,92 @@
+// jwt_test.go contains unit tests for JWKS parsing and bearer token verification.
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+var hmacSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func testJWKS(t *testing.T) (*rsa.PrivateKey, []byte) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	n := base64.RawURLEncoding.EncodeToString(privateKey.N.Bytes())
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(privateKey.E)).Bytes())
+	k := base64.RawURLEncoding.EncodeToString(hmacSecret)
+	return privateKey, []byte(fmt.Sprintf(`{"keys":[
+		{"kty":"RSA","kid":"rsa-1","alg":"RS256","use":"sig","n":%q,"e":%q},
+		{"kty":"oct","kid":"hmac-1","alg":"HS256","k":%q}
+	]}`, n, e, k))
+}
+
+func signedRequest(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) *http.Request {
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	assert.NoError(t, err)
+
+	req, _ := http.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer "+signed)
+	return req
+}
+
+func TestJWTVerifier(t *testing.T) {
+	privateKey, jwks := testJWKS(t)
+	verifier, err := ParseJWKS(jwks, "https://issuer.example", "swift-api")
+	assert.NoError(t, err)
+
+	valid := jwt.MapClaims{
+		"sub":   "payments-pipeline",
+		"iss":   "https://issuer.example",
+		"aud":   "swift-api",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": "swift:read swift:write",
+	}
+
+	principal, err := verifier.Authenticate(signedRequest(t, jwt.SigningMethodRS256, "rsa-1", privateKey, valid))
+	assert.NoError(t, err)
+	assert.Equal(t, "payments-pipeline", principal.Subject)
+	assert.True(t, principal.HasScope(ScopeWrite))
+	assert.Equal(t, MethodJWT, principal.Method)
+
+	principal, err = verifier.Authenticate(signedRequest(t, jwt.SigningMethodHS256, "hmac-1", hmacSecret, valid))
+	assert.NoError(t, err)
+	assert.Equal(t, "payments-pipeline", principal.Subject)
+
+	expired := jwt.MapClaims{"sub": "x", "iss": "https://issuer.example", "aud": "swift-api", "exp": time.Now().Add(-time.Hour).Unix()}
+	_, err = verifier.Authenticate(signedRequest(t, jwt.SigningMethodRS256, "rsa-1", privateKey, expired))
+	assert.Error(t, err)
+
+	wrongAudience := jwt.MapClaims{"sub": "x", "iss": "https://issuer.example", "aud": "other", "exp": time.Now().Add(time.Hour).Unix()}
+	_, err = verifier.Authenticate(signedRequest(t, jwt.SigningMethodRS256, "rsa-1", privateKey, wrongAudience))
+	assert.Error(t, err)
+
+	_, err = verifier.Authenticate(signedRequest(t, jwt.SigningMethodHS256, "rsa-1", hmacSecret, valid))
+	assert.Error(t, err, "an HS256 token must not be accepted for an RSA key")
+
+	req, _ := http.NewRequest("GET", "/", nil)
+	_, err = verifier.Authenticate(req)
+	assert.Equal(t, ErrNoCredentials, err)
+}
+
+func TestParseJWKS_Invalid(t *testing.T) {
+	_, err := ParseJWKS([]byte(`{"keys":[]}`), "", "")
+	assert.Error(t, err)
+
+	_, err = ParseJWKS([]byte(`{"keys":[{"kty":"EC","kid":"ec-1"}]}`), "", "")
+	assert.Error(t, err)
+
+	_, err = ParseJWKS([]byte(`not json`), "", "")
+	assert.Error(t, err)
+}
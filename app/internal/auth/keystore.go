@@ -0,0 +1,245 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"swift-app/internal/errors"
+	"swift-app/internal/models"
+	"swift-app/internal/timeouts"
+	"swift-app/internal/utils"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// keyPrefix marks keys issued by the key store, so they are recognizable in logs and secret scanners.
+const keyPrefix = "swk_"
+
+// displayPrefixLength is the number of leading key characters kept in plain text to identify a key.
+const displayPrefixLength = len(keyPrefix) + 6
+
+// lastUsedResolution limits how often the last-used time of a key is written.
+const lastUsedResolution = time.Minute
+
+// KeyStore issues, rotates and revokes API keys stored in MongoDB and authenticates requests against them.
+// Only the SHA-256 hash of a key is stored; the key itself is returned once, when it is issued.
+type KeyStore struct {
+	DB *mongo.Collection
+}
+
+// NewKeyStore creates a KeyStore backed by the given API key collection.
+func NewKeyStore(db *mongo.Collection) *KeyStore {
+	return &KeyStore{DB: db}
+}
+
+// CollectionFor returns the API key collection that lives next to the given SWIFT collection.
+func CollectionFor(swiftCollection *mongo.Collection) *mongo.Collection {
+	return swiftCollection.Database().Collection(swiftCollection.Name() + utils.APIKeyCollectionSuffix)
+}
+
+// EnsureIndexes creates the unique index on key hashes.
+func (s *KeyStore) EnsureIndexes(ctx context.Context) error {
+	_, err := s.DB.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: utils.FieldKeyHash, Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create API key index: %v", err)
+	}
+	return nil
+}
+
+// Authenticate implements Provider.
+func (s *KeyStore) Authenticate(r *http.Request) (*Principal, error) {
+	key := apiKeyFromRequest(r)
+	if key == "" {
+		return nil, ErrNoCredentials
+	}
+
+	ctx, cancel := timeouts.Read(r.Context())
+	defer cancel()
+	var apiKey models.APIKey
+	err := s.DB.FindOne(ctx, bson.M{utils.FieldKeyHash: HashAPIKey(key)}).Decode(&apiKey)
+	if err == mongo.ErrNoDocuments {
+		return nil, errInvalidAPIKey
+	}
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrInternal, "error looking up API key").WithCause(err)
+	}
+	if apiKey.RevokedAt != nil {
+		return nil, errors.New("API key has been revoked", http.StatusUnauthorized).WithCode(errors.CodeAPIKeyRevoked)
+	}
+	if apiKey.ExpiresAt != nil && time.Now().After(*apiKey.ExpiresAt) {
+		return nil, errors.New("API key has expired", http.StatusUnauthorized).WithCode(errors.CodeAPIKeyExpired)
+	}
+
+	s.touch(r.Context(), apiKey)
+	return &Principal{
+		Subject:   apiKey.Owner,
+		Scopes:    apiKey.Scopes,
+		Method:    MethodAPIKey,
+		KeyID:     apiKey.ID,
+		RateLimit: apiKey.RateLimit,
+	}, nil
+}
+
+// touch records the last-used time of a key, at most once per lastUsedResolution.
+func (s *KeyStore) touch(ctx context.Context, apiKey models.APIKey) {
+	now := time.Now().UTC()
+	if apiKey.LastUsedAt != nil && now.Sub(*apiKey.LastUsedAt) < lastUsedResolution {
+		return
+	}
+	ctx, cancel := timeouts.Write(ctx)
+	defer cancel()
+	_, err := s.DB.UpdateOne(ctx,
+		bson.M{"_id": apiKey.ID},
+		bson.M{"$set": bson.M{utils.FieldLastUsedAt: now}})
+	if err != nil {
+		slog.WarnContext(ctx, "failed to record last use of API key", "key_id", apiKey.ID, "error", err)
+	}
+}
+
+// Create issues a new API key. The returned key is the only time the plain-text key is available.
+func (s *KeyStore) Create(ctx context.Context, request models.CreateAPIKeyRequest) (*models.APIKeyCreatedResponse, error) {
+	if request.Owner == "" {
+		return nil, errors.Wrap(errors.ErrBadRequest, "owner is required").WithFields("/owner")
+	}
+	for _, scope := range request.Scopes {
+		if _, ok := scopeRank[scope]; !ok {
+			return nil, errors.Wrap(errors.ErrBadRequest, "unknown scope '%s'", scope).
+				WithCode(errors.CodeInvalidScope).WithFields("/scopes")
+		}
+	}
+	if len(request.Scopes) == 0 {
+		return nil, errors.Wrap(errors.ErrBadRequest, "at least one scope is required").
+			WithCode(errors.CodeInvalidScope).WithFields("/scopes")
+	}
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(time.Now()) {
+		return nil, errors.Wrap(errors.ErrBadRequest, "expiresAt must be in the future").WithFields("/expiresAt")
+	}
+	if request.RateLimit < 0 {
+		return nil, errors.Wrap(errors.ErrBadRequest, "rateLimit must not be negative").WithFields("/rateLimit")
+	}
+
+	key, err := generateKey()
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrInternal, "error generating API key").WithCause(err)
+	}
+	apiKey := models.APIKey{
+		ID:        primitive.NewObjectID().Hex(),
+		Owner:     request.Owner,
+		Prefix:    key[:displayPrefixLength],
+		KeyHash:   HashAPIKey(key),
+		Scopes:    request.Scopes,
+		RateLimit: request.RateLimit,
+		CreatedAt: time.Now().UTC().Truncate(time.Millisecond),
+		ExpiresAt: request.ExpiresAt,
+	}
+	ctx, cancel := timeouts.Write(ctx)
+	defer cancel()
+	if _, err := s.DB.InsertOne(ctx, apiKey); err != nil {
+		return nil, errors.Wrap(errors.ErrInternal, "error storing API key").WithCause(err)
+	}
+	return &models.APIKeyCreatedResponse{Key: key, APIKey: apiKey}, nil
+}
+
+// List returns all stored keys, including revoked and expired ones, oldest first.
+func (s *KeyStore) List(ctx context.Context) ([]models.APIKey, error) {
+	ctx, cancel := timeouts.Read(ctx)
+	defer cancel()
+	cursor, err := s.DB.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: utils.FieldCreatedAt, Value: 1}}))
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrInternal, "error retrieving API keys").WithCause(err)
+	}
+	defer cursor.Close(ctx)
+
+	keys := []models.APIKey{}
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, errors.Wrap(errors.ErrInternal, "error decoding API keys").WithCause(err)
+	}
+	return keys, nil
+}
+
+// Rotate replaces the secret of an active key while keeping its identity, owner, scopes and limits.
+// The old secret stops working immediately.
+func (s *KeyStore) Rotate(ctx context.Context, id string) (*models.APIKeyCreatedResponse, error) {
+	ctx, cancel := timeouts.Write(ctx)
+	defer cancel()
+	existing, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.RevokedAt != nil {
+		return nil, errors.Wrap(errors.ErrConflict, "API key %s has been revoked and cannot be rotated", id).
+			WithCode(errors.CodeAPIKeyRevoked)
+	}
+
+	key, err := generateKey()
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrInternal, "error generating API key").WithCause(err)
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	var rotated models.APIKey
+	err = s.DB.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, utils.FieldRevokedAt: bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{
+			utils.FieldKeyHash:   HashAPIKey(key),
+			utils.FieldPrefix:    key[:displayPrefixLength],
+			utils.FieldRotatedAt: now,
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&rotated)
+	if err == mongo.ErrNoDocuments {
+		return nil, errors.Wrap(errors.ErrConflict, "API key %s has been revoked and cannot be rotated", id).
+			WithCode(errors.CodeAPIKeyRevoked)
+	}
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrInternal, "error rotating API key %s", id).WithCause(err)
+	}
+	return &models.APIKeyCreatedResponse{Key: key, APIKey: rotated}, nil
+}
+
+// Revoke permanently disables a key. Revoking an already revoked key is a no-op.
+func (s *KeyStore) Revoke(ctx context.Context, id string) (*models.APIKey, error) {
+	ctx, cancel := timeouts.Write(ctx)
+	defer cancel()
+	if _, err := s.get(ctx, id); err != nil {
+		return nil, err
+	}
+
+	_, err := s.DB.UpdateOne(ctx,
+		bson.M{"_id": id, utils.FieldRevokedAt: bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{utils.FieldRevokedAt: time.Now().UTC().Truncate(time.Millisecond)}})
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrInternal, "error revoking API key %s", id).WithCause(err)
+	}
+	return s.get(ctx, id)
+}
+
+func (s *KeyStore) get(ctx context.Context, id string) (*models.APIKey, error) {
+	var apiKey models.APIKey
+	err := s.DB.FindOne(ctx, bson.M{"_id": id}).Decode(&apiKey)
+	if err == mongo.ErrNoDocuments {
+		return nil, errors.Wrap(errors.ErrNotFound, "API key %s not found", id).WithCode(errors.CodeAPIKeyNotFound)
+	}
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrInternal, "error retrieving API key %s", id).WithCause(err)
+	}
+	return &apiKey, nil
+}
+
+// generateKey returns a new random API key with 256 bits of entropy.
+func generateKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return keyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
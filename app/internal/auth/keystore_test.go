@@ -0,0 +1,106 @@
+// keystore_test.go contains integration tests for issuing, rotating, revoking and authenticating API keys stored in MongoDB.
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"swift-app/internal/errors"
+	"swift-app/internal/models"
+	testutils "swift-app/internal/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestKeyStoreAuthenticate(t *testing.T) {
+	store := NewKeyStore(CollectionFor(testutils.Collection))
+	_, _ = store.DB.DeleteMany(context.Background(), bson.M{})
+
+	past := time.Now().Add(-time.Hour)
+	_, err := store.DB.InsertMany(context.Background(), []interface{}{
+		models.APIKey{ID: "active", Owner: "ops", KeyHash: HashAPIKey("active-key"), Scopes: []string{ScopeWrite}, CreatedAt: time.Now()},
+		models.APIKey{ID: "revoked", Owner: "ops", KeyHash: HashAPIKey("revoked-key"), Scopes: []string{ScopeWrite}, CreatedAt: time.Now(), RevokedAt: &past},
+		models.APIKey{ID: "expired", Owner: "ops", KeyHash: HashAPIKey("expired-key"), Scopes: []string{ScopeWrite}, CreatedAt: time.Now(), ExpiresAt: &past},
+	})
+	assert.NoError(t, err)
+
+	request := func(key string) *http.Request {
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.Header.Set(APIKeyHeader, key)
+		return req
+	}
+
+	principal, err := store.Authenticate(request("active-key"))
+	assert.NoError(t, err)
+	assert.Equal(t, "ops", principal.Subject)
+	assert.Equal(t, "active", principal.KeyID)
+
+	_, err = store.Authenticate(request("revoked-key"))
+	assert.ErrorContains(t, err, "revoked")
+
+	_, err = store.Authenticate(request("expired-key"))
+	assert.ErrorContains(t, err, "expired")
+
+	_, err = store.Authenticate(request("unknown-key"))
+	assert.Error(t, err)
+}
+
+func TestKeyStoreLifecycle(t *testing.T) {
+	store := NewKeyStore(CollectionFor(testutils.Collection))
+	_, _ = store.DB.DeleteMany(context.Background(), bson.M{})
+
+	request := func(key string) *http.Request {
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.Header.Set(APIKeyHeader, key)
+		return req
+	}
+
+	expiresAt := time.Now().Add(24 * time.Hour)
+	created, err := store.Create(context.Background(), models.CreateAPIKeyRequest{
+		Owner: "partner-a", Scopes: []string{ScopeRead}, ExpiresAt: &expiresAt, RateLimit: 120,
+	})
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(created.Key, keyPrefix))
+	assert.Equal(t, created.Key[:displayPrefixLength], created.Prefix)
+
+	var stored bson.M
+	assert.NoError(t, store.DB.FindOne(context.Background(), bson.M{"_id": created.ID}).Decode(&stored))
+	assert.NotContains(t, stored, "key", "the plain-text key must not be stored")
+	assert.Equal(t, HashAPIKey(created.Key), stored["keyHash"])
+
+	principal, err := store.Authenticate(request(created.Key))
+	assert.NoError(t, err)
+	assert.Equal(t, 120, principal.RateLimit)
+
+	keys, err := store.List(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, keys, 1)
+	assert.NotNil(t, keys[0].LastUsedAt, "authentication should record the last-used time")
+
+	rotated, err := store.Rotate(context.Background(), created.ID)
+	assert.NoError(t, err)
+	assert.NotEqual(t, created.Key, rotated.Key)
+	_, err = store.Authenticate(request(created.Key))
+	assert.Error(t, err, "the old secret should stop working after rotation")
+	_, err = store.Authenticate(request(rotated.Key))
+	assert.NoError(t, err)
+
+	revoked, err := store.Revoke(context.Background(), created.ID)
+	assert.NoError(t, err)
+	assert.NotNil(t, revoked.RevokedAt)
+	_, err = store.Authenticate(request(rotated.Key))
+	assert.Error(t, err)
+
+	_, err = store.Rotate(context.Background(), created.ID)
+	assert.Equal(t, http.StatusConflict, errors.GetStatusCode(err))
+
+	_, err = store.Revoke(context.Background(), "missing")
+	assert.Equal(t, http.StatusNotFound, errors.GetStatusCode(err))
+
+	_, err = store.Create(context.Background(), models.CreateAPIKeyRequest{Owner: "partner-b", Scopes: []string{"swift:everything"}})
+	assert.Equal(t, http.StatusBadRequest, errors.GetStatusCode(err))
+}
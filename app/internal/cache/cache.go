@@ -0,0 +1,204 @@
+// Package cache provides the read-through cache used by the SWIFT code service: an in-process
+// LRU with a TTL. Cached values are copied on the way in and out, so callers never share them.
+//
+// Every invalidation bumps a generation counter. A value loaded from the store is only cached if no
+// invalidation happened while it was being loaded, so a reader racing a mutation never caches stale data.
+// Invalidation only reaches this process: changes made by another instance or by
+// the import command become visible here once the TTL has passed, which is why the default TTL is short.
+package cache
+
+import (
+	"container/list"
+	"swift-app/internal/models"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Cloner is implemented by cached values. Clone returns a deep copy, so a caller can modify the
+// value it got without changing the cached entry.
+type Cloner[T any] interface {
+	Clone() T
+}
+
+// Defaults used by the SWIFT code service when no cache configuration is given.
+// DefaultTTL bounds how long a change written by another process can stay hidden behind a cached entry.
+const (
+	DefaultSize = 10000
+	DefaultTTL  = 30 * time.Second
+)
+
+// Config configures a Cache. A zero Size disables caching, a zero TTL defaults to DefaultTTL.
+type Config struct {
+	Size int
+	TTL  time.Duration
+}
+
+type entry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+// Cache is a size-bounded LRU cache with per-entry expiry. It is safe for concurrent use.
+type Cache struct {
+	config Config
+	now    func() time.Time
+
+	mu         sync.Mutex
+	order      *list.List
+	items      map[string]*list.Element
+	generation uint64
+
+	hits          atomic.Int64
+	misses        atomic.Int64
+	evictions     atomic.Int64
+	invalidations atomic.Int64
+}
+
+// New creates a Cache.
+func New(config Config) *Cache {
+	if config.TTL <= 0 {
+		config.TTL = DefaultTTL
+	}
+	return &Cache{
+		config: config,
+		now:    time.Now,
+		order:  list.New(),
+		items:  make(map[string]*list.Element),
+	}
+}
+
+// Enabled reports whether the cache stores anything.
+func (c *Cache) Enabled() bool {
+	return c.config.Size > 0
+}
+
+// Generation returns the current invalidation generation, to be passed to Set.
+func (c *Cache) Generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+// Get returns a copy of the cached value for key.
+func Get[T Cloner[T]](c *Cache, key string) (T, bool) {
+	var zero T
+	if !c.Enabled() {
+		return zero, false
+	}
+
+	c.mu.Lock()
+	if element, ok := c.items[key]; ok {
+		e := element.Value.(*entry)
+		if c.now().Before(e.expiresAt) {
+			c.order.MoveToFront(element)
+			c.mu.Unlock()
+			if value, ok := e.value.(T); ok {
+				c.hits.Add(1)
+				return value.Clone(), true
+			}
+			c.misses.Add(1)
+			return zero, false
+		}
+		c.removeElement(element)
+	}
+	c.mu.Unlock()
+
+	c.misses.Add(1)
+	return zero, false
+}
+
+// Set caches a copy of value under key unless the cache was invalidated since generation was read.
+func Set[T Cloner[T]](c *Cache, key string, value T, generation uint64) {
+	if !c.Enabled() {
+		return
+	}
+	c.store(key, value.Clone(), generation)
+}
+
+// Load returns the cached value for key or calls load and caches its result. Errors are not cached.
+func Load[T Cloner[T]](c *Cache, key string, load func() (T, error)) (T, error) {
+	if value, ok := Get[T](c, key); ok {
+		return value, nil
+	}
+	generation := c.Generation()
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+	Set(c, key, value, generation)
+	return value, nil
+}
+
+// Invalidate removes keys from the cache.
+func (c *Cache) Invalidate(keys ...string) {
+	c.mu.Lock()
+	c.generation++
+	for _, key := range keys {
+		if element, ok := c.items[key]; ok {
+			c.removeElement(element)
+		}
+	}
+	c.mu.Unlock()
+	c.invalidations.Add(1)
+}
+
+// Purge removes everything from the cache.
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	c.generation++
+	c.order.Init()
+	c.items = make(map[string]*list.Element)
+	c.mu.Unlock()
+	c.invalidations.Add(1)
+}
+
+// Stats returns the hit and miss counters of the cache.
+func (c *Cache) Stats() models.CacheStats {
+	c.mu.Lock()
+	entries := len(c.items)
+	c.mu.Unlock()
+
+	stats := models.CacheStats{
+		Enabled:       c.Enabled(),
+		Entries:       entries,
+		Capacity:      c.config.Size,
+		TTLSeconds:    int64(c.config.TTL.Seconds()),
+		Hits:          c.hits.Load(),
+		Misses:        c.misses.Load(),
+		Evictions:     c.evictions.Load(),
+		Invalidations: c.invalidations.Load(),
+	}
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		stats.HitRatio = float64(stats.Hits) / float64(lookups)
+	}
+	return stats
+}
+
+// store caches value under key if generation is still current.
+func (c *Cache) store(key string, value interface{}, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		return
+	}
+
+	expiresAt := c.now().Add(c.config.TTL)
+	if element, ok := c.items[key]; ok {
+		element.Value = &entry{key: key, value: value, expiresAt: expiresAt}
+		c.order.MoveToFront(element)
+		return
+	}
+	c.items[key] = c.order.PushFront(&entry{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.config.Size {
+		c.removeElement(c.order.Back())
+		c.evictions.Add(1)
+	}
+}
+
+// removeElement drops an entry. Callers must hold c.mu.
+func (c *Cache) removeElement(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.items, element.Value.(*entry).key)
+}
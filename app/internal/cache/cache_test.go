@@ -0,0 +1,126 @@
+// cache_test.go contains unit tests for the LRU/TTL cache.
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// text and number are cached values whose copies are themselves.
+type text string
+
+func (t text) Clone() text { return t }
+
+type number int
+
+func (n number) Clone() number { return n }
+
+// names is a cached value that shares memory unless it is copied.
+type names []string
+
+func (n names) Clone() names { return append(names(nil), n...) }
+
+func TestLoad_CachesValues(t *testing.T) {
+	c := New(Config{Size: 10})
+	calls := 0
+	load := func() (text, error) {
+		calls++
+		return "value", nil
+	}
+
+	for i := 0; i < 3; i++ {
+		value, err := Load(c, "key", load)
+		assert.NoError(t, err)
+		assert.Equal(t, text("value"), value)
+	}
+	assert.Equal(t, 1, calls)
+
+	stats := c.Stats()
+	assert.Equal(t, int64(2), stats.Hits)
+	assert.Equal(t, int64(1), stats.Misses)
+	assert.Equal(t, 1, stats.Entries)
+}
+
+func TestSet_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := New(Config{Size: 2})
+	Set(c, "a", number(1), c.Generation())
+	Set(c, "b", number(2), c.Generation())
+	_, _ = Get[number](c, "a")
+	Set(c, "c", number(3), c.Generation())
+
+	_, ok := Get[number](c, "b")
+	assert.False(t, ok, "b was least recently used and should be evicted")
+	value, ok := Get[number](c, "a")
+	assert.True(t, ok)
+	assert.Equal(t, number(1), value)
+	assert.Equal(t, int64(1), c.Stats().Evictions)
+}
+
+func TestGet_ExpiresEntries(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := New(Config{Size: 10, TTL: time.Minute})
+	c.now = func() time.Time { return now }
+
+	Set(c, "key", text("value"), c.Generation())
+	_, ok := Get[text](c, "key")
+	assert.True(t, ok)
+
+	now = now.Add(time.Minute)
+	_, ok = Get[text](c, "key")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Stats().Entries)
+}
+
+func TestSet_SkipsValuesLoadedBeforeInvalidation(t *testing.T) {
+	c := New(Config{Size: 10})
+	generation := c.Generation()
+	c.Invalidate("key")
+	Set(c, "key", text("stale"), generation)
+
+	_, ok := Get[text](c, "key")
+	assert.False(t, ok, "a value read before an invalidation must not be cached")
+}
+
+func TestInvalidateAndPurge(t *testing.T) {
+	c := New(Config{Size: 10})
+	Set(c, "a", number(1), c.Generation())
+	Set(c, "b", number(2), c.Generation())
+
+	c.Invalidate("a")
+	_, ok := Get[number](c, "a")
+	assert.False(t, ok)
+	_, ok = Get[number](c, "b")
+	assert.True(t, ok)
+
+	c.Purge()
+	_, ok = Get[number](c, "b")
+	assert.False(t, ok)
+	assert.Equal(t, int64(2), c.Stats().Invalidations)
+}
+
+func TestGet_ReturnsCopies(t *testing.T) {
+	c := New(Config{Size: 10})
+	value := names{"a", "b"}
+	Set(c, "key", value, c.Generation())
+	value[0] = "changed after Set"
+
+	cached, ok := Get[names](c, "key")
+	assert.True(t, ok)
+	assert.Equal(t, names{"a", "b"}, cached)
+	cached[1] = "changed after Get"
+	_ = append(cached[:1], "appended after Get")
+
+	cached, _ = Get[names](c, "key")
+	assert.Equal(t, names{"a", "b"}, cached, "changes to a returned value must not reach the cache")
+}
+
+func TestDisabledCache(t *testing.T) {
+	c := New(Config{})
+	Set(c, "key", text("value"), c.Generation())
+
+	_, ok := Get[text](c, "key")
+	assert.False(t, ok)
+	assert.False(t, c.Stats().Enabled)
+}
@@ -0,0 +1,261 @@
+// Package config holds the typed configuration of the Swift App. Settings are loaded from
+// defaults, then an optional YAML or TOML file, then environment variables and finally command
+// line flags, each source overriding the previous one. The result is validated as a whole, so
+// every invalid setting is reported at once.
+package config
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"reflect"
+	"strings"
+	"swift-app/internal/auth"
+	"swift-app/internal/cache"
+	"swift-app/internal/logging"
+	"swift-app/internal/problem"
+	"swift-app/internal/timeouts"
+	"swift-app/internal/tracing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Redacted replaces secrets when the configuration is printed.
+const Redacted = "[REDACTED]"
+
+// Config is the complete application configuration. Every setting has a key made of the yaml tags
+// on its path (e.g. "http.port"), which is also its flag name; the env tag names its environment
+// variable. Settings tagged secret are redacted when printed; secret:"url" only hides the password.
+type Config struct {
+	Mongo     Mongo     `yaml:"mongo"`
+	HTTP      HTTP      `yaml:"http"`
+	Import    Import    `yaml:"import"`
+	Auth      Auth      `yaml:"auth"`
+	Cache     Cache     `yaml:"cache"`
+	RateLimit RateLimit `yaml:"rateLimit"`
+	Timeouts  Timeouts  `yaml:"timeouts"`
+	Logging   Logging   `yaml:"logging"`
+	Tracing   Tracing   `yaml:"tracing"`
+}
+
+// Mongo configures the MongoDB connection.
+type Mongo struct {
+	URI        string `yaml:"uri" env:"MONGO_URI" secret:"url" usage:"MongoDB connection URI"`
+	Database   string `yaml:"database" env:"MONGO_DB" usage:"MongoDB database name"`
+	Collection string `yaml:"collection" env:"MONGO_COLLECTION" usage:"MongoDB collection of SWIFT codes"`
+}
+
+// HTTP configures the HTTP server.
+type HTTP struct {
+	Host            string        `yaml:"host" env:"HOST" usage:"interface the server binds to; empty binds every interface"`
+	Port            int           `yaml:"port" env:"PORT" usage:"port the server listens on"`
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout" env:"SHUTDOWN_TIMEOUT" usage:"how long in-flight requests may take to finish on shutdown"`
+	ErrorFormat     string        `yaml:"errorFormat" env:"ERROR_FORMAT" usage:"error response format: problem or legacy"`
+	TrustedProxies  string        `yaml:"trustedProxies" env:"TRUSTED_PROXIES" usage:"comma-separated IPs or CIDR ranges of reverse proxies whose X-Forwarded-For header is trusted; empty trusts none"`
+}
+
+// Address returns the host:port address the server listens on.
+func (h HTTP) Address() string {
+	return fmt.Sprintf("%s:%d", h.Host, h.Port)
+}
+
+// Proxies returns the trusted proxies, or nil when none are trusted. Without trusted proxies the
+// client IP used for rate limiting and the audit log is the address of the connection.
+func (h HTTP) Proxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(h.TrustedProxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
+// Import configures the SWIFT data import.
+type Import struct {
+	CSVPath string `yaml:"csvPath" env:"CSV_PATH" usage:"path to the file with SWIFT data: CSV, BIC Plus or ISO 20022 XML"`
+	Profile string `yaml:"profile" env:"IMPORT_PROFILE" usage:"path to a YAML or JSON profile mapping the columns of CSV files; empty reads the default layout"`
+}
+
+// Auth configures how API clients authenticate.
+type Auth struct {
+	Disabled    bool   `yaml:"disabled" env:"AUTH_DISABLED" usage:"leave all API routes open (local development only)"`
+	APIKeys     string `yaml:"apiKeys" env:"AUTH_API_KEYS" secret:"true" usage:"static API keys as owner|key|scope1,scope2 entries separated by ;"`
+	JWKSPath    string `yaml:"jwksPath" env:"AUTH_JWKS_PATH" usage:"path to a JWKS file used to verify bearer tokens"`
+	JWTIssuer   string `yaml:"jwtIssuer" env:"AUTH_JWT_ISSUER" usage:"required iss claim of bearer tokens"`
+	JWTAudience string `yaml:"jwtAudience" env:"AUTH_JWT_AUDIENCE" usage:"required aud claim of bearer tokens"`
+}
+
+// Cache configures the lookup cache.
+type Cache struct {
+	Size int           `yaml:"size" env:"CACHE_SIZE" usage:"maximum number of cached lookups (0 disables caching)"`
+	TTL  time.Duration `yaml:"ttl" env:"CACHE_TTL" usage:"how long cached entries live"`
+}
+
+// RateLimit configures per-client request limits. Zero disables a limit.
+type RateLimit struct {
+	ReadPerMinute   int   `yaml:"readPerMinute" env:"RATE_LIMIT_READ_PER_MINUTE" usage:"read requests per minute per client"`
+	WritePerMinute  int   `yaml:"writePerMinute" env:"RATE_LIMIT_WRITE_PER_MINUTE" usage:"write requests per minute per client"`
+	ImportPerMinute int   `yaml:"importPerMinute" env:"RATE_LIMIT_IMPORT_PER_MINUTE" usage:"import and export requests per minute per client"`
+	IPPerMinute     int   `yaml:"ipPerMinute" env:"RATE_LIMIT_IP_PER_MINUTE" usage:"API requests per minute per client IP, counted before authentication"`
+	DailyQuota      int64 `yaml:"dailyQuota" env:"RATE_LIMIT_DAILY_QUOTA" usage:"requests per client per UTC day"`
+}
+
+// Timeouts configures the database operation timeouts. Zero disables a timeout.
+type Timeouts struct {
+	Read        time.Duration `yaml:"read" env:"TIMEOUT_READ" usage:"timeout of database reads"`
+	Write       time.Duration `yaml:"write" env:"TIMEOUT_WRITE" usage:"timeout of database writes"`
+	ImportBatch time.Duration `yaml:"importBatch" env:"TIMEOUT_IMPORT_BATCH" usage:"timeout of each batch of imported records"`
+}
+
+// Operations returns the timeouts in the form used by the timeouts package.
+func (t Timeouts) Operations() timeouts.Config {
+	return timeouts.Config{Read: t.Read, Write: t.Write, ImportBatch: t.ImportBatch}
+}
+
+// Logging configures the structured logs.
+type Logging struct {
+	Level  string `yaml:"level" env:"LOG_LEVEL" usage:"minimum log level: debug, info, warn or error"`
+	Format string `yaml:"format" env:"LOG_FORMAT" usage:"log output format: json or text"`
+}
+
+// Tracing configures OpenTelemetry tracing. The OTLP exporter reads its own OTEL_EXPORTER_OTLP_* variables.
+type Tracing struct {
+	Exporter    string `yaml:"exporter" env:"OTEL_TRACES_EXPORTER" usage:"span exporter: none, stdout or otlp"`
+	ServiceName string `yaml:"serviceName" env:"OTEL_SERVICE_NAME" usage:"service name reported in spans"`
+}
+
+// Default returns the configuration used for settings no source sets.
+func Default() Config {
+	operations := timeouts.DefaultConfig()
+	return Config{
+		Mongo: Mongo{
+			URI:        "mongodb://localhost:27017",
+			Database:   "swiftDB",
+			Collection: "swiftCodes",
+		},
+		HTTP: HTTP{
+			Port:            8080,
+			ShutdownTimeout: 15 * time.Second,
+			ErrorFormat:     string(problem.FormatProblem),
+		},
+		Import: Import{
+			CSVPath: "./pkg/data/Interns_2025_SWIFT_CODES.csv",
+		},
+		Cache: Cache{
+			Size: cache.DefaultSize,
+			TTL:  cache.DefaultTTL,
+		},
+		RateLimit: RateLimit{
+			ReadPerMinute:   600,
+			WritePerMinute:  60,
+			ImportPerMinute: 5,
+			IPPerMinute:     1200,
+			DailyQuota:      100000,
+		},
+		Timeouts: Timeouts{
+			Read:        operations.Read,
+			Write:       operations.Write,
+			ImportBatch: operations.ImportBatch,
+		},
+		Logging: Logging{
+			Level:  "info",
+			Format: logging.FormatJSON,
+		},
+		Tracing: Tracing{
+			Exporter:    tracing.ExporterNone,
+			ServiceName: "swift-app",
+		},
+	}
+}
+
+// Validate checks every setting and returns an *Error listing all invalid ones, or nil.
+func (c Config) Validate() error {
+	var problems problems
+	if c.Mongo.URI == "" {
+		problems.add("mongo.uri", "", "must be set")
+	} else if u, err := url.Parse(c.Mongo.URI); err != nil || (u.Scheme != "mongodb" && u.Scheme != "mongodb+srv") {
+		problems.add("mongo.uri", "", "must be a mongodb:// or mongodb+srv:// URI")
+	}
+	problems.required("mongo.database", c.Mongo.Database)
+	problems.required("mongo.collection", c.Mongo.Collection)
+
+	if c.HTTP.Port < 1 || c.HTTP.Port > 65535 {
+		problems.add("http.port", "", fmt.Sprintf("must be between 1 and 65535, got %d", c.HTTP.Port))
+	}
+	problems.positive("http.shutdownTimeout", c.HTTP.ShutdownTimeout)
+	if _, err := problem.ParseFormat(c.HTTP.ErrorFormat); err != nil {
+		problems.add("http.errorFormat", "", fmt.Sprintf("must be %s or %s, got %q", problem.FormatProblem, problem.FormatLegacy, c.HTTP.ErrorFormat))
+	}
+	for _, proxy := range c.HTTP.Proxies() {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			problems.add("http.trustedProxies", "", fmt.Sprintf("must list IP addresses or CIDR ranges, got %q", proxy))
+		}
+	}
+
+	problems.required("import.csvPath", c.Import.CSVPath)
+
+	// Credentials are only required to serve, so a missing one is reported when the server starts.
+	if !c.Auth.Disabled && c.Auth.APIKeys != "" {
+		if _, err := auth.ParseStaticKeys(c.Auth.APIKeys); err != nil {
+			// The value holds secrets, so only the parser's complaint is reported.
+			problems.add("auth.apiKeys", "", err.Error())
+		}
+	}
+
+	problems.nonNegative("cache.size", int64(c.Cache.Size))
+	problems.positive("cache.ttl", c.Cache.TTL)
+
+	problems.nonNegative("rateLimit.readPerMinute", int64(c.RateLimit.ReadPerMinute))
+	problems.nonNegative("rateLimit.writePerMinute", int64(c.RateLimit.WritePerMinute))
+	problems.nonNegative("rateLimit.importPerMinute", int64(c.RateLimit.ImportPerMinute))
+	problems.nonNegative("rateLimit.ipPerMinute", int64(c.RateLimit.IPPerMinute))
+	problems.nonNegative("rateLimit.dailyQuota", c.RateLimit.DailyQuota)
+
+	problems.nonNegativeDuration("timeouts.read", c.Timeouts.Read)
+	problems.nonNegativeDuration("timeouts.write", c.Timeouts.Write)
+	problems.nonNegativeDuration("timeouts.importBatch", c.Timeouts.ImportBatch)
+
+	if _, err := logging.ParseLevel(c.Logging.Level); err != nil {
+		problems.add("logging.level", "", fmt.Sprintf("must be debug, info, warn or error, got %q", c.Logging.Level))
+	}
+	problems.oneOf("logging.format", c.Logging.Format, logging.FormatJSON, logging.FormatText)
+
+	problems.oneOf("tracing.exporter", c.Tracing.Exporter, tracing.ExporterNone, tracing.ExporterStdout, tracing.ExporterOTLP)
+
+	return problems.err()
+}
+
+// Redact returns a copy of c that is safe to print: secrets are replaced by Redacted and URIs
+// keep everything but their password.
+func (c Config) Redact() Config {
+	redacted := c
+	visit(reflect.ValueOf(&redacted).Elem(), "", func(_ string, field reflect.StructField, value reflect.Value) {
+		if value.Kind() != reflect.String || value.String() == "" {
+			return
+		}
+		switch field.Tag.Get("secret") {
+		case "true":
+			value.SetString(Redacted)
+		case "url":
+			if u, err := url.Parse(value.String()); err == nil {
+				value.SetString(u.Redacted())
+			} else {
+				value.SetString(Redacted)
+			}
+		}
+	})
+	return redacted
+}
+
+// Write prints the configuration with its secrets redacted, as YAML that can be used as a config file.
+func Write(w io.Writer, c Config) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c.Redact()); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
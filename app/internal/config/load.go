@@ -0,0 +1,293 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the environment variable that points at a config file when --config is not given.
+const FileEnv = "CONFIG_FILE"
+
+// Flags holds the configuration flags registered on a flag set.
+type Flags struct {
+	// File is the YAML (.yaml, .yml) or TOML (.toml) config file given with --config.
+	File string
+	// PrintConfig is set by --print-config: print the effective configuration and exit.
+	PrintConfig bool
+
+	values map[string]string
+}
+
+// RegisterFlags registers --config, --print-config and one flag per setting, named after its key
+// (e.g. --http.port), on fs.
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	flags := &Flags{values: make(map[string]string)}
+	fs.StringVar(&flags.File, "config", "", "YAML or TOML config file (default $"+FileEnv+")")
+	fs.BoolVar(&flags.PrintConfig, "print-config", false, "print the effective configuration, with secrets redacted, and exit")
+
+	defaults := Default()
+	visit(reflect.ValueOf(&defaults).Elem(), "", func(key string, field reflect.StructField, value reflect.Value) {
+		usage := field.Tag.Get("usage")
+		if env := field.Tag.Get("env"); env != "" {
+			usage += " (env " + env + ")"
+		}
+		fs.Func(key, usage, func(s string) error {
+			flags.values[key] = s
+			return nil
+		})
+	})
+	return flags
+}
+
+// Load builds the configuration from defaults, the config file, the environment (read with
+// lookupEnv, usually os.LookupEnv) and the flags, in that order. Flags may be nil. Any setting that
+// cannot be parsed or fails validation is reported in the returned *Error, together with its source.
+func Load(flags *Flags, lookupEnv func(string) (string, bool)) (Config, error) {
+	if flags == nil {
+		flags = &Flags{}
+	}
+	config := Default()
+	settings := settingsOf(&config)
+	var problems problems
+
+	file := flags.File
+	if file == "" {
+		file, _ = lookupEnv(FileEnv)
+	}
+	if file != "" {
+		values, err := readFile(file)
+		if err != nil {
+			problems.add("config", "file "+file, err.Error())
+		}
+		for _, key := range sortedKeys(values) {
+			setting, ok := settings[key]
+			if !ok {
+				problems.add(key, "file "+file, "unknown setting")
+				continue
+			}
+			problems.set(setting, values[key], "file "+file)
+		}
+	}
+
+	for _, key := range sortedKeys(settings) {
+		setting := settings[key]
+		if setting.env == "" {
+			continue
+		}
+		if value, ok := lookupEnv(setting.env); ok && value != "" {
+			problems.set(setting, value, "env "+setting.env)
+		}
+	}
+
+	for _, key := range sortedKeys(flags.values) {
+		problems.set(settings[key], flags.values[key], "flag --"+key)
+	}
+
+	// Settings that could not be parsed keep their previous value, so validating it would only add noise.
+	if err, ok := config.Validate().(*Error); ok {
+		reported := make(map[string]bool)
+		for _, problem := range problems {
+			reported[problem.Key] = true
+		}
+		for _, problem := range err.Problems {
+			if !reported[problem.Key] {
+				problems = append(problems, problem)
+			}
+		}
+	}
+	return config, problems.err()
+}
+
+// setting is one leaf of the configuration that sources can set.
+type setting struct {
+	key   string
+	env   string
+	value reflect.Value
+}
+
+func settingsOf(config *Config) map[string]setting {
+	settings := make(map[string]setting)
+	visit(reflect.ValueOf(config).Elem(), "", func(key string, field reflect.StructField, value reflect.Value) {
+		settings[key] = setting{key: key, env: field.Tag.Get("env"), value: value}
+	})
+	return settings
+}
+
+// visit calls fn for every setting below v, with its dotted key.
+func visit(v reflect.Value, prefix string, fn func(key string, field reflect.StructField, value reflect.Value)) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		key := prefix + field.Tag.Get("yaml")
+		if field.Type.Kind() == reflect.Struct {
+			visit(v.Field(i), key+".", fn)
+			continue
+		}
+		fn(key, field, v.Field(i))
+	}
+}
+
+// parse stores s in the setting, converting it to the setting's type.
+func (s setting) parse(value string) error {
+	switch {
+	case s.value.Type() == reflect.TypeOf(time.Duration(0)):
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("must be a duration such as 30s or 5m, got %q", value)
+		}
+		s.value.SetInt(int64(d))
+	case s.value.Kind() == reflect.String:
+		s.value.SetString(value)
+	case s.value.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("must be true or false, got %q", value)
+		}
+		s.value.SetBool(b)
+	case s.value.Kind() == reflect.Int || s.value.Kind() == reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("must be an integer, got %q", value)
+		}
+		s.value.SetInt(n)
+	default:
+		return fmt.Errorf("unsupported setting type %s", s.value.Type())
+	}
+	return nil
+}
+
+// readFile reads a YAML or TOML config file, chosen by its extension, into dotted keys and their values.
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	document := map[string]interface{}{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &document)
+	case ".toml":
+		err = toml.Unmarshal(data, &document)
+	default:
+		return nil, fmt.Errorf("unsupported config file type %q, expected .yaml, .yml or .toml", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file: %v", err)
+	}
+
+	values := make(map[string]string)
+	flatten(document, "", values)
+	return values, nil
+}
+
+func flatten(document map[string]interface{}, prefix string, values map[string]string) {
+	for name, value := range document {
+		if nested, ok := value.(map[string]interface{}); ok {
+			flatten(nested, prefix+name+".", values)
+			continue
+		}
+		if value == nil {
+			values[prefix+name] = ""
+			continue
+		}
+		values[prefix+name] = fmt.Sprint(value)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Problem is one invalid setting. Source tells where the value came from, e.g. "env PORT"; it is
+// empty when the effective value failed validation.
+type Problem struct {
+	Key     string
+	Source  string
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.Source == "" {
+		return p.Key + ": " + p.Message
+	}
+	return p.Key + " (" + p.Source + "): " + p.Message
+}
+
+// Error lists every invalid setting.
+type Error struct {
+	Problems []Problem
+}
+
+func (e *Error) Error() string {
+	lines := make([]string, 0, len(e.Problems))
+	for _, problem := range e.Problems {
+		lines = append(lines, "  "+problem.String())
+	}
+	return "invalid configuration:\n" + strings.Join(lines, "\n")
+}
+
+type problems []Problem
+
+func (p *problems) add(key, source, message string) {
+	*p = append(*p, Problem{Key: key, Source: source, Message: message})
+}
+
+func (p *problems) set(s setting, value, source string) {
+	if err := s.parse(value); err != nil {
+		p.add(s.key, source, err.Error())
+	}
+}
+
+func (p *problems) required(key, value string) {
+	if value == "" {
+		p.add(key, "", "must be set")
+	}
+}
+
+func (p *problems) positive(key string, d time.Duration) {
+	if d <= 0 {
+		p.add(key, "", fmt.Sprintf("must be a positive duration, got %s", d))
+	}
+}
+
+func (p *problems) nonNegativeDuration(key string, d time.Duration) {
+	if d < 0 {
+		p.add(key, "", fmt.Sprintf("must not be negative, got %s", d))
+	}
+}
+
+func (p *problems) nonNegative(key string, n int64) {
+	if n < 0 {
+		p.add(key, "", fmt.Sprintf("must not be negative, got %d", n))
+	}
+}
+
+func (p *problems) oneOf(key, value string, allowed ...string) {
+	for _, a := range allowed {
+		if strings.EqualFold(value, a) {
+			return
+		}
+	}
+	p.add(key, "", fmt.Sprintf("must be one of %s, got %q", strings.Join(allowed, ", "), value))
+}
+
+func (p problems) err() error {
+	if len(p) == 0 {
+		return nil
+	}
+	return &Error{Problems: p}
+}
@@ -0,0 +1,227 @@
+// Package countries holds the country reference data: the table embedded in the binary, the aliases
+// country names are matched against, and the overrides applied on top of it at runtime. The data is
+// loaded once and shared by every caller.
+package countries
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"swift-app/internal/models"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DatasetVersion identifies the embedded tables. Bump it whenever countries.csv or aliases.csv changes.
+const DatasetVersion = "2026.2"
+
+// countriesCSV is the embedded table: one row per ISO2 code with the ISO3 and numeric codes, the
+// name, the currency, the IBAN length (empty outside the IBAN registry) and SEPA membership.
+//
+//go:embed countries.csv
+var countriesCSV []byte
+
+// columns of countries.csv, in order.
+var columns = []string{"iso2", "iso3", "numeric", "name", "currency", "ibanLength", "sepa"}
+
+// Registry is the country table with its overrides. It is safe for concurrent use: readers get an
+// immutable snapshot, which every change replaces.
+type Registry struct {
+	// mu serializes changes; reads only load the snapshot.
+	mu        sync.Mutex
+	base      map[string]models.Country
+	aliases   map[string][]models.CountryAlias
+	overrides map[string]models.Country
+	snapshot  atomic.Pointer[snapshot]
+}
+
+type snapshot struct {
+	byISO2 map[string]models.Country
+	sorted []models.Country
+	// names maps the normalized names of every country to the alias they belong to, or to nil for
+	// the name of the country.
+	names     map[string]map[string]*models.CountryAlias
+	updatedAt *time.Time
+}
+
+var defaultRegistry = sync.OnceValue(func() *Registry {
+	registry, err := Parse(countriesCSV)
+	if err == nil {
+		err = registry.LoadAliases(aliasesCSV)
+	}
+	if err != nil {
+		// The tables are embedded and covered by tests, so this only happens in a broken build.
+		panic(fmt.Sprintf("invalid embedded country table: %v", err))
+	}
+	return registry
+})
+
+// Default returns the registry of the embedded tables, parsing them on first use.
+func Default() *Registry {
+	return defaultRegistry()
+}
+
+// Parse builds a registry from a table in the layout of countries.csv. Names are stored in upper
+// case, like the country names of SWIFT codes. The registry has no aliases until LoadAliases.
+func Parse(data []byte) (*Registry, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 || !slices.Equal(records[0], columns) {
+		return nil, fmt.Errorf("expected the header %s", strings.Join(columns, ","))
+	}
+
+	base := make(map[string]models.Country, len(records)-1)
+	for i, record := range records[1:] {
+		country, err := parseRow(record)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+2, err)
+		}
+		if _, duplicate := base[country.ISO2]; duplicate {
+			return nil, fmt.Errorf("row %d: duplicate ISO2 code %s", i+2, country.ISO2)
+		}
+		base[country.ISO2] = country
+	}
+
+	registry := &Registry{base: base, overrides: map[string]models.Country{}}
+	registry.publish()
+	return registry, nil
+}
+
+func parseRow(record []string) (models.Country, error) {
+	country := models.Country{
+		ISO2:     strings.ToUpper(record[0]),
+		ISO3:     strings.ToUpper(record[1]),
+		Numeric:  record[2],
+		Name:     strings.ToUpper(record[3]),
+		Currency: strings.ToUpper(record[4]),
+	}
+	if len(country.ISO2) != 2 || country.Name == "" {
+		return country, fmt.Errorf("an ISO2 code and a name are required")
+	}
+	if record[5] != "" {
+		length, err := strconv.Atoi(record[5])
+		if err != nil {
+			return country, fmt.Errorf("invalid IBAN length %q", record[5])
+		}
+		country.IBANLength = length
+	}
+	sepa, err := strconv.ParseBool(record[6])
+	if err != nil {
+		return country, fmt.Errorf("invalid SEPA flag %q", record[6])
+	}
+	country.SEPA = sepa
+	return country, nil
+}
+
+// Get returns the country with the given ISO2 code.
+func (r *Registry) Get(iso2 string) (models.Country, bool) {
+	country, ok := r.snapshot.Load().byISO2[iso2]
+	return country, ok
+}
+
+// Map returns every country by ISO2 code. The map is shared and must not be modified.
+func (r *Registry) Map() map[string]models.Country {
+	return r.snapshot.Load().byISO2
+}
+
+// All returns every country, sorted by ISO2 code.
+func (r *Registry) All() []models.Country {
+	return slices.Clone(r.snapshot.Load().sorted)
+}
+
+// Len returns the number of countries.
+func (r *Registry) Len() int {
+	return len(r.snapshot.Load().sorted)
+}
+
+// Overrides returns the number of overridden entries and the time of the last override.
+func (r *Registry) Overrides() (int, *time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.overrides), r.snapshot.Load().updatedAt
+}
+
+// Base returns the embedded entry of a country, ignoring its override.
+func (r *Registry) Base(iso2 string) (models.Country, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	country, ok := r.base[iso2]
+	country.Aliases = r.aliases[iso2]
+	return country, ok
+}
+
+// Override replaces or adds a country. It is kept until Reset.
+func (r *Registry) Override(country models.Country) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.overrides[country.ISO2] = country
+	r.publish()
+}
+
+// SetOverrides replaces every override, for example with the overrides stored in the database.
+func (r *Registry) SetOverrides(countries []models.Country) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.overrides = make(map[string]models.Country, len(countries))
+	for _, country := range countries {
+		r.overrides[country.ISO2] = country
+	}
+	r.publish()
+}
+
+// Reset removes the override of a country, restoring its embedded entry. A country that is not in
+// the embedded table is removed. It reports whether the country was overridden.
+func (r *Registry) Reset(iso2 string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.overrides[iso2]; !ok {
+		return false
+	}
+	delete(r.overrides, iso2)
+	r.publish()
+	return true
+}
+
+// publish replaces the snapshot with the embedded table merged with the overrides. r.mu must be held.
+func (r *Registry) publish() {
+	byISO2 := maps.Clone(r.base)
+	var updatedAt *time.Time
+	for iso2, country := range r.overrides {
+		byISO2[iso2] = country
+		if country.UpdatedAt != nil && (updatedAt == nil || country.UpdatedAt.After(*updatedAt)) {
+			updatedAt = country.UpdatedAt
+		}
+	}
+
+	names := make(map[string]map[string]*models.CountryAlias, len(byISO2))
+	for iso2, country := range byISO2 {
+		country.Aliases = r.aliases[iso2]
+		if base, ok := r.base[iso2]; ok && NormalizeName(base.Name) != NormalizeName(country.Name) {
+			// The embedded name is an English short name; files written before the override still use it.
+			country.Aliases = append(slices.Clip(country.Aliases), models.CountryAlias{
+				Name: base.Name, Kind: models.CountryAliasShort, Language: "en",
+			})
+		}
+		byISO2[iso2] = country
+
+		names[iso2] = map[string]*models.CountryAlias{NormalizeName(country.Name): nil}
+		for i := range country.Aliases {
+			name := NormalizeName(country.Aliases[i].Name)
+			if _, taken := names[iso2][name]; !taken {
+				names[iso2][name] = &country.Aliases[i]
+			}
+		}
+	}
+	sorted := slices.SortedFunc(maps.Values(byISO2), func(a, b models.Country) int {
+		return strings.Compare(a.ISO2, b.ISO2)
+	})
+	r.snapshot.Store(&snapshot{byISO2: byISO2, sorted: sorted, names: names, updatedAt: updatedAt})
+}
@@ -0,0 +1,123 @@
+// countries_test.go contains unit tests for the embedded country table and its overrides.
+package countries
+
+import (
+	"regexp"
+	"swift-app/internal/models"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const header = "iso2,iso3,numeric,name,currency,ibanLength,sepa\n"
+
+func TestDefault(t *testing.T) {
+	registry := Default()
+	assert.Greater(t, registry.Len(), 240)
+
+	poland, ok := registry.Get("PL")
+	require.True(t, ok)
+	assert.Contains(t, poland.Aliases, models.CountryAlias{Name: "Polska", Kind: models.CountryAliasShort, Language: "pl"})
+	poland.Aliases = nil
+	assert.Equal(t, models.Country{ISO2: "PL", ISO3: "POL", Numeric: "616", Name: "POLAND", Currency: "PLN", IBANLength: 28, SEPA: true}, poland)
+
+	iso3 := regexp.MustCompile(`^([A-Z]{3})?$`)
+	numeric := regexp.MustCompile(`^([0-9]{3})?$`)
+	for _, country := range registry.All() {
+		assert.Regexp(t, iso3, country.ISO3, country.ISO2)
+		assert.Regexp(t, numeric, country.Numeric, country.ISO2)
+		assert.Regexp(t, iso3, country.Currency, country.ISO2)
+		if country.IBANLength != 0 {
+			assert.True(t, country.IBANLength >= 15 && country.IBANLength <= 34, "IBAN length of %s", country.ISO2)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"header":      "iso2,name\nPL,Poland\n",
+		"duplicate":   header + "PL,POL,616,Poland,PLN,28,true\npl,POL,616,Poland,PLN,28,true\n",
+		"flag":        header + "PL,POL,616,Poland,PLN,28,maybe\n",
+		"iban length": header + "PL,POL,616,Poland,PLN,x,true\n",
+		"name":        header + "PL,POL,616,,PLN,28,true\n",
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse([]byte(data))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRegistry_Overrides(t *testing.T) {
+	registry, err := Parse([]byte(header + "HR,HRV,191,Croatia,HRK,21,true\nPL,POL,616,Poland,PLN,28,true\n"))
+	require.NoError(t, err)
+	count, updatedAt := registry.Overrides()
+	assert.Zero(t, count)
+	assert.Nil(t, updatedAt)
+
+	now := time.Now()
+	croatia := models.Country{ISO2: "HR", ISO3: "HRV", Numeric: "191", Name: "CROATIA", Currency: "EUR", IBANLength: 21, SEPA: true, UpdatedAt: &now}
+	registry.Override(croatia)
+	registry.Override(models.Country{ISO2: "XK", Name: "KOSOVO", Currency: "EUR", IBANLength: 20, UpdatedAt: &now})
+
+	got, _ := registry.Get("HR")
+	assert.Equal(t, "EUR", got.Currency)
+	base, _ := registry.Base("HR")
+	assert.Equal(t, "HRK", base.Currency, "Base should ignore the override")
+	assert.Equal(t, 3, registry.Len())
+	assert.Equal(t, []string{"HR", "PL", "XK"}, iso2s(registry.All()))
+	count, updatedAt = registry.Overrides()
+	assert.Equal(t, 2, count)
+	assert.Equal(t, &now, updatedAt)
+
+	assert.True(t, registry.Reset("HR"))
+	got, _ = registry.Get("HR")
+	assert.Equal(t, "HRK", got.Currency)
+	assert.False(t, registry.Reset("HR"), "HR is no longer overridden")
+	assert.True(t, registry.Reset("XK"))
+	_, ok := registry.Get("XK")
+	assert.False(t, ok, "a country added by an override should be removed with it")
+
+	registry.SetOverrides([]models.Country{croatia})
+	got, _ = registry.Get("HR")
+	assert.Equal(t, "EUR", got.Currency)
+	registry.SetOverrides(nil)
+	got, _ = registry.Get("HR")
+	assert.False(t, got.Overridden())
+}
+
+func TestRegistry_ConcurrentReads(t *testing.T) {
+	registry, err := Parse([]byte(header + "HR,HRV,191,Croatia,HRK,21,true\n"))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				country, ok := registry.Get("HR")
+				assert.True(t, ok)
+				assert.Contains(t, []string{"HRK", "EUR"}, country.Currency)
+				assert.Len(t, registry.All(), 1)
+			}
+		}()
+	}
+	for range 1000 {
+		registry.Override(models.Country{ISO2: "HR", Name: "CROATIA", Currency: "EUR"})
+		registry.Reset("HR")
+	}
+	wg.Wait()
+}
+
+func iso2s(countries []models.Country) []string {
+	codes := make([]string, len(countries))
+	for i, country := range countries {
+		codes[i] = country.ISO2
+	}
+	return codes
+}
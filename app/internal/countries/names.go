@@ -0,0 +1,129 @@
+package countries
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"slices"
+	"strings"
+	"swift-app/internal/models"
+	"unicode"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// aliasesCSV is the embedded alias table: official, short and common names of countries, in English
+// and in other languages.
+//
+//go:embed aliases.csv
+var aliasesCSV []byte
+
+// aliasColumns of aliases.csv, in order.
+var aliasColumns = []string{"iso2", "kind", "language", "name"}
+
+// NameMatch is the result of matching a name against the names of a country.
+type NameMatch struct {
+	// Name is the name of the country in the reference data, the one to store.
+	Name string
+	// Alias is the alias the name matched, or nil when it matched Name.
+	Alias *models.CountryAlias
+}
+
+// MatchName reports whether name is a name of the country with the given ISO2 code: its name in the
+// reference data or one of its aliases, compared with NormalizeName. When the name of an overridden
+// country changed, its embedded name is still matched as an alias.
+func (r *Registry) MatchName(iso2, name string) (NameMatch, bool) {
+	snapshot := r.snapshot.Load()
+	alias, ok := snapshot.names[iso2][NormalizeName(name)]
+	if !ok {
+		return NameMatch{}, false
+	}
+	return NameMatch{Name: snapshot.byISO2[iso2].Name, Alias: alias}, true
+}
+
+// LoadAliases replaces the aliases with the table in the layout of aliases.csv. Every alias must
+// belong to a country of the embedded table and differ from its other names.
+func (r *Registry) LoadAliases(data []byte) error {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 || !slices.Equal(records[0], aliasColumns) {
+		return fmt.Errorf("expected the header %s", strings.Join(aliasColumns, ","))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	aliases := make(map[string][]models.CountryAlias)
+	seen := make(map[string]bool)
+	for i, record := range records[1:] {
+		iso2 := strings.ToUpper(record[0])
+		alias, err := parseAlias(record)
+		if err != nil {
+			return fmt.Errorf("row %d: %w", i+2, err)
+		}
+		country, ok := r.base[iso2]
+		if !ok {
+			return fmt.Errorf("row %d: unknown ISO2 code %s", i+2, iso2)
+		}
+		key := iso2 + " " + NormalizeName(alias.Name)
+		if seen[key] || NormalizeName(alias.Name) == NormalizeName(country.Name) {
+			return fmt.Errorf("row %d: %q is already a name of %s", i+2, alias.Name, iso2)
+		}
+		seen[key] = true
+		aliases[iso2] = append(aliases[iso2], alias)
+	}
+	r.aliases = aliases
+	r.publish()
+	return nil
+}
+
+func parseAlias(record []string) (models.CountryAlias, error) {
+	alias := models.CountryAlias{Kind: record[1], Language: record[2], Name: strings.TrimSpace(record[3])}
+	switch alias.Kind {
+	case models.CountryAliasOfficial, models.CountryAliasShort, models.CountryAliasCommon:
+	default:
+		return alias, fmt.Errorf("invalid alias kind %q", alias.Kind)
+	}
+	if _, err := language.Parse(alias.Language); err != nil {
+		return alias, fmt.Errorf("invalid language %q", alias.Language)
+	}
+	if NormalizeName(alias.Name) == "" {
+		return alias, fmt.Errorf("a name is required")
+	}
+	return alias, nil
+}
+
+// removeMarks decomposes letters and drops their combining marks, so "Côte" becomes "Cote".
+var removeMarks = transform.Chain(norm.NFKD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+// foldLetters spells out the letters that have no decomposition.
+var foldLetters = strings.NewReplacer(
+	"ß", "SS", "ẞ", "SS", "æ", "AE", "Æ", "AE", "œ", "OE", "Œ", "OE", "þ", "TH", "Þ", "TH",
+	"ł", "L", "Ł", "L", "ø", "O", "Ø", "O", "đ", "D", "Đ", "D", "ı", "I",
+)
+
+// NormalizeName returns the form in which country names are compared: upper case, without
+// diacritics, apostrophes and periods, with other punctuation and runs of spaces turned into a
+// single space. "Côte d’Ivoire" and "COTE D'IVOIRE" both become "COTE DIVOIRE".
+func NormalizeName(name string) string {
+	if folded, _, err := transform.String(removeMarks, name); err == nil {
+		name = folded
+	}
+	name = strings.ToUpper(foldLetters.Replace(name))
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\'' || r == '’' || r == '‘' || r == '`' || r == '.':
+			return -1
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			return r
+		default:
+			return ' '
+		}
+	}, name)
+	return strings.Join(strings.Fields(name), " ")
+}
@@ -0,0 +1,86 @@
+// names_test.go contains unit tests for country name normalization and alias matching.
+package countries
+
+import (
+	"testing"
+
+	"swift-app/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := map[string]string{
+		"Côte d’Ivoire":        "COTE DIVOIRE",
+		"COTE D'IVOIRE":        "COTE DIVOIRE",
+		"  Bosnia-Herzegovina": "BOSNIA HERZEGOVINA",
+		"Myanmar [Burma]":      "MYANMAR BURMA",
+		"Österreich":           "OSTERREICH",
+		"Łotwa":                "LOTWA",
+		"Großbritannien":       "GROSSBRITANNIEN",
+		"U.S.A.":               "USA",
+		"Korea, Republic of":   "KOREA REPUBLIC OF",
+		"Россия":               "РОССИЯ",
+	}
+	for name, want := range tests {
+		assert.Equal(t, want, NormalizeName(name), name)
+	}
+}
+
+func TestRegistry_MatchName(t *testing.T) {
+	registry, err := Parse([]byte(header + "CZ,CZE,203,Czech Republic,CZK,24,true\nDE,DEU,276,Germany,EUR,22,true\n"))
+	require.NoError(t, err)
+	require.NoError(t, registry.LoadAliases([]byte("iso2,kind,language,name\n"+
+		"CZ,short,en,Czechia\nCZ,short,cs,Česko\nDE,short,de,Deutschland\n")))
+
+	match, ok := registry.MatchName("CZ", "czech republic")
+	assert.True(t, ok)
+	assert.Equal(t, NameMatch{Name: "CZECH REPUBLIC"}, match)
+
+	match, ok = registry.MatchName("CZ", "CESKO")
+	assert.True(t, ok)
+	assert.Equal(t, "CZECH REPUBLIC", match.Name)
+	assert.Equal(t, &models.CountryAlias{Name: "Česko", Kind: models.CountryAliasShort, Language: "cs"}, match.Alias)
+
+	_, ok = registry.MatchName("CZ", "Deutschland")
+	assert.False(t, ok, "aliases only match their own country")
+	_, ok = registry.MatchName("XX", "Czechia")
+	assert.False(t, ok)
+
+	// Renaming a country keeps its embedded name, and the alias it was renamed to is its name now.
+	registry.Override(models.Country{ISO2: "CZ", Name: "CZECHIA"})
+	match, ok = registry.MatchName("CZ", "Czechia")
+	assert.True(t, ok)
+	assert.Equal(t, NameMatch{Name: "CZECHIA"}, match)
+	match, ok = registry.MatchName("CZ", "CZECH REPUBLIC")
+	assert.True(t, ok)
+	assert.Equal(t, "CZECHIA", match.Name)
+	assert.NotNil(t, match.Alias)
+	czechia, _ := registry.Get("CZ")
+	assert.Len(t, czechia.Aliases, 3)
+
+	registry.Reset("CZ")
+	base, _ := registry.Base("CZ")
+	assert.Len(t, base.Aliases, 2)
+}
+
+func TestRegistry_LoadAliases_Invalid(t *testing.T) {
+	registry, err := Parse([]byte(header + "DE,DEU,276,Germany,EUR,22,true\n"))
+	require.NoError(t, err)
+
+	tests := map[string]string{
+		"header":    "iso2,name\nDE,Deutschland\n",
+		"country":   "iso2,kind,language,name\nXX,short,de,Deutschland\n",
+		"kind":      "iso2,kind,language,name\nDE,nickname,de,Deutschland\n",
+		"language":  "iso2,kind,language,name\nDE,short,deutsch!,Deutschland\n",
+		"name":      "iso2,kind,language,name\nDE,short,de, \n",
+		"duplicate": "iso2,kind,language,name\nDE,short,de,Deutschland\nDE,short,nl,DEUTSCHLAND\n",
+		"redundant": "iso2,kind,language,name\nDE,short,fr,germany\n",
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, registry.LoadAliases([]byte(data)))
+		})
+	}
+}
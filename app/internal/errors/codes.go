@@ -0,0 +1,98 @@
+package errors
+
+import "net/http"
+
+// Error codes are part of the API contract: clients may branch on them, so existing codes must
+// not be renamed. Messages, on the other hand, are for humans and may change.
+const (
+	// Generic codes, used when no more specific code applies.
+	CodeBadRequest         = "BAD_REQUEST"
+	CodeUnauthorized       = "UNAUTHORIZED"
+	CodeForbidden          = "FORBIDDEN"
+	CodeNotFound           = "NOT_FOUND"
+	CodeConflict           = "CONFLICT"
+	CodePreconditionFailed = "PRECONDITION_FAILED"
+	CodeRateLimited        = "RATE_LIMITED"
+	CodeInternal           = "INTERNAL_ERROR"
+	CodeTimeout            = "TIMEOUT"
+
+	// Request format and field validation
+	CodeValidationFailed = "VALIDATION_FAILED"
+	CodeFieldRequired    = "FIELD_REQUIRED"
+	CodeFieldTooShort    = "FIELD_TOO_SHORT"
+	CodeFieldTooLong     = "FIELD_TOO_LONG"
+	CodeFieldFormat      = "FIELD_INVALID_FORMAT"
+	CodeFieldNotAllowed  = "FIELD_VALUE_NOT_ALLOWED"
+	CodeUnknownField     = "UNKNOWN_FIELD"
+	CodeInvalidBody      = "INVALID_BODY"
+	CodeInvalidQuery     = "INVALID_QUERY"
+	CodeInvalidTime      = "INVALID_TIME"
+	CodeNoRoute          = "ROUTE_NOT_FOUND"
+
+	// SWIFT codes
+	CodeSwiftMissing              = "SWIFT_MISSING"
+	CodeSwiftInvalidLength        = "SWIFT_INVALID_LENGTH"
+	CodeSwiftInvalidCharacters    = "SWIFT_INVALID_CHARACTERS"
+	CodeSwiftHQSuffixRequired     = "SWIFT_HQ_SUFFIX_REQUIRED"
+	CodeSwiftBranchSuffixReserved = "SWIFT_BRANCH_SUFFIX_RESERVED"
+	CodeHQNotFound                = "HQ_NOT_FOUND"
+	CodeHQExists                  = "HQ_EXISTS"
+	CodeBranchNotFound            = "BRANCH_NOT_FOUND"
+	CodeBranchExists              = "BRANCH_EXISTS"
+	CodeBranchCountryMismatch     = "BRANCH_COUNTRY_MISMATCH"
+	CodeConcurrentModification    = "CONCURRENT_MODIFICATION"
+	CodeETagMismatch              = "ETAG_MISMATCH"
+	CodeLookupEmpty               = "LOOKUP_EMPTY"
+	CodeLookupTooLarge            = "LOOKUP_TOO_LARGE"
+	CodeHistoryNotFound           = "HISTORY_NOT_FOUND"
+
+	// Countries
+	CodeCountryInvalidISO2   = "COUNTRY_INVALID_ISO2"
+	CodeCountryNotFound      = "COUNTRY_NOT_FOUND"
+	CodeCountryNameMismatch  = "COUNTRY_NAME_MISMATCH"
+	CodeCountryNoCodes       = "COUNTRY_NO_SWIFT_CODES"
+	CodeCountryNotOverridden = "COUNTRY_NOT_OVERRIDDEN"
+
+	// Authentication and API keys
+	CodeInvalidCredentials = "INVALID_CREDENTIALS"
+	CodeAPIKeyRevoked      = "API_KEY_REVOKED"
+	CodeAPIKeyExpired      = "API_KEY_EXPIRED"
+	CodeAPIKeyNotFound     = "API_KEY_NOT_FOUND"
+	CodeInvalidScope       = "INVALID_SCOPE"
+)
+
+// Codes lists every error code the API can return, for documentation and tests.
+var Codes = []string{
+	CodeBadRequest, CodeUnauthorized, CodeForbidden, CodeNotFound, CodeConflict, CodePreconditionFailed, CodeRateLimited, CodeInternal, CodeTimeout,
+	CodeValidationFailed, CodeFieldRequired, CodeFieldTooShort, CodeFieldTooLong, CodeFieldFormat, CodeFieldNotAllowed, CodeUnknownField,
+	CodeInvalidBody, CodeInvalidQuery, CodeInvalidTime, CodeNoRoute,
+	CodeSwiftMissing, CodeSwiftInvalidLength, CodeSwiftInvalidCharacters, CodeSwiftHQSuffixRequired, CodeSwiftBranchSuffixReserved,
+	CodeHQNotFound, CodeHQExists, CodeBranchNotFound, CodeBranchExists, CodeBranchCountryMismatch, CodeConcurrentModification,
+	CodeETagMismatch, CodeLookupEmpty, CodeLookupTooLarge, CodeHistoryNotFound,
+	CodeCountryInvalidISO2, CodeCountryNotFound, CodeCountryNameMismatch, CodeCountryNoCodes, CodeCountryNotOverridden,
+	CodeInvalidCredentials, CodeAPIKeyRevoked, CodeAPIKeyExpired, CodeAPIKeyNotFound, CodeInvalidScope,
+}
+
+// codeForStatus returns the generic code for errors created without a specific one.
+func codeForStatus(statusCode int) string {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusPreconditionFailed:
+		return CodePreconditionFailed
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusGatewayTimeout:
+		return CodeTimeout
+	default:
+		return CodeInternal
+	}
+}
@@ -3,21 +3,87 @@
 package errors
 
 import (
+	"context"
+	stderrors "errors"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
+// AppError is an error that can be shown to API clients. Code is a stable machine-readable
+// identifier (see codes.go), Fields are JSON pointers to the offending input, Violations explain
+// per field what is wrong with it and Cause is the underlying error, which is logged but never
+// sent to clients.
 type AppError struct {
+	Code       string
 	Message    string
 	StatusCode int
+	Fields     []string
+	Violations []Violation
+	Cause      error
+
+	// kind is the predefined error this one was derived from, so errors.Is(err, ErrNotFound)
+	// holds for every not-found error regardless of its specific code.
+	kind *AppError
 }
 
 func (e *AppError) Error() string {
+	if e.Cause != nil {
+		return e.Message + ": " + e.Cause.Error()
+	}
 	return e.Message
 }
 
+// Unwrap returns the underlying cause, for errors.Is and errors.As.
+func (e *AppError) Unwrap() error {
+	return e.Cause
+}
+
+// Is reports whether e was derived from target, e.g. by Wrap.
+func (e *AppError) Is(target error) bool {
+	t, ok := target.(*AppError)
+	return ok && e.kind != nil && t == e.kind
+}
+
+// WithCode returns a copy of e with a specific error code.
+func (e *AppError) WithCode(code string) *AppError {
+	derived := e.derive()
+	derived.Code = code
+	return derived
+}
+
+// WithFields returns a copy of e pointing at the offending input fields, as JSON pointers such as "/swiftCode".
+func (e *AppError) WithFields(fields ...string) *AppError {
+	derived := e.derive()
+	derived.Fields = append(append([]string{}, e.Fields...), fields...)
+	return derived
+}
+
+// WithViolations returns a copy of e listing what is wrong with each invalid field.
+func (e *AppError) WithViolations(violations ...Violation) *AppError {
+	derived := e.derive()
+	derived.Violations = append(append([]Violation{}, e.Violations...), violations...)
+	return derived
+}
+
+// WithCause returns a copy of e wrapping the underlying error.
+func (e *AppError) WithCause(cause error) *AppError {
+	derived := e.derive()
+	derived.Cause = cause
+	return derived
+}
+
+func (e *AppError) derive() *AppError {
+	derived := *e
+	if derived.kind == nil {
+		derived.kind = e
+	}
+	return &derived
+}
+
 func New(message string, statusCode int) *AppError {
 	return &AppError{
+		Code:       codeForStatus(statusCode),
 		Message:    message,
 		StatusCode: statusCode,
 	}
@@ -28,17 +94,124 @@ var (
 	ErrNotFound   = New("not found", http.StatusNotFound)
 	ErrConflict   = New("conflict", http.StatusConflict)
 	ErrInternal   = New("internal server error", http.StatusInternalServerError)
+	ErrTimeout    = New("operation timed out", http.StatusGatewayTimeout)
+
+	ErrPreconditionFailed = New("precondition failed", http.StatusPreconditionFailed)
 )
 
+// Wrap derives an error from base with a new message, keeping its status code and error code.
 func Wrap(base *AppError, format string, args ...interface{}) *AppError {
-	return &AppError{
-		Message:    fmt.Sprintf(format, args...),
-		StatusCode: base.StatusCode,
+	derived := base.derive()
+	derived.Message = fmt.Sprintf(format, args...)
+	return derived
+}
+
+// Violation describes one invalid input field. Field is a JSON pointer such as "/swiftCode".
+type Violation struct {
+	Field   string
+	Code    string
+	Message string
+}
+
+// Collect aggregates validation errors so a client learns about every invalid field at once.
+// Nil errors are skipped. A single bad request error is returned with its own code and a matching
+// violation; several are merged into one VALIDATION_FAILED error listing all their violations,
+// keeping only the first violation per field. Any other error, such as a database failure, is
+// returned unchanged because the validation outcome is then unknown.
+func Collect(errs ...error) error {
+	var invalid []*AppError
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		appErr, ok := As(err)
+		if !ok || appErr.StatusCode != http.StatusBadRequest {
+			return err
+		}
+		invalid = append(invalid, appErr)
+	}
+
+	var violations []Violation
+	seen := make(map[string]bool)
+	for _, appErr := range invalid {
+		for _, violation := range appErr.violations() {
+			if violation.Field != "" && seen[violation.Field] {
+				continue
+			}
+			seen[violation.Field] = true
+			violations = append(violations, violation)
+		}
+	}
+
+	switch len(invalid) {
+	case 0:
+		return nil
+	case 1:
+		if len(invalid[0].Violations) > 0 {
+			return invalid[0]
+		}
+		return invalid[0].WithViolations(violations...)
+	}
+	return Invalid(violations...)
+}
+
+// Invalid builds a VALIDATION_FAILED error from field violations. Its message joins theirs.
+func Invalid(violations ...Violation) *AppError {
+	messages := make([]string, 0, len(violations))
+	fields := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		messages = append(messages, violation.Message)
+		if violation.Field != "" {
+			fields = append(fields, violation.Field)
+		}
+	}
+	err := Wrap(ErrBadRequest, "%s", strings.Join(messages, "; ")).WithCode(CodeValidationFailed).WithViolations(violations...)
+	err.Fields = fields
+	return err
+}
+
+// violations returns e's own violations or, if it has none, one describing e itself.
+func (e *AppError) violations() []Violation {
+	if len(e.Violations) > 0 {
+		return e.Violations
 	}
+	field := ""
+	if len(e.Fields) > 0 {
+		field = e.Fields[0]
+	}
+	return []Violation{{Field: field, Code: e.Code, Message: e.Message}}
+}
+
+// As finds the first AppError in err's chain.
+func As(err error) (*AppError, bool) {
+	var appErr *AppError
+	if stderrors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
+
+// FromDeadline reports an error caused by an expired deadline, such as a database operation that ran
+// past its timeout, as a TIMEOUT error with err as its cause. Any other error is returned unchanged.
+func FromDeadline(err error) error {
+	if err == nil || !stderrors.Is(err, context.DeadlineExceeded) || stderrors.Is(err, ErrTimeout) {
+		return err
+	}
+	message := "the operation did not finish in time"
+	if appErr, ok := As(err); ok {
+		message = appErr.Message + ": " + message
+	}
+	return Wrap(ErrTimeout, "%s", message).WithCause(err)
+}
+
+// HasCode reports whether err carries the given error code.
+func HasCode(err error, code string) bool {
+	appErr, ok := As(err)
+	return ok && appErr.Code == code
 }
 
 func GetStatusCode(err error) int {
-	if appErr, ok := err.(*AppError); ok {
+	if appErr, ok := As(err); ok {
 		return appErr.StatusCode
 	}
 	return http.StatusInternalServerError
@@ -0,0 +1,98 @@
+// errors_test.go contains unit tests for AppError codes, fields and wrapping.
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrap_KeepsKindAndCode(t *testing.T) {
+	err := Wrap(ErrNotFound, "headquarter %s not found", "AAAABBB1XXX").WithCode(CodeHQNotFound)
+
+	assert.Equal(t, "headquarter AAAABBB1XXX not found", err.Error())
+	assert.Equal(t, http.StatusNotFound, GetStatusCode(err))
+	assert.True(t, stderrors.Is(err, ErrNotFound))
+	assert.False(t, stderrors.Is(err, ErrBadRequest))
+	assert.True(t, HasCode(err, CodeHQNotFound))
+	assert.Equal(t, CodeNotFound, Wrap(ErrNotFound, "missing").Code, "a wrapped error keeps the generic code")
+}
+
+func TestWithMethods_DoNotModifyTheBase(t *testing.T) {
+	err := ErrBadRequest.WithCode(CodeSwiftInvalidLength).WithFields("/swiftCode")
+
+	assert.Equal(t, CodeBadRequest, ErrBadRequest.Code)
+	assert.Empty(t, ErrBadRequest.Fields)
+	assert.Equal(t, []string{"/swiftCode"}, err.Fields)
+	assert.True(t, stderrors.Is(err, ErrBadRequest))
+}
+
+func TestWithCause_UnwrapsForIsAndAs(t *testing.T) {
+	cause := stderrors.New("connection refused")
+	err := Wrap(ErrInternal, "error looking up SWIFT codes").WithCause(cause)
+
+	assert.Equal(t, "error looking up SWIFT codes: connection refused", err.Error())
+	assert.Equal(t, "error looking up SWIFT codes", err.Message)
+	assert.True(t, stderrors.Is(err, cause))
+	assert.True(t, stderrors.Is(err, ErrInternal))
+
+	wrapped := fmt.Errorf("import failed: %w", err)
+	appErr, ok := As(wrapped)
+	assert.True(t, ok)
+	assert.Same(t, err, appErr)
+	assert.Equal(t, http.StatusInternalServerError, GetStatusCode(wrapped))
+}
+
+func TestNew_DerivesGenericCodeFromStatus(t *testing.T) {
+	assert.Equal(t, CodeUnauthorized, New("no credentials", http.StatusUnauthorized).Code)
+	assert.Equal(t, CodeRateLimited, New("slow down", http.StatusTooManyRequests).Code)
+	assert.Equal(t, CodeTimeout, New("too slow", http.StatusGatewayTimeout).Code)
+	assert.Equal(t, CodeInternal, New("boom", http.StatusBadGateway).Code)
+	assert.Equal(t, http.StatusInternalServerError, GetStatusCode(stderrors.New("plain")))
+}
+
+func TestFromDeadline(t *testing.T) {
+	cause := fmt.Errorf("server selection error: %w", context.DeadlineExceeded)
+	err := FromDeadline(Wrap(ErrInternal, "error looking up SWIFT codes").WithCause(cause))
+
+	appErr, ok := As(err)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusGatewayTimeout, appErr.StatusCode)
+	assert.Equal(t, CodeTimeout, appErr.Code)
+	assert.Equal(t, "error looking up SWIFT codes: the operation did not finish in time", appErr.Message)
+	assert.True(t, stderrors.Is(err, ErrTimeout))
+	assert.True(t, stderrors.Is(err, context.DeadlineExceeded))
+	assert.Same(t, appErr, FromDeadline(err), "a timeout is not wrapped twice")
+
+	assert.Equal(t, http.StatusGatewayTimeout, GetStatusCode(FromDeadline(context.DeadlineExceeded)))
+
+	notFound := Wrap(ErrNotFound, "missing")
+	assert.Same(t, notFound, FromDeadline(notFound))
+	assert.Equal(t, context.Canceled, FromDeadline(context.Canceled))
+	assert.Nil(t, FromDeadline(nil))
+}
+
+func TestCollect(t *testing.T) {
+	length := Wrap(ErrBadRequest, "SWIFT code must be 8 or 11 characters").WithCode(CodeSwiftInvalidLength).WithFields("/swiftCode")
+	country := Wrap(ErrBadRequest, "country name 'X' does not match ISO2 'PL'").WithCode(CodeCountryNameMismatch).WithFields("/countryName", "/countryISO2")
+
+	assert.NoError(t, Collect(nil, nil))
+
+	single, _ := As(Collect(nil, length))
+	assert.Equal(t, CodeSwiftInvalidLength, single.Code, "a single error keeps its own code")
+	assert.Equal(t, []Violation{{Field: "/swiftCode", Code: CodeSwiftInvalidLength, Message: length.Message}}, single.Violations)
+
+	merged, _ := As(Collect(length, country, Wrap(ErrBadRequest, "SWIFT code is taken").WithFields("/swiftCode")))
+	assert.Equal(t, CodeValidationFailed, merged.Code)
+	assert.Equal(t, http.StatusBadRequest, merged.StatusCode)
+	assert.Equal(t, []string{"/swiftCode", "/countryName"}, merged.Fields)
+	assert.Len(t, merged.Violations, 2, "only the first violation per field is kept")
+	assert.Equal(t, "SWIFT code must be 8 or 11 characters; country name 'X' does not match ISO2 'PL'", merged.Message)
+
+	internal := Wrap(ErrInternal, "error loading country data")
+	assert.Same(t, internal, Collect(length, internal))
+}
@@ -0,0 +1,217 @@
+// Package export writes the stored SWIFT codes as CSV, JSON Lines or an Excel workbook. Headquarters are streamed from
+// MongoDB one document at a time, so an export never holds the whole dataset in memory.
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"swift-app/internal/errors"
+	"swift-app/internal/models"
+	"swift-app/internal/tracing"
+	"swift-app/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Format is an export file format.
+type Format string
+
+// Supported export formats.
+const (
+	FormatCSV   Format = "csv"
+	FormatJSONL Format = "jsonl"
+	FormatXLSX  Format = "xlsx"
+)
+
+// Formats lists the supported formats.
+var Formats = []Format{FormatCSV, FormatJSONL, FormatXLSX}
+
+// ContentType returns the media type of files in format.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatJSONL:
+		return "application/x-ndjson"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// FileName returns the name of an export file in format, naming the country when filter has one.
+func FileName(format Format, filter Filter) string {
+	if filter.CountryISO2 != "" {
+		return "swift-codes-" + strings.ToUpper(filter.CountryISO2) + "." + string(format)
+	}
+	return "swift-codes." + string(format)
+}
+
+// ParseFormat parses a format name, defaulting to FormatCSV when it is empty.
+func ParseFormat(value string) (Format, error) {
+	if value == "" {
+		return FormatCSV, nil
+	}
+	for _, format := range Formats {
+		if strings.EqualFold(value, string(format)) {
+			return format, nil
+		}
+	}
+	names := make([]string, len(Formats))
+	for i, format := range Formats {
+		names[i] = string(format)
+	}
+	return "", fmt.Errorf("unknown export format %q, expected one of %s", value, strings.Join(names, ", "))
+}
+
+// Header is the CSV header. It is the column layout the importer reads, so an export can be imported again.
+// The importer does not store TOWN NAME, TIME ZONE and CODE TYPE, so they are not round-tripped: the first
+// two are left empty and CODE TYPE is derived from the length of the code.
+var Header = []string{"COUNTRY ISO2 CODE", "SWIFT CODE", "CODE TYPE", "NAME", "ADDRESS", "TOWN NAME", "COUNTRY NAME", "TIME ZONE"}
+
+// codeType returns the CODE TYPE of an exported code: BIC8 for an 8 character code, BIC11 otherwise.
+func codeType(swiftCode string) string {
+	if len(swiftCode) == 8 {
+		return "BIC8"
+	}
+	return "BIC11"
+}
+
+// Filter limits an export. Empty fields match everything.
+type Filter struct {
+	CountryISO2 string
+}
+
+// Write streams every stored headquarter matching filter, each followed by its branches, to w in
+// format and returns the number of SWIFT codes written. Headquarters are ordered by SWIFT code.
+// The export is bounded by ctx only, as a full export may take longer than any single read.
+func Write(ctx context.Context, collection *mongo.Collection, w io.Writer, format Format, filter Filter) (written int, err error) {
+	ctx, span := tracing.Start(ctx, "export.Write", attribute.String("export.format", string(format)))
+	defer func() {
+		span.SetAttributes(attribute.Int("export.records", written))
+		tracing.End(span, &err)
+	}()
+
+	var enc encoder
+	switch format {
+	case FormatCSV:
+		enc = newCSVEncoder(w)
+	case FormatJSONL:
+		enc = newJSONLEncoder(w)
+	case FormatXLSX:
+		enc = newXLSXEncoder(w)
+	default:
+		return 0, fmt.Errorf("unknown export format %q", format)
+	}
+
+	query := bson.M{}
+	if filter.CountryISO2 != "" {
+		query[utils.FieldCountryISO2] = strings.ToUpper(filter.CountryISO2)
+	}
+	cursor, err := collection.Find(ctx, query, options.Find().SetSort(bson.D{{Key: utils.FieldSwiftCode, Value: 1}}))
+	if err != nil {
+		return 0, errors.Wrap(errors.ErrInternal, "failed to query SWIFT codes").WithCause(err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var hq models.SwiftCode
+		if err := cursor.Decode(&hq); err != nil {
+			return written, errors.Wrap(errors.ErrInternal, "failed to decode SWIFT code").WithCause(err)
+		}
+		for _, row := range rows(hq) {
+			if err := enc.encode(row); err != nil {
+				return written, err
+			}
+			written++
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return written, errors.Wrap(errors.ErrInternal, "failed to read SWIFT codes").WithCause(err)
+	}
+	return written, enc.close()
+}
+
+// rows flattens a stored document and its branches. The document keeps its own isHeadquarter flag;
+// branches take the country name of their headquarter.
+func rows(hq models.SwiftCode) []models.SwiftBranch {
+	rows := make([]models.SwiftBranch, 0, 1+len(hq.Branches))
+	rows = append(rows, models.SwiftBranch{
+		Address:       hq.Address,
+		BankName:      hq.BankName,
+		CountryISO2:   hq.CountryISO2,
+		CountryName:   hq.CountryName,
+		IsHeadquarter: hq.IsHeadquarter,
+		SwiftCode:     hq.SwiftCode,
+	})
+	for _, branch := range hq.Branches {
+		if branch.CountryName == "" {
+			branch.CountryName = hq.CountryName
+		}
+		branch.IsHeadquarter = false
+		rows = append(rows, branch)
+	}
+	return rows
+}
+
+// encoder writes exported rows in one format.
+type encoder interface {
+	encode(row models.SwiftBranch) error
+	// close flushes buffered rows; it is only called when every row was encoded.
+	close() error
+}
+
+type csvEncoder struct {
+	writer      *csv.Writer
+	wroteHeader bool
+}
+
+func newCSVEncoder(w io.Writer) *csvEncoder {
+	return &csvEncoder{writer: csv.NewWriter(w)}
+}
+
+func (e *csvEncoder) encode(row models.SwiftBranch) error {
+	if err := e.header(); err != nil {
+		return err
+	}
+	return e.writer.Write([]string{row.CountryISO2, row.SwiftCode, codeType(row.SwiftCode), row.BankName, row.Address, "", row.CountryName, ""})
+}
+
+// header writes the header once, so even an empty export is a valid import file.
+func (e *csvEncoder) header() error {
+	if e.wroteHeader {
+		return nil
+	}
+	e.wroteHeader = true
+	return e.writer.Write(Header)
+}
+
+func (e *csvEncoder) close() error {
+	if err := e.header(); err != nil {
+		return err
+	}
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+type jsonlEncoder struct {
+	encoder *json.Encoder
+}
+
+func newJSONLEncoder(w io.Writer) *jsonlEncoder {
+	return &jsonlEncoder{encoder: json.NewEncoder(w)}
+}
+
+func (e *jsonlEncoder) encode(row models.SwiftBranch) error {
+	return e.encoder.Encode(row)
+}
+
+func (e *jsonlEncoder) close() error {
+	return nil
+}
@@ -0,0 +1,222 @@
+// export_test.go contains unit tests for the export formats, the flattening of headquarters and the
+// round-trip of a CSV export through the importer.
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"swift-app/internal/errors"
+	"swift-app/internal/models"
+	parser "swift-app/pkg/csv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var testHeadquarter = models.SwiftCode{
+	SwiftCode:     "AAAAPLPWXXX",
+	BankName:      "TEST BANK",
+	Address:       "1 MAIN ST, WARSAW",
+	CountryISO2:   "PL",
+	CountryName:   "POLAND",
+	IsHeadquarter: true,
+	Branches: []models.SwiftBranch{
+		{SwiftCode: "AAAAPLPWKRK", BankName: "TEST BANK", Address: "2 SIDE ST, KRAKOW", CountryISO2: "PL"},
+	},
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatCSV, format)
+
+	format, err = ParseFormat("JSONL")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatJSONL, format)
+
+	format, err = ParseFormat("xlsx")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatXLSX, format)
+
+	_, err = ParseFormat("xml")
+	assert.ErrorContains(t, err, "unknown export format")
+}
+
+func TestFileName(t *testing.T) {
+	assert.Equal(t, "swift-codes.csv", FileName(FormatCSV, Filter{}))
+	assert.Equal(t, "swift-codes-PL.xlsx", FileName(FormatXLSX, Filter{CountryISO2: "pl"}))
+}
+
+func TestRows(t *testing.T) {
+	rows := rows(testHeadquarter)
+	assert.Len(t, rows, 2)
+	assert.True(t, rows[0].IsHeadquarter)
+	assert.Equal(t, "AAAAPLPWKRK", rows[1].SwiftCode)
+	assert.False(t, rows[1].IsHeadquarter)
+	assert.Equal(t, "POLAND", rows[1].CountryName, "Branches should take the country name of their headquarter")
+}
+
+func TestRows_KeepsStoredFlag(t *testing.T) {
+	rows := rows(models.SwiftCode{SwiftCode: "AAAAPLPWWAW", CountryISO2: "PL", CountryName: "POLAND", IsHeadquarter: false})
+	assert.Len(t, rows, 1)
+	assert.False(t, rows[0].IsHeadquarter, "A document stored as a branch should be exported as one")
+}
+
+func TestCSVEncoder(t *testing.T) {
+	var out bytes.Buffer
+	enc := newCSVEncoder(&out)
+	for _, row := range rows(testHeadquarter) {
+		assert.NoError(t, enc.encode(row))
+	}
+	assert.NoError(t, enc.close())
+	assert.Equal(t, `COUNTRY ISO2 CODE,SWIFT CODE,CODE TYPE,NAME,ADDRESS,TOWN NAME,COUNTRY NAME,TIME ZONE
+PL,AAAAPLPWXXX,BIC11,TEST BANK,"1 MAIN ST, WARSAW",,POLAND,
+PL,AAAAPLPWKRK,BIC11,TEST BANK,"2 SIDE ST, KRAKOW",,POLAND,
+`, out.String())
+
+	out.Reset()
+	assert.NoError(t, newCSVEncoder(&out).close())
+	assert.Equal(t, "COUNTRY ISO2 CODE,SWIFT CODE,CODE TYPE,NAME,ADDRESS,TOWN NAME,COUNTRY NAME,TIME ZONE\n", out.String(),
+		"An empty export should still have a header")
+}
+
+func TestJSONLEncoder(t *testing.T) {
+	var out bytes.Buffer
+	enc := newJSONLEncoder(&out)
+	for _, row := range rows(testHeadquarter) {
+		assert.NoError(t, enc.encode(row))
+	}
+	assert.NoError(t, enc.close())
+	assert.Equal(t, `{"address":"1 MAIN ST, WARSAW","bankName":"TEST BANK","countryISO2":"PL","countryName":"POLAND","isHeadquarter":true,"swiftCode":"AAAAPLPWXXX"}
+{"address":"2 SIDE ST, KRAKOW","bankName":"TEST BANK","countryISO2":"PL","countryName":"POLAND","isHeadquarter":false,"swiftCode":"AAAAPLPWKRK"}
+`, out.String())
+}
+
+func TestXLSXEncoder(t *testing.T) {
+	hq := testHeadquarter
+	hq.BankName = "TEST & SONS <BANK>"
+	var out bytes.Buffer
+	enc := newXLSXEncoder(&out)
+	for _, row := range rows(hq) {
+		assert.NoError(t, enc.encode(row))
+	}
+	require.NoError(t, enc.close())
+
+	archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	require.NoError(t, err)
+	parts := map[string]string{}
+	for _, file := range archive.File {
+		r, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		parts[file.Name] = string(content)
+	}
+	assert.Contains(t, parts, "[Content_Types].xml")
+	assert.Contains(t, parts, "xl/workbook.xml")
+
+	var sheet struct {
+		Rows []struct {
+			Cells []struct {
+				Ref  string `xml:"r,attr"`
+				Text string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	require.NoError(t, xml.Unmarshal([]byte(parts["xl/worksheets/sheet1.xml"]), &sheet))
+	require.Len(t, sheet.Rows, 3, "The header and one row per SWIFT code")
+	assert.Len(t, sheet.Rows[0].Cells, len(Header))
+	assert.Equal(t, "COUNTRY ISO2 CODE", sheet.Rows[0].Cells[0].Text)
+	assert.Equal(t, "D2", sheet.Rows[1].Cells[3].Ref)
+	assert.Equal(t, "TEST & SONS <BANK>", sheet.Rows[1].Cells[3].Text)
+	assert.Equal(t, "AAAAPLPWKRK", sheet.Rows[2].Cells[1].Text)
+}
+
+func TestWrite_StoreErrorIsInternal(t *testing.T) {
+	// Nothing listens on port 1, so the query fails once server selection times out.
+	client, err := mongo.Connect(context.Background(),
+		options.Client().ApplyURI("mongodb://127.0.0.1:1").SetServerSelectionTimeout(50*time.Millisecond))
+	require.NoError(t, err)
+	defer func() { _ = client.Disconnect(context.Background()) }()
+
+	var out bytes.Buffer
+	_, err = Write(context.Background(), client.Database("swiftDB").Collection("swiftCodes"), &out, FormatCSV, Filter{})
+	appErr, ok := errors.As(err)
+	require.True(t, ok, "store errors should be typed, got %v", err)
+	assert.Equal(t, http.StatusInternalServerError, appErr.StatusCode)
+	assert.Equal(t, "failed to query SWIFT codes", appErr.Message)
+	assert.Empty(t, out.String())
+}
+
+func TestCodeType(t *testing.T) {
+	assert.Equal(t, "BIC11", codeType("BPKOPLPWXXX"))
+	assert.Equal(t, "BIC8", codeType("BPKOPLPW"))
+}
+
+// TestCSVRoundTrip exports the SWIFT codes read from an import file the way they are stored, and
+// checks the importer reads the export back unchanged. The columns that are not stored are not
+// round-tripped, so the export leaves them empty rather than repeating the source file.
+func TestCSVRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "source.csv")
+	require.NoError(t, os.WriteFile(source, []byte(`COUNTRY ISO2 CODE,SWIFT CODE,CODE TYPE,NAME,ADDRESS,TOWN NAME,COUNTRY NAME,TIME ZONE
+BG,ADCRBGS1XXX,BIC11,ADAMANT CAPITAL PARTNERS AD,"JAMES BOURCHIER BLVD 76A HILL TOWER SOFIA, SOFIA, 1421",SOFIA,BULGARIA,Europe/Sofia
+BG,ADCRBGS1TRD,BIC11,ADAMANT CAPITAL PARTNERS AD,"JAMES BOURCHIER BLVD 76A, ""HILL TOWER""",SOFIA,BULGARIA,Europe/Sofia
+PL,BPKOPLPWXXX,BIC11,PKO BANK POLSKI S.A.,,WARSZAWA,POLAND,Europe/Warsaw
+PL,BPKOPLPWKRK,BIC11,PKO BANK POLSKI S.A.,  UL. WIELOPOLE 19  ,KRAKOW,POLAND,Europe/Warsaw
+PL,BPKOPLPWWAW,BIC11,PKO BANK POLSKI S.A.,UL. PULAWSKA 15,WARSZAWA,POLAND,Europe/Warsaw
+`), 0o600))
+	imported, err := parser.LoadSwiftCodes(source)
+	require.NoError(t, err)
+	require.Len(t, imported, 5)
+
+	// Store the codes the way the importer does: branches inside their headquarter, without a country name.
+	var stored []models.SwiftCode
+	for _, code := range imported {
+		if code.IsHeadquarter {
+			stored = append(stored, code)
+			continue
+		}
+		hq := &stored[len(stored)-1]
+		require.Equal(t, hq.SwiftCode[:8], code.SwiftCode[:8], "The fixture lists branches after their headquarter")
+		hq.Branches = append(hq.Branches, models.SwiftBranch{
+			Address: code.Address, BankName: code.BankName, CountryISO2: code.CountryISO2, SwiftCode: code.SwiftCode,
+		})
+	}
+
+	var out bytes.Buffer
+	enc := newCSVEncoder(&out)
+	for _, hq := range stored {
+		for _, row := range rows(hq) {
+			require.NoError(t, enc.encode(row))
+		}
+	}
+	require.NoError(t, enc.close())
+	exported := filepath.Join(dir, "export.csv")
+	require.NoError(t, os.WriteFile(exported, out.Bytes(), 0o600))
+
+	reimported, err := parser.LoadSwiftCodes(exported)
+	require.NoError(t, err)
+	assert.Equal(t, imported, reimported)
+
+	records, err := csv.NewReader(bytes.NewReader(out.Bytes())).ReadAll()
+	require.NoError(t, err)
+	for _, record := range records[1:] {
+		assert.Equal(t, []string{"BIC11", "", ""}, []string{record[2], record[5], record[7]}, record[1])
+	}
+
+	report, err := parser.ValidateFile(exported)
+	require.NoError(t, err)
+	assert.Empty(t, report.Errors)
+}
@@ -0,0 +1,111 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"swift-app/internal/models"
+)
+
+// The parts of a workbook with one worksheet. Cells are written as inline strings, so the workbook
+// needs no shared string table and the worksheet can be streamed row by row.
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+	xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="SWIFT codes" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+// xlsxEncoder writes an Excel workbook with the CSV columns. The archive is written as rows arrive,
+// so the workbook is never held in memory.
+type xlsxEncoder struct {
+	archive *zip.Writer
+	sheet   io.Writer
+	row     int
+}
+
+func newXLSXEncoder(w io.Writer) *xlsxEncoder {
+	return &xlsxEncoder{archive: zip.NewWriter(w)}
+}
+
+func (e *xlsxEncoder) encode(row models.SwiftBranch) error {
+	if err := e.start(); err != nil {
+		return err
+	}
+	return e.writeRow([]string{row.CountryISO2, row.SwiftCode, codeType(row.SwiftCode), row.BankName, row.Address, "", row.CountryName, ""})
+}
+
+// start writes the workbook parts and the header row once, so even an empty export opens in Excel.
+func (e *xlsxEncoder) start() error {
+	if e.sheet != nil {
+		return nil
+	}
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	}
+	for _, part := range parts {
+		w, err := e.archive.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, part.content); err != nil {
+			return err
+		}
+	}
+	sheet, err := e.archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(sheet, xlsxSheetStart); err != nil {
+		return err
+	}
+	e.sheet = sheet
+	return e.writeRow(Header)
+}
+
+func (e *xlsxEncoder) writeRow(values []string) error {
+	e.row++
+	ref := strconv.Itoa(e.row)
+	if _, err := fmt.Fprintf(e.sheet, `<row r="%s">`, ref); err != nil {
+		return err
+	}
+	for i, value := range values {
+		if value == "" {
+			continue
+		}
+		if _, err := fmt.Fprintf(e.sheet, `<c r="%c%s" t="inlineStr"><is><t xml:space="preserve">`, 'A'+i, ref); err != nil {
+			return err
+		}
+		// EscapeText also replaces characters XML cannot represent.
+		if err := xml.EscapeText(e.sheet, []byte(value)); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(e.sheet, `</t></is></c>`); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(e.sheet, `</row>`)
+	return err
+}
+
+func (e *xlsxEncoder) close() error {
+	if err := e.start(); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(e.sheet, xlsxSheetEnd); err != nil {
+		return err
+	}
+	return e.archive.Close()
+}
@@ -0,0 +1,139 @@
+// Package health runs the readiness checks of the service, such as pinging MongoDB or waiting for the
+// initial import, and reports each outcome with its latency.
+package health
+
+import (
+	"context"
+	"fmt"
+	"swift-app/internal/models"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultTimeout bounds each readiness check, so a hanging dependency reports down instead of
+// stalling the probe.
+const DefaultTimeout = 2 * time.Second
+
+// CheckFunc checks one dependency. Details are reported with the outcome, also when the check fails.
+type CheckFunc func(ctx context.Context) (details map[string]interface{}, err error)
+
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+// Checker runs the registered readiness checks concurrently.
+type Checker struct {
+	timeout time.Duration
+	mu      sync.RWMutex
+	checks  []check
+}
+
+// NewChecker creates a Checker that gives each check at most timeout to finish.
+func NewChecker(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Checker{timeout: timeout}
+}
+
+// Register adds a named check. The service is only ready while every check passes.
+func (c *Checker) Register(name string, fn CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, check{name: name, fn: fn})
+}
+
+// Check runs every check and reports the service ready if all of them passed.
+func (c *Checker) Check(ctx context.Context) models.HealthResponse {
+	c.mu.RLock()
+	checks := append([]check(nil), c.checks...)
+	c.mu.RUnlock()
+
+	results := make([]models.HealthCheck, len(checks))
+	var wg sync.WaitGroup
+	for i, chk := range checks {
+		wg.Add(1)
+		go func(i int, chk check) {
+			defer wg.Done()
+			results[i] = c.run(ctx, chk)
+		}(i, chk)
+	}
+	wg.Wait()
+
+	response := models.HealthResponse{Status: models.HealthStatusReady, Checks: make(map[string]models.HealthCheck, len(checks))}
+	for i, chk := range checks {
+		if results[i].Status != models.HealthStatusUp {
+			response.Status = models.HealthStatusNotReady
+		}
+		response.Checks[chk.name] = results[i]
+	}
+	return response
+}
+
+// run executes one check within the timeout. A check that does not return in time is reported down,
+// even if it ignores its context.
+func (c *Checker) run(ctx context.Context, chk check) models.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	type outcome struct {
+		details map[string]interface{}
+		err     error
+	}
+	done := make(chan outcome, 1)
+	start := time.Now()
+	go func() {
+		details, err := chk.fn(ctx)
+		done <- outcome{details, err}
+	}()
+
+	var result outcome
+	select {
+	case result = <-done:
+	case <-ctx.Done():
+		result.err = fmt.Errorf("check did not finish within %s", c.timeout)
+	}
+
+	status := models.HealthCheck{
+		Status:    models.HealthStatusUp,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		Details:   result.details,
+	}
+	if result.err != nil {
+		status.Status = models.HealthStatusDown
+		status.Error = result.err.Error()
+	}
+	return status
+}
+
+// Flag is a check that fails until it is set, e.g. while the initial import is still running.
+type Flag struct {
+	set     atomic.Bool
+	pending string
+}
+
+// NewFlag creates an unset flag. Until Set is called its check fails with the pending message.
+func NewFlag(pending string) *Flag {
+	return &Flag{pending: pending}
+}
+
+// Set marks the flag as done.
+func (f *Flag) Set() {
+	f.set.Store(true)
+}
+
+// IsSet reports whether Set was called.
+func (f *Flag) IsSet() bool {
+	return f.set.Load()
+}
+
+// Check implements CheckFunc.
+func (f *Flag) Check(context.Context) (map[string]interface{}, error) {
+	details := map[string]interface{}{"finished": f.IsSet()}
+	if !f.IsSet() {
+		return details, fmt.Errorf("%s", f.pending)
+	}
+	return details, nil
+}
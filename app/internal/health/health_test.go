@@ -0,0 +1,80 @@
+// health_test.go contains unit tests for running readiness checks and the import flag.
+package health
+
+import (
+	"context"
+	"errors"
+	"swift-app/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecker_AllChecksUp(t *testing.T) {
+	checker := NewChecker(time.Second)
+	checker.Register("mongo", func(context.Context) (map[string]interface{}, error) {
+		return nil, nil
+	})
+	checker.Register("countries", func(context.Context) (map[string]interface{}, error) {
+		return map[string]interface{}{"count": 249}, nil
+	})
+
+	response := checker.Check(context.Background())
+
+	assert.Equal(t, models.HealthStatusReady, response.Status)
+	assert.Len(t, response.Checks, 2)
+	assert.Equal(t, models.HealthStatusUp, response.Checks["mongo"].Status)
+	assert.Equal(t, 249, response.Checks["countries"].Details["count"])
+	assert.Empty(t, response.Checks["countries"].Error)
+}
+
+func TestChecker_FailingAndSlowChecks(t *testing.T) {
+	checker := NewChecker(20 * time.Millisecond)
+	checker.Register("mongo", func(context.Context) (map[string]interface{}, error) {
+		return nil, errors.New("connection refused")
+	})
+	checker.Register("slow", func(context.Context) (map[string]interface{}, error) {
+		// Ignores its context, so only the checker's timeout can end it.
+		time.Sleep(time.Second)
+		return nil, nil
+	})
+	checker.Register("countries", func(context.Context) (map[string]interface{}, error) {
+		return nil, nil
+	})
+
+	start := time.Now()
+	response := checker.Check(context.Background())
+
+	assert.Less(t, time.Since(start), 500*time.Millisecond, "checks should run concurrently and time out")
+	assert.Equal(t, models.HealthStatusNotReady, response.Status)
+	assert.Equal(t, models.HealthStatusDown, response.Checks["mongo"].Status)
+	assert.Equal(t, "connection refused", response.Checks["mongo"].Error)
+	assert.Equal(t, models.HealthStatusDown, response.Checks["slow"].Status)
+	assert.Contains(t, response.Checks["slow"].Error, "did not finish")
+	assert.GreaterOrEqual(t, response.Checks["slow"].LatencyMs, 20.0)
+	assert.Equal(t, models.HealthStatusUp, response.Checks["countries"].Status)
+}
+
+func TestChecker_NoChecksIsReady(t *testing.T) {
+	response := NewChecker(0).Check(context.Background())
+
+	assert.Equal(t, models.HealthStatusReady, response.Status)
+	assert.Empty(t, response.Checks)
+}
+
+func TestFlag(t *testing.T) {
+	flag := NewFlag("initial import has not finished")
+	checker := NewChecker(time.Second)
+	checker.Register("import", flag.Check)
+
+	response := checker.Check(context.Background())
+	assert.Equal(t, models.HealthStatusNotReady, response.Status)
+	assert.Equal(t, "initial import has not finished", response.Checks["import"].Error)
+	assert.Equal(t, false, response.Checks["import"].Details["finished"])
+
+	flag.Set()
+	response = checker.Check(context.Background())
+	assert.Equal(t, models.HealthStatusReady, response.Status)
+	assert.Equal(t, true, response.Checks["import"].Details["finished"])
+}
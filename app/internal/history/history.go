@@ -0,0 +1,313 @@
+// Package history records versioned before/after snapshots of SWIFT headquarter documents
+// and reconstructs how a SWIFT code (including its branches) looked at a given point in time.
+package history
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"swift-app/internal/errors"
+	"swift-app/internal/models"
+	"swift-app/internal/timeouts"
+	"swift-app/internal/utils"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Recorder stores and queries history entries kept in a dedicated collection. Versions are taken
+// from one counter document per headquarter, so concurrent writers never share a version.
+type Recorder struct {
+	DB       *mongo.Collection
+	Counters *mongo.Collection
+}
+
+// NewRecorder creates a Recorder backed by the given history collection and the counter collection
+// next to it.
+func NewRecorder(db *mongo.Collection) *Recorder {
+	return &Recorder{DB: db, Counters: db.Database().Collection(db.Name() + utils.CounterCollectionSuffix)}
+}
+
+// CollectionFor returns the history collection that lives next to the given SWIFT collection.
+func CollectionFor(swiftCollection *mongo.Collection) *mongo.Collection {
+	return swiftCollection.Database().Collection(swiftCollection.Name() + utils.HistoryCollectionSuffix)
+}
+
+// EnsureIndexes creates the indexes used by history lookups, including the unique index on the
+// version of each headquarter. Counters missing for history recorded before versions were counted
+// are seeded from the highest recorded version.
+func (r *Recorder) EnsureIndexes(ctx context.Context) error {
+	if err := r.seedCounters(ctx); err != nil {
+		return err
+	}
+	_, err := r.DB.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: utils.FieldHeadquarterCode, Value: 1}, {Key: utils.FieldTimestamp, Value: 1}}},
+		{Keys: bson.D{{Key: utils.FieldSwiftCode, Value: 1}, {Key: utils.FieldTimestamp, Value: 1}}},
+		{Keys: bson.D{
+			{Key: utils.FieldOperation, Value: 1},
+			{Key: utils.FieldBefore + "." + utils.FieldCountryISO2, Value: 1},
+			{Key: utils.FieldTimestamp, Value: -1},
+		}},
+		{
+			Keys:    bson.D{{Key: utils.FieldHeadquarterCode, Value: 1}, {Key: utils.FieldVersion, Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create history indexes: %w", err)
+	}
+	return nil
+}
+
+// seedCounters raises every counter to the highest version recorded for its headquarter.
+func (r *Recorder) seedCounters(ctx context.Context) error {
+	cursor, err := r.DB.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": "$" + utils.FieldHeadquarterCode, "version": bson.M{"$max": "$" + utils.FieldVersion}}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read history versions: %w", err)
+	}
+	var versions []struct {
+		HeadquarterCode string `bson:"_id"`
+		Version         int64  `bson:"version"`
+	}
+	if err := cursor.All(ctx, &versions); err != nil {
+		return fmt.Errorf("failed to read history versions: %w", err)
+	}
+	for _, v := range versions {
+		_, err := r.Counters.UpdateOne(ctx, bson.M{"_id": v.HeadquarterCode},
+			bson.M{"$max": bson.M{utils.FieldVersion: v.Version}}, options.Update().SetUpsert(true))
+		if err != nil {
+			return fmt.Errorf("failed to seed history counter for %s: %w", v.HeadquarterCode, err)
+		}
+	}
+	return nil
+}
+
+// nextVersion increments the counter of a headquarter and returns its new value.
+func (r *Recorder) nextVersion(ctx context.Context, headquarterCode string) (int64, error) {
+	var counter struct {
+		Version int64 `bson:"version"`
+	}
+	err := r.Counters.FindOneAndUpdate(ctx, bson.M{"_id": headquarterCode},
+		bson.M{"$inc": bson.M{utils.FieldVersion: int64(1)}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)).Decode(&counter)
+	return counter.Version, err
+}
+
+// Record stores a change of a headquarter document. The timestamp and the per-headquarter
+// version are assigned by the recorder; versions keep counting when a headquarter is deleted and
+// added again. Pass a session context to record inside a transaction.
+func (r *Recorder) Record(ctx context.Context, swiftCode, operation, source string, before, after *models.SwiftCode) error {
+	_, err := r.record(ctx, swiftCode, operation, source, time.Now(), before, after)
+	return err
+}
+
+// RecordReversible works like Record and also returns a function that removes the entry again.
+// Writers that cannot record inside a transaction use it to take the entry back when a later step
+// of the same change fails.
+func (r *Recorder) RecordReversible(ctx context.Context, swiftCode, operation, source string, before, after *models.SwiftCode) (func(context.Context) error, error) {
+	version, err := r.record(ctx, swiftCode, operation, source, time.Now(), before, after)
+	if err != nil {
+		return nil, err
+	}
+	headquarterCode := HeadquarterCode(swiftCode)
+	return func(ctx context.Context) error {
+		ctx, cancel := timeouts.Write(ctx)
+		defer cancel()
+		filter := bson.M{utils.FieldHeadquarterCode: headquarterCode, utils.FieldVersion: version}
+		if _, err := r.DB.DeleteOne(ctx, filter); err != nil {
+			return fmt.Errorf("failed to remove history version %d of %s: %w", version, headquarterCode, err)
+		}
+		return nil
+	}, nil
+}
+
+func (r *Recorder) record(ctx context.Context, swiftCode, operation, source string, at time.Time, before, after *models.SwiftCode) (int64, error) {
+	headquarterCode := HeadquarterCode(swiftCode)
+	ctx, cancel := timeouts.Write(ctx)
+	defer cancel()
+
+	version, err := r.nextVersion(ctx, headquarterCode)
+	if err != nil {
+		return 0, fmt.Errorf("failed to determine history version for %s: %w", headquarterCode, err)
+	}
+
+	entry := models.HistoryEntry{
+		SwiftCode:       swiftCode,
+		HeadquarterCode: headquarterCode,
+		Version:         version,
+		Operation:       operation,
+		Source:          source,
+		Timestamp:       at.UTC().Truncate(time.Millisecond),
+		Before:          before,
+		After:           after,
+	}
+	if _, err := r.DB.InsertOne(ctx, entry); err != nil {
+		return 0, fmt.Errorf("failed to record history for %s: %w", swiftCode, err)
+	}
+	return version, nil
+}
+
+// Seed records a baseline create entry for every headquarter in swiftCollection that has no history
+// yet, and one for each of its branches, so codes stored before history was kept can be looked up.
+// The entries are dated when the document was last modified, or else when it was inserted, and hold
+// the document as it is stored now. It returns the number of seeded headquarters and is safe to rerun.
+func (r *Recorder) Seed(ctx context.Context, swiftCollection *mongo.Collection) (int, error) {
+	recorded, err := r.DB.Distinct(ctx, utils.FieldHeadquarterCode, bson.M{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read recorded headquarters: %w", err)
+	}
+	hasHistory := make(map[string]bool, len(recorded))
+	for _, code := range recorded {
+		if code, ok := code.(string); ok {
+			hasHistory[code] = true
+		}
+	}
+
+	cursor, err := swiftCollection.Find(ctx, bson.M{utils.FieldIsHeadquarter: true})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read stored SWIFT codes: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	seeded := 0
+	for cursor.Next(ctx) {
+		var stored struct {
+			ID               interface{} `bson:"_id"`
+			models.SwiftCode `bson:",inline"`
+		}
+		if err := cursor.Decode(&stored); err != nil {
+			return seeded, fmt.Errorf("failed to decode stored SWIFT code: %w", err)
+		}
+		hq := stored.SwiftCode
+		if hasHistory[hq.SwiftCode] {
+			continue
+		}
+		if hq.Branches == nil {
+			hq.Branches = []models.SwiftBranch{}
+		}
+
+		at := time.Now()
+		if hq.LastModified != nil {
+			at = *hq.LastModified
+		} else if id, ok := stored.ID.(primitive.ObjectID); ok {
+			at = id.Timestamp()
+		}
+		codes := []string{hq.SwiftCode}
+		for _, branch := range hq.Branches {
+			codes = append(codes, branch.SwiftCode)
+		}
+		for _, code := range codes {
+			if _, err := r.record(ctx, code, models.HistoryOperationCreate, models.HistorySourceMigrate, at, nil, &hq); err != nil {
+				return seeded, err
+			}
+		}
+		seeded++
+	}
+	if err := cursor.Err(); err != nil {
+		return seeded, fmt.Errorf("failed to read stored SWIFT codes: %w", err)
+	}
+	return seeded, nil
+}
+
+// List returns all history entries affecting the given SWIFT code, oldest first.
+// For a headquarter this includes changes to any of its branches.
+func (r *Recorder) List(ctx context.Context, swiftCode string) ([]models.HistoryEntry, error) {
+	filter := bson.M{utils.FieldSwiftCode: swiftCode}
+	if strings.HasSuffix(swiftCode, "XXX") {
+		filter = bson.M{utils.FieldHeadquarterCode: swiftCode}
+	}
+
+	ctx, cancel := timeouts.Read(ctx)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: utils.FieldTimestamp, Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.DB.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrInternal, "error retrieving history for SWIFT code %s", swiftCode).WithCause(err)
+	}
+	defer cursor.Close(ctx)
+
+	entries := []models.HistoryEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, errors.Wrap(errors.ErrInternal, "error decoding history for SWIFT code %s", swiftCode).WithCause(err)
+	}
+	return entries, nil
+}
+
+// LastDelete returns when a SWIFT code of the country was last deleted, or nil if none was. A delete
+// changes the country's listing without leaving a newer document behind to date the change.
+func (r *Recorder) LastDelete(ctx context.Context, countryISO2 string) (*time.Time, error) {
+	ctx, cancel := timeouts.Read(ctx)
+	defer cancel()
+
+	var entry models.HistoryEntry
+	err := r.DB.FindOne(ctx, bson.M{
+		utils.FieldOperation:                             models.HistoryOperationDelete,
+		utils.FieldBefore + "." + utils.FieldCountryISO2: countryISO2,
+	}, options.FindOne().SetSort(bson.D{{Key: utils.FieldTimestamp, Value: -1}})).Decode(&entry)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrInternal, "error retrieving deletes for country %s", countryISO2).WithCause(err)
+	}
+	return &entry.Timestamp, nil
+}
+
+// AsOf reconstructs the SWIFT code as it was recorded at the given time. A headquarter is returned
+// together with its branches; a branch is returned with the country name of its headquarter.
+func (r *Recorder) AsOf(ctx context.Context, swiftCode string, at time.Time) (*models.SwiftCode, error) {
+	headquarterCode := HeadquarterCode(swiftCode)
+	ctx, cancel := timeouts.Read(ctx)
+	defer cancel()
+
+	opts := options.FindOne().SetSort(bson.D{{Key: utils.FieldTimestamp, Value: -1}, {Key: "_id", Value: -1}})
+	var entry models.HistoryEntry
+	err := r.DB.FindOne(ctx, bson.M{
+		utils.FieldHeadquarterCode: headquarterCode,
+		utils.FieldTimestamp:       bson.M{"$lte": at},
+	}, opts).Decode(&entry)
+	if err == mongo.ErrNoDocuments || (err == nil && entry.After == nil) {
+		return nil, errors.Wrap(errors.ErrNotFound, "SWIFT code %s did not exist as of %s", swiftCode, at.Format(time.RFC3339)).
+			WithCode(errors.CodeHistoryNotFound)
+	}
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrInternal, "error retrieving history for SWIFT code %s", swiftCode).WithCause(err)
+	}
+
+	headquarter := entry.After
+	if swiftCode == headquarterCode {
+		if headquarter.Branches == nil {
+			headquarter.Branches = []models.SwiftBranch{}
+		}
+		return headquarter, nil
+	}
+
+	for _, branch := range headquarter.Branches {
+		if branch.SwiftCode == swiftCode {
+			return &models.SwiftCode{
+				Address:       branch.Address,
+				BankName:      branch.BankName,
+				CountryISO2:   branch.CountryISO2,
+				CountryName:   headquarter.CountryName,
+				IsHeadquarter: false,
+				SwiftCode:     branch.SwiftCode,
+			}, nil
+		}
+	}
+	return nil, errors.Wrap(errors.ErrNotFound, "SWIFT code %s did not exist as of %s", swiftCode, at.Format(time.RFC3339)).
+		WithCode(errors.CodeHistoryNotFound)
+}
+
+// HeadquarterCode returns the headquarter SWIFT code that owns the given code.
+func HeadquarterCode(swiftCode string) string {
+	if len(swiftCode) < 8 {
+		return swiftCode
+	}
+	return swiftCode[:8] + "XXX"
+}
@@ -0,0 +1,160 @@
+// history_test.go contains integration tests for recording history entries and
+// reconstructing SWIFT codes at a point in time.
+package history
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"swift-app/internal/models"
+	testutils "swift-app/internal/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func newRecorder() *Recorder {
+	recorder := NewRecorder(CollectionFor(testutils.Collection))
+	_, _ = recorder.DB.DeleteMany(context.Background(), bson.M{})
+	_, _ = recorder.Counters.DeleteMany(context.Background(), bson.M{})
+	return recorder
+}
+
+func TestRecordAndList(t *testing.T) {
+	recorder := newRecorder()
+
+	hq := &models.SwiftCode{SwiftCode: "HISTBANKXXX", BankName: "Hist Bank", CountryISO2: "PL", CountryName: "POLAND", IsHeadquarter: true}
+	withBranch := &models.SwiftCode{SwiftCode: "HISTBANKXXX", BankName: "Hist Bank", CountryISO2: "PL", CountryName: "POLAND", IsHeadquarter: true,
+		Branches: []models.SwiftBranch{{SwiftCode: "HISTBANK001", BankName: "Hist Branch", CountryISO2: "PL"}}}
+
+	assert.NoError(t, recorder.Record(context.Background(), "HISTBANKXXX", models.HistoryOperationCreate, models.HistorySourceImport, nil, hq))
+	assert.NoError(t, recorder.Record(context.Background(), "HISTBANK001", models.HistoryOperationCreate, models.HistorySourceAPI, hq, withBranch))
+
+	entries, err := recorder.List(context.Background(), "HISTBANKXXX")
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2, "HQ history should include branch changes")
+	assert.Equal(t, int64(1), entries[0].Version)
+	assert.Equal(t, int64(2), entries[1].Version)
+	assert.Equal(t, models.HistorySourceImport, entries[0].Source)
+
+	entries, err = recorder.List(context.Background(), "HISTBANK001")
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "HISTBANKXXX", entries[0].HeadquarterCode)
+}
+
+func TestAsOf(t *testing.T) {
+	recorder := newRecorder()
+
+	hq := &models.SwiftCode{SwiftCode: "ASOFBANKXXX", BankName: "AsOf Bank", CountryISO2: "PL", CountryName: "POLAND", IsHeadquarter: true}
+	withBranch := &models.SwiftCode{SwiftCode: "ASOFBANKXXX", BankName: "AsOf Bank", CountryISO2: "PL", CountryName: "POLAND", IsHeadquarter: true,
+		Branches: []models.SwiftBranch{{SwiftCode: "ASOFBANK001", BankName: "AsOf Branch", CountryISO2: "PL"}}}
+
+	beforeCreate := time.Now().UTC().Add(-time.Second)
+	assert.NoError(t, recorder.Record(context.Background(), "ASOFBANKXXX", models.HistoryOperationCreate, models.HistorySourceAPI, nil, hq))
+	time.Sleep(10 * time.Millisecond)
+	afterCreate := time.Now().UTC()
+	time.Sleep(10 * time.Millisecond)
+	assert.NoError(t, recorder.Record(context.Background(), "ASOFBANK001", models.HistoryOperationCreate, models.HistorySourceAPI, hq, withBranch))
+	time.Sleep(10 * time.Millisecond)
+	afterBranch := time.Now().UTC()
+	time.Sleep(10 * time.Millisecond)
+	assert.NoError(t, recorder.Record(context.Background(), "ASOFBANKXXX", models.HistoryOperationDelete, models.HistorySourceAPI, withBranch, nil))
+
+	_, err := recorder.AsOf(context.Background(), "ASOFBANKXXX", beforeCreate)
+	assert.Error(t, err, "HQ should not exist before it was created")
+
+	result, err := recorder.AsOf(context.Background(), "ASOFBANKXXX", afterCreate)
+	assert.NoError(t, err)
+	assert.Empty(t, result.Branches)
+
+	result, err = recorder.AsOf(context.Background(), "ASOFBANKXXX", afterBranch)
+	assert.NoError(t, err)
+	assert.Len(t, result.Branches, 1)
+
+	branch, err := recorder.AsOf(context.Background(), "ASOFBANK001", afterBranch)
+	assert.NoError(t, err)
+	assert.False(t, branch.IsHeadquarter)
+	assert.Equal(t, "POLAND", branch.CountryName)
+
+	_, err = recorder.AsOf(context.Background(), "ASOFBANK001", afterCreate)
+	assert.Error(t, err, "branch should not exist before it was added")
+
+	_, err = recorder.AsOf(context.Background(), "ASOFBANKXXX", time.Now().UTC())
+	assert.Error(t, err, "HQ should not exist after it was deleted")
+}
+
+func TestRecord_ConcurrentVersions(t *testing.T) {
+	recorder := newRecorder()
+	assert.NoError(t, recorder.EnsureIndexes(context.Background()))
+
+	hq := &models.SwiftCode{SwiftCode: "VERSBANKXXX", BankName: "Version Bank", CountryISO2: "PL", CountryName: "POLAND", IsHeadquarter: true}
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			branch := fmt.Sprintf("VERSBANK%03d", i)
+			assert.NoError(t, recorder.Record(context.Background(), branch, models.HistoryOperationCreate, models.HistorySourceAPI, hq, hq))
+		}()
+	}
+	wg.Wait()
+
+	entries, err := recorder.List(context.Background(), "VERSBANKXXX")
+	assert.NoError(t, err)
+	versions := map[int64]bool{}
+	for _, entry := range entries {
+		versions[entry.Version] = true
+	}
+	assert.Len(t, versions, 10, "concurrent writers should record distinct versions")
+
+	// Versions keep counting after the headquarter is deleted and added again.
+	assert.NoError(t, recorder.Record(context.Background(), "VERSBANKXXX", models.HistoryOperationDelete, models.HistorySourceAPI, hq, nil))
+	assert.NoError(t, recorder.Record(context.Background(), "VERSBANKXXX", models.HistoryOperationCreate, models.HistorySourceAPI, nil, hq))
+	entries, err = recorder.List(context.Background(), "VERSBANKXXX")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), entries[len(entries)-1].Version)
+
+	_, err = recorder.DB.InsertOne(context.Background(), models.HistoryEntry{HeadquarterCode: "VERSBANKXXX", Version: 12})
+	assert.True(t, mongo.IsDuplicateKeyError(err), "versions should be unique per headquarter")
+}
+
+func TestSeed(t *testing.T) {
+	recorder := newRecorder()
+	_, _ = testutils.Collection.DeleteMany(context.Background(), bson.M{})
+
+	_, err := testutils.Collection.InsertOne(context.Background(), bson.M{
+		"swiftCode": "SEEDBANKXXX", "bankName": "Seed Bank", "countryISO2": "PL", "countryName": "POLAND", "isHeadquarter": true,
+		"branches": []bson.M{{"swiftCode": "SEEDBANK001", "bankName": "Seed Branch", "countryISO2": "PL", "isHeadquarter": false}},
+	})
+	assert.NoError(t, err)
+	stored := time.Now().UTC()
+	// A headquarter with history already is left alone.
+	assert.NoError(t, recorder.Record(context.Background(), "KEEPBANKXXX", models.HistoryOperationCreate, models.HistorySourceAPI, nil,
+		&models.SwiftCode{SwiftCode: "KEEPBANKXXX", CountryISO2: "PL", IsHeadquarter: true}))
+	_, err = testutils.Collection.InsertOne(context.Background(), bson.M{"swiftCode": "KEEPBANKXXX", "countryISO2": "PL", "isHeadquarter": true})
+	assert.NoError(t, err)
+
+	seeded, err := recorder.Seed(context.Background(), testutils.Collection)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, seeded)
+
+	branch, err := recorder.AsOf(context.Background(), "SEEDBANK001", stored)
+	assert.NoError(t, err, "A seeded code exists as of the time it was stored")
+	if assert.NotNil(t, branch) {
+		assert.Equal(t, "Seed Branch", branch.BankName)
+		assert.Equal(t, "POLAND", branch.CountryName)
+	}
+	entries, err := recorder.List(context.Background(), "SEEDBANKXXX")
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2, "one entry for the headquarter and one per branch")
+	assert.Equal(t, models.HistorySourceMigrate, entries[0].Source)
+
+	seeded, err = recorder.Seed(context.Background(), testutils.Collection)
+	assert.NoError(t, err)
+	assert.Zero(t, seeded, "seeding again adds nothing")
+}
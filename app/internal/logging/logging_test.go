@@ -0,0 +1,131 @@
+// logging_test.go contains unit tests for logger configuration, request ID and trace correlation and access logging.
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"swift-app/internal/requestid"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var lines []map[string]interface{}
+	decoder := json.NewDecoder(buf)
+	for decoder.More() {
+		var line map[string]interface{}
+		require.NoError(t, decoder.Decode(&line))
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestNew_LevelAndFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "warn", "")
+	require.NoError(t, err)
+	logger.Info("hidden")
+	logger.Warn("shown", "key", "value")
+
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "shown", lines[0]["msg"])
+	assert.Equal(t, "WARN", lines[0]["level"])
+	assert.Equal(t, "value", lines[0]["key"])
+
+	buf.Reset()
+	logger, err = New(&buf, "DEBUG", "text")
+	require.NoError(t, err)
+	logger.Debug("debugging")
+	assert.Contains(t, buf.String(), "level=DEBUG msg=debugging")
+
+	_, err = New(&buf, "verbose", "json")
+	assert.Error(t, err)
+	_, err = New(&buf, "info", "xml")
+	assert.Error(t, err)
+}
+
+func TestNew_AddsRequestIDFromContext(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "info", "json")
+	require.NoError(t, err)
+
+	logger.With("component", "test").InfoContext(requestid.NewContext(context.Background(), "abc123"), "with id")
+	logger.InfoContext(context.Background(), "without id")
+
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 2)
+	assert.Equal(t, "abc123", lines[0][KeyRequestID])
+	assert.Equal(t, "test", lines[0]["component"])
+	assert.NotContains(t, lines[1], KeyRequestID)
+}
+
+func TestNew_AddsTraceFromContext(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "info", "json")
+	require.NoError(t, err)
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+	logger.InfoContext(ctx, "traced")
+
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", lines[0][KeyTraceID])
+	assert.Equal(t, "00f067aa0ba902b7", lines[0][KeySpanID])
+}
+
+func TestMiddleware_LogsRequest(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "info", "json")
+	require.NoError(t, err)
+	previous := slog.Default()
+	slog.SetDefault(logger)
+	defer slog.SetDefault(previous)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(requestid.Middleware(), Middleware())
+	r.GET("/items/:id", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	r.GET("/broken", func(c *gin.Context) {
+		_ = c.Error(assert.AnError)
+		c.Status(http.StatusInternalServerError)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/items/42", nil)
+	req.Header.Set(requestid.Header, "req-1")
+	r.ServeHTTP(w, req)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/broken", nil)
+	r.ServeHTTP(w, req)
+
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 2)
+	assert.Equal(t, "INFO", lines[0]["level"])
+	assert.Equal(t, "GET", lines[0]["method"])
+	assert.Equal(t, "/items/:id", lines[0]["route"])
+	assert.Equal(t, "/items/42", lines[0]["path"])
+	assert.Equal(t, float64(http.StatusOK), lines[0]["status"])
+	assert.Equal(t, "req-1", lines[0][KeyRequestID])
+	assert.Contains(t, lines[0], "duration_ms")
+
+	assert.Equal(t, "ERROR", lines[1]["level"])
+	assert.Equal(t, assert.AnError.Error(), lines[1]["error"])
+	assert.Equal(t, w.Header().Get(requestid.Header), lines[1][KeyRequestID])
+}
@@ -0,0 +1,46 @@
+package logging
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Middleware writes one access log line per request once it has been handled. Server errors are
+// logged at error level, client errors at warn level and everything else at info level.
+// It must run after the requestid middleware so the line carries the request identifier.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("route", route),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Int("bytes", c.Writer.Size()),
+			Duration(time.Since(start)),
+			slog.String("client_ip", c.ClientIP()),
+		}
+		if last := c.Errors.Last(); last != nil {
+			attrs = append(attrs, slog.String("error", last.Error()))
+		}
+		slog.LogAttrs(c.Request.Context(), level, "request handled", attrs...)
+	}
+}
@@ -0,0 +1,126 @@
+// Package metrics collects Prometheus metrics about HTTP requests, MongoDB commands, data imports
+// and stored SWIFT codes, and exposes them in the Prometheus text format.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"swift-app/internal/models"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes every metric name.
+const namespace = "swift"
+
+// unmatchedRoute labels requests that matched no route, so unknown paths cannot inflate the label set.
+const unmatchedRoute = "unmatched"
+
+// Import row outcomes and rejection reasons.
+const (
+	OutcomeAccepted = "accepted"
+	OutcomeRejected = "rejected"
+
+	ReasonAdded              = "added"
+	ReasonDuplicate          = "duplicate"
+	ReasonMissingHeadquarter = "missing_headquarter"
+)
+
+// Metrics holds the collectors of the application. All of them are registered on Registry.
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	httpRequests   *prometheus.CounterVec
+	httpDuration   *prometheus.HistogramVec
+	mongoDuration  *prometheus.HistogramVec
+	mongoErrors    *prometheus.CounterVec
+	importDuration prometheus.Histogram
+	importRows     *prometheus.CounterVec
+}
+
+// New creates the application metrics and registers them, together with Go runtime and process
+// metrics, on registry. Tests pass their own registry to assert on the collected values.
+func New(registry *prometheus.Registry) *Metrics {
+	m := &Metrics{
+		Registry: registry,
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests handled, by method, route and status.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency, by method, route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		mongoDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "mongo_command_duration_seconds",
+			Help:      "MongoDB command latency, by command name.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"command"}),
+		mongoErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "mongo_command_errors_total",
+			Help:      "MongoDB commands that failed, by command name.",
+		}, []string{"command"}),
+		importDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "import_duration_seconds",
+			Help:      "Duration of import runs started by this server process.",
+			Buckets:   prometheus.ExponentialBuckets(0.1, 2, 12),
+		}),
+		importRows: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "import_rows_total",
+			Help:      "Rows imported by this server process, by kind (headquarter or branch), outcome and reason.",
+		}, []string{"kind", "outcome", "reason"}),
+	}
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests, m.httpDuration, m.mongoDuration, m.mongoErrors, m.importDuration, m.importRows,
+	)
+	return m
+}
+
+// Handler serves the registered metrics in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{Registry: m.Registry})
+}
+
+// Middleware counts requests and observes their latency by method, route template and status.
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		m.httpRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		m.httpDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObserveImport records the duration of an import run and the rows it accepted and rejected.
+// Only the server's own import (serve --import-on-start) is observed: the import command runs in
+// a separate process whose metrics no server exposes, so its summary is printed instead.
+func (m *Metrics) ObserveImport(duration time.Duration, summary *models.ImportSummary) {
+	m.importDuration.Observe(duration.Seconds())
+	if summary == nil {
+		return
+	}
+	m.importRows.WithLabelValues("headquarter", OutcomeAccepted, ReasonAdded).Add(float64(summary.HQAdded))
+	m.importRows.WithLabelValues("headquarter", OutcomeRejected, ReasonDuplicate).Add(float64(summary.HQSkipped))
+	m.importRows.WithLabelValues("branch", OutcomeAccepted, ReasonAdded).Add(float64(summary.BranchesAdded))
+	m.importRows.WithLabelValues("branch", OutcomeRejected, ReasonDuplicate).Add(float64(summary.BranchesDuplicate))
+	m.importRows.WithLabelValues("branch", OutcomeRejected, ReasonMissingHeadquarter).Add(float64(summary.BranchesMissingHQ))
+}
@@ -0,0 +1,115 @@
+// metrics_test.go contains unit tests for the HTTP, MongoDB, import and SWIFT code total metrics.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"swift-app/internal/models"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+func TestMiddleware_CountsRequestsByRoute(t *testing.T) {
+	m := New(prometheus.NewRegistry())
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(m.Middleware())
+	r.GET("/items/:id", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for _, path := range []string{"/items/1", "/items/2", "/missing"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.httpRequests.WithLabelValues("GET", "/items/:id", "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.httpRequests.WithLabelValues("GET", unmatchedRoute, "404")))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.httpDuration))
+}
+
+func TestCommandMonitor(t *testing.T) {
+	m := New(prometheus.NewRegistry())
+	monitor := m.CommandMonitor()
+
+	monitor.Succeeded(context.Background(), &event.CommandSucceededEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "find", Duration: 2 * time.Millisecond},
+	})
+	monitor.Failed(context.Background(), &event.CommandFailedEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "insert", Duration: time.Millisecond},
+	})
+
+	assert.Equal(t, 2, testutil.CollectAndCount(m.mongoDuration))
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.mongoErrors.WithLabelValues("find")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.mongoErrors.WithLabelValues("insert")))
+}
+
+func TestObserveImport(t *testing.T) {
+	m := New(prometheus.NewRegistry())
+	m.ObserveImport(3*time.Second, &models.ImportSummary{
+		HQAdded:           10,
+		HQSkipped:         2,
+		BranchesAdded:     30,
+		BranchesDuplicate: 4,
+		BranchesMissingHQ: 5,
+		BranchesSkipped:   9,
+	})
+
+	expected := `
+# HELP swift_import_rows_total Rows imported by this server process, by kind (headquarter or branch), outcome and reason.
+# TYPE swift_import_rows_total counter
+swift_import_rows_total{kind="branch",outcome="accepted",reason="added"} 30
+swift_import_rows_total{kind="branch",outcome="rejected",reason="duplicate"} 4
+swift_import_rows_total{kind="branch",outcome="rejected",reason="missing_headquarter"} 5
+swift_import_rows_total{kind="headquarter",outcome="accepted",reason="added"} 10
+swift_import_rows_total{kind="headquarter",outcome="rejected",reason="duplicate"} 2
+`
+	assert.NoError(t, testutil.CollectAndCompare(m.importRows, strings.NewReader(expected)))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.importDuration))
+}
+
+func TestRegisterCountryTotals(t *testing.T) {
+	m := New(prometheus.NewRegistry())
+	var fail bool
+	require.NoError(t, m.RegisterCountryTotals(func(context.Context) ([]models.CountryTotals, error) {
+		if fail {
+			return nil, errors.New("database unavailable")
+		}
+		return []models.CountryTotals{{CountryISO2: "PL", Headquarters: 3, Branches: 7}}, nil
+	}))
+
+	expected := `
+# HELP swift_codes Stored SWIFT codes, by country and type (headquarter or branch).
+# TYPE swift_codes gauge
+swift_codes{country_iso2="PL",type="branch"} 7
+swift_codes{country_iso2="PL",type="headquarter"} 3
+`
+	assert.NoError(t, testutil.GatherAndCompare(m.Registry, strings.NewReader(expected), "swift_codes"))
+
+	// A failing count leaves the gauges out instead of failing the scrape.
+	fail = true
+	count, err := testutil.GatherAndCount(m.Registry, "swift_codes")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestHandler(t *testing.T) {
+	m := New(prometheus.NewRegistry())
+	m.ObserveImport(time.Second, nil)
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "swift_import_duration_seconds_count 1")
+	assert.Contains(t, w.Body.String(), "go_goroutines")
+}
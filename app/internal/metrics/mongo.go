@@ -0,0 +1,21 @@
+package metrics
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// CommandMonitor returns a MongoDB command monitor that observes the latency of every command and
+// counts failed ones. Install it with options.Client().SetMonitor.
+func (m *Metrics) CommandMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			m.mongoDuration.WithLabelValues(e.CommandName).Observe(e.Duration.Seconds())
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			m.mongoDuration.WithLabelValues(e.CommandName).Observe(e.Duration.Seconds())
+			m.mongoErrors.WithLabelValues(e.CommandName).Inc()
+		},
+	}
+}
@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"swift-app/internal/models"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TotalsFunc returns the number of stored headquarters and branches per country.
+type TotalsFunc func(ctx context.Context) ([]models.CountryTotals, error)
+
+// totalsCollector reports the stored SWIFT codes per country. The totals are read when the metrics
+// are scraped, so they always match the database, including changes made by other instances.
+type totalsCollector struct {
+	totals TotalsFunc
+	desc   *prometheus.Desc
+}
+
+// RegisterCountryTotals reports gauges of the headquarters and branches per country returned by totals.
+func (m *Metrics) RegisterCountryTotals(totals TotalsFunc) error {
+	return m.Registry.Register(&totalsCollector{
+		totals: totals,
+		desc: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "codes"),
+			"Stored SWIFT codes, by country and type (headquarter or branch).",
+			[]string{"country_iso2", "type"}, nil),
+	})
+}
+
+func (c *totalsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *totalsCollector) Collect(ch chan<- prometheus.Metric) {
+	totals, err := c.totals(context.Background())
+	if err != nil {
+		// Skip the gauges rather than failing the whole scrape.
+		slog.Warn("failed to count SWIFT codes for metrics", "error", err)
+		return
+	}
+	for _, country := range totals {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(country.Headquarters), country.CountryISO2, "headquarter")
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(country.Branches), country.CountryISO2, "branch")
+	}
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// APIKey is an API key stored in the database. Only the SHA-256 hash of the key is kept;
+// Prefix holds the first characters of the key so it can be recognized.
+type APIKey struct {
+	ID         string     `json:"id" bson:"_id"`
+	Owner      string     `json:"owner" bson:"owner"`
+	Prefix     string     `json:"prefix" bson:"prefix"`
+	KeyHash    string     `json:"-" bson:"keyHash"`
+	Scopes     []string   `json:"scopes" bson:"scopes"`
+	RateLimit  int        `json:"rateLimit,omitempty" bson:"rateLimit,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt" bson:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty" bson:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty" bson:"lastUsedAt,omitempty"`
+	RotatedAt  *time.Time `json:"rotatedAt,omitempty" bson:"rotatedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty" bson:"revokedAt,omitempty"`
+}
+
+// CreateAPIKeyRequest is the request body for issuing a new API key.
+// RateLimit caps the read and write requests per minute allowed for the key below the server-wide limits; 0 uses the server default.
+type CreateAPIKeyRequest struct {
+	Owner     string     `json:"owner" binding:"required,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,oneof=swift:read swift:write swift:admin"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	RateLimit int        `json:"rateLimit,omitempty" binding:"min=0"`
+}
+
+// APIKeyCreatedResponse is returned when a key is issued or rotated. Key is shown only once.
+type APIKeyCreatedResponse struct {
+	Key string `json:"key"`
+	APIKey
+}
+
+// APIKeysResponse lists stored API keys.
+type APIKeysResponse struct {
+	Keys []APIKey `json:"keys"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Audit outcomes.
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+)
+
+// AuditEntry is an append-only record of a mutating API call.
+type AuditEntry struct {
+	Timestamp  time.Time `json:"timestamp" bson:"timestamp"`
+	Actor      string    `json:"actor" bson:"actor"`
+	ClientIP   string    `json:"clientIP" bson:"clientIP"`
+	RequestID  string    `json:"requestId" bson:"requestId"`
+	Method     string    `json:"method" bson:"method"`
+	Route      string    `json:"route" bson:"route"`
+	SwiftCodes []string  `json:"swiftCodes" bson:"swiftCodes"`
+	Status     int       `json:"status" bson:"status"`
+	Outcome    string    `json:"outcome" bson:"outcome"`
+	// Error and ErrorCode are what the client was told; the underlying cause is only logged.
+	Error     string `json:"error,omitempty" bson:"error,omitempty"`
+	ErrorCode string `json:"errorCode,omitempty" bson:"errorCode,omitempty"`
+}
+
+// AuditEntriesResponse lists audit entries matching a query, oldest first.
+type AuditEntriesResponse struct {
+	Entries []AuditEntry `json:"entries"`
+}
@@ -0,0 +1,14 @@
+package models
+
+// CacheStats reports the effectiveness of the read-through cache.
+type CacheStats struct {
+	Enabled       bool    `json:"enabled"`
+	Entries       int     `json:"entries"`
+	Capacity      int     `json:"capacity"`
+	TTLSeconds    int64   `json:"ttlSeconds"`
+	Hits          int64   `json:"hits"`
+	Misses        int64   `json:"misses"`
+	Evictions     int64   `json:"evictions"`
+	Invalidations int64   `json:"invalidations"`
+	HitRatio      float64 `json:"hitRatio"`
+}
@@ -1,7 +1,66 @@
 package models
 
-// Country represents a country with its ISO2 code and full name.
+import "time"
+
+// Country is an entry of the country reference data. Overridden entries are stored in the
+// database as they are returned, with the time and actor of the override. Aliases come from the
+// embedded alias table and are not stored.
 type Country struct {
-	ISO2 string `json:"iso2"`
-	Name string `json:"name"`
+	ISO2       string         `json:"iso2" bson:"_id" example:"PL"`
+	ISO3       string         `json:"iso3,omitempty" bson:"iso3,omitempty" example:"POL"`
+	Numeric    string         `json:"numeric,omitempty" bson:"numeric,omitempty" example:"616"`
+	Name       string         `json:"name" bson:"name" example:"POLAND"`
+	Currency   string         `json:"currency,omitempty" bson:"currency,omitempty" example:"PLN"`
+	IBANLength int            `json:"ibanLength,omitempty" bson:"ibanLength,omitempty" example:"28"`
+	SEPA       bool           `json:"sepa" bson:"sepa"`
+	Aliases    []CountryAlias `json:"aliases,omitempty" bson:"-"`
+	UpdatedAt  *time.Time     `json:"updatedAt,omitempty" bson:"updatedAt,omitempty"`
+	UpdatedBy  string         `json:"updatedBy,omitempty" bson:"updatedBy,omitempty"`
+}
+
+// Alias kinds.
+const (
+	CountryAliasOfficial = "official"
+	CountryAliasShort    = "short"
+	CountryAliasCommon   = "common"
+)
+
+// CountryAlias is another name of a country: its official, short or common name in a language,
+// given as a BCP 47 tag.
+type CountryAlias struct {
+	Name     string `json:"name" example:"Polska"`
+	Kind     string `json:"kind" enums:"official,short,common" example:"short"`
+	Language string `json:"language" example:"pl"`
+}
+
+// Overridden reports whether the entry was set through the admin API rather than taken from the
+// embedded table.
+func (c Country) Overridden() bool {
+	return c.UpdatedAt != nil
+}
+
+// CountryRequest is the request body for overriding or adding a country. It replaces the whole entry.
+type CountryRequest struct {
+	ISO3       string `json:"iso3,omitempty" binding:"omitempty,len=3,alpha"`
+	Numeric    string `json:"numeric,omitempty" binding:"omitempty,len=3,numeric"`
+	Name       string `json:"name" binding:"required,max=100"`
+	Currency   string `json:"currency,omitempty" binding:"omitempty,len=3,alpha"`
+	IBANLength int    `json:"ibanLength,omitempty" binding:"omitempty,min=15,max=34"`
+	SEPA       bool   `json:"sepa"`
+}
+
+// CountriesResponse lists the country reference data. Version identifies the embedded table;
+// Overrides counts the entries changed through the admin API, last at UpdatedAt.
+type CountriesResponse struct {
+	Version   string     `json:"version" example:"2026.1"`
+	Overrides int        `json:"overrides"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+	Countries []Country  `json:"countries"`
+}
+
+// CountryTotals counts the headquarters and branches stored for a country.
+type CountryTotals struct {
+	CountryISO2  string `bson:"_id" json:"countryISO2"`
+	Headquarters int    `bson:"headquarters" json:"headquarters"`
+	Branches     int    `bson:"branches" json:"branches"`
 }
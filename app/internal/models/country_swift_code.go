@@ -1,9 +1,31 @@
 package models
 
+import (
+	"slices"
+	"time"
+)
+
 // CountrySwiftCodesResponse represents the response format for retrieving SWIFT codes by country.
 // It includes the country ISO2 code, full country name, and a list of associated branch SWIFT codes.
 type CountrySwiftCodesResponse struct {
 	CountryISO2 string        `json:"countryISO2"`
 	CountryName string        `json:"countryName"`
 	SwiftCodes  []SwiftBranch `json:"swiftCodes"`
+	// LastModified is the latest change to any listed headquarter or delete of a code of the country.
+	// It is served as a header.
+	LastModified *time.Time `json:"-"`
+}
+
+// Clone returns a deep copy of the listing, so its SWIFT codes can be changed without affecting r.
+func (r *CountrySwiftCodesResponse) Clone() *CountrySwiftCodesResponse {
+	if r == nil {
+		return nil
+	}
+	clone := *r
+	clone.SwiftCodes = slices.Clone(r.SwiftCodes)
+	if r.LastModified != nil {
+		lastModified := *r.LastModified
+		clone.LastModified = &lastModified
+	}
+	return &clone
 }
@@ -0,0 +1,23 @@
+package models
+
+// Health statuses of the service and of individual checks.
+const (
+	HealthStatusUp       = "up"
+	HealthStatusDown     = "down"
+	HealthStatusReady    = "ready"
+	HealthStatusNotReady = "not_ready"
+)
+
+// HealthResponse reports the overall status of the service and, for readiness, each dependency check.
+type HealthResponse struct {
+	Status string                 `json:"status" enums:"up,ready,not_ready" example:"ready"`
+	Checks map[string]HealthCheck `json:"checks,omitempty"`
+}
+
+// HealthCheck is the outcome of one readiness check.
+type HealthCheck struct {
+	Status    string                 `json:"status" enums:"up,down" example:"up"`
+	LatencyMs float64                `json:"latencyMs" example:"1.42"`
+	Error     string                 `json:"error,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
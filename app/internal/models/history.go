@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// History operations recorded for a SWIFT code.
+const (
+	HistoryOperationCreate = "create"
+	HistoryOperationUpdate = "update"
+	HistoryOperationDelete = "delete"
+)
+
+// History sources describing where a change originated.
+const (
+	HistorySourceAPI     = "api"
+	HistorySourceImport  = "import"
+	HistorySourceMigrate = "migrate"
+)
+
+// HistoryEntry is a single versioned change of a headquarter document.
+// Before and After hold full snapshots of the headquarter (including its branches)
+// around the change; After is empty when the headquarter was deleted.
+type HistoryEntry struct {
+	SwiftCode       string     `json:"swiftCode" bson:"swiftCode"`
+	HeadquarterCode string     `json:"headquarterCode" bson:"headquarterCode"`
+	Version         int64      `json:"version" bson:"version"`
+	Operation       string     `json:"operation" bson:"operation"`
+	Source          string     `json:"source" bson:"source"`
+	Timestamp       time.Time  `json:"timestamp" bson:"timestamp"`
+	Before          *SwiftCode `json:"before,omitempty" bson:"before,omitempty"`
+	After           *SwiftCode `json:"after,omitempty" bson:"after,omitempty"`
+}
+
+// SwiftCodeHistoryResponse lists the recorded changes affecting a SWIFT code, oldest first.
+type SwiftCodeHistoryResponse struct {
+	SwiftCode string         `json:"swiftCode"`
+	Entries   []HistoryEntry `json:"entries"`
+}
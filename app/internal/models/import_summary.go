@@ -1,11 +1,28 @@
 package models
 
+import "log/slog"
+
 // ImportSummary holds statistics about the import process.
 type ImportSummary struct {
-	HQAdded           int
-	HQSkipped         int
-	BranchesAdded     int
-	BranchesDuplicate int
-	BranchesMissingHQ int
-	BranchesSkipped   int
+	// HQRemoved counts the headquarters deleted, with their branches, before a replacing import.
+	HQRemoved         int `json:"hqRemoved"`
+	HQAdded           int `json:"hqAdded"`
+	HQSkipped         int `json:"hqSkipped"`
+	BranchesAdded     int `json:"branchesAdded"`
+	BranchesDuplicate int `json:"branchesDuplicate"`
+	BranchesMissingHQ int `json:"branchesMissingHQ"`
+	BranchesSkipped   int `json:"branchesSkipped"`
+}
+
+// LogValue implements slog.LogValuer, so the counters are logged as a group of structured fields.
+func (s ImportSummary) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("hq_removed", s.HQRemoved),
+		slog.Int("hq_added", s.HQAdded),
+		slog.Int("hq_skipped", s.HQSkipped),
+		slog.Int("branches_added", s.BranchesAdded),
+		slog.Int("branches_duplicate", s.BranchesDuplicate),
+		slog.Int("branches_missing_hq", s.BranchesMissingHQ),
+		slog.Int("branches_skipped", s.BranchesSkipped),
+	)
 }
@@ -0,0 +1,29 @@
+package models
+
+// Outcomes of looking up a single SWIFT code in a batch.
+const (
+	LookupStatusHeadquarter = "found_headquarter"
+	LookupStatusBranch      = "found_branch"
+	LookupStatusNotFound    = "not_found"
+	LookupStatusInvalid     = "invalid"
+)
+
+// LookupRequest lists the SWIFT codes to resolve in one batch.
+type LookupRequest struct {
+	SwiftCodes []string `json:"swiftCodes"`
+}
+
+// LookupResult is the outcome for one requested SWIFT code. Details has the same shape as the
+// single-code endpoint (a headquarter with branches, or a branch) and is set only when the code was found;
+// Message explains why an invalid or missing code was not resolved.
+type LookupResult struct {
+	SwiftCode string      `json:"swiftCode"`
+	Status    string      `json:"status"`
+	Details   interface{} `json:"details,omitempty"`
+	Message   string      `json:"message,omitempty"`
+}
+
+// LookupResponse holds one result per requested SWIFT code, in request order.
+type LookupResponse struct {
+	Results []LookupResult `json:"results"`
+}
@@ -1,6 +1,36 @@
 package models
 
 // MessageResponse represents a generic message returned in API responses.
+// Errors use it only in the legacy error format; see ProblemDetails.
 type MessageResponse struct {
 	Message string `json:"message"`
 }
+
+// DeleteSwiftCodeResponse reports the SWIFT codes removed by a delete, or with DryRun the codes that would be removed.
+type DeleteSwiftCodeResponse struct {
+	Message      string   `json:"message"`
+	DryRun       bool     `json:"dryRun"`
+	DeletedCodes []string `json:"deletedCodes"`
+}
+
+// ProblemDetails is an RFC 7807 error response, served as application/problem+json.
+// Code is stable and meant for programs; Detail is meant for humans and may change.
+type ProblemDetails struct {
+	Type     string   `json:"type" example:"urn:swift-app:problem:swift-invalid-length"`
+	Title    string   `json:"title" example:"Bad Request"`
+	Status   int      `json:"status" example:"400"`
+	Detail   string   `json:"detail" example:"SWIFT code must be 8 or 11 characters"`
+	Instance string   `json:"instance,omitempty" example:"/v1/swift-codes/ABC"`
+	Code     string   `json:"code" enums:"BAD_REQUEST,UNAUTHORIZED,FORBIDDEN,NOT_FOUND,CONFLICT,PRECONDITION_FAILED,RATE_LIMITED,INTERNAL_ERROR,TIMEOUT,VALIDATION_FAILED,FIELD_REQUIRED,FIELD_TOO_SHORT,FIELD_TOO_LONG,FIELD_INVALID_FORMAT,FIELD_VALUE_NOT_ALLOWED,UNKNOWN_FIELD,INVALID_BODY,INVALID_QUERY,INVALID_TIME,ROUTE_NOT_FOUND,SWIFT_MISSING,SWIFT_INVALID_LENGTH,SWIFT_INVALID_CHARACTERS,SWIFT_HQ_SUFFIX_REQUIRED,SWIFT_BRANCH_SUFFIX_RESERVED,HQ_NOT_FOUND,HQ_EXISTS,BRANCH_NOT_FOUND,BRANCH_EXISTS,BRANCH_COUNTRY_MISMATCH,CONCURRENT_MODIFICATION,ETAG_MISMATCH,LOOKUP_EMPTY,LOOKUP_TOO_LARGE,HISTORY_NOT_FOUND,COUNTRY_INVALID_ISO2,COUNTRY_NOT_FOUND,COUNTRY_NAME_MISMATCH,COUNTRY_NO_SWIFT_CODES,COUNTRY_NOT_OVERRIDDEN,INVALID_CREDENTIALS,API_KEY_REVOKED,API_KEY_EXPIRED,API_KEY_NOT_FOUND,INVALID_SCOPE"`
+	Fields   []string `json:"fields,omitempty" example:"/swiftCode"`
+	// Errors lists every invalid input field with its reason.
+	Errors    []FieldError `json:"errors,omitempty"`
+	RequestID string       `json:"requestId,omitempty"`
+}
+
+// FieldError explains why one input field was rejected. Field is a JSON pointer.
+type FieldError struct {
+	Field   string `json:"field" example:"/countryName"`
+	Code    string `json:"code" example:"COUNTRY_NAME_MISMATCH"`
+	Message string `json:"message" example:"country name 'GERMANY' does not match ISO2 'PL'"`
+}
@@ -2,24 +2,72 @@
 // including SWIFT code structures for headquarters and branches.
 package models
 
+import (
+	"slices"
+	"time"
+)
+
 // SwiftCode represents a SWIFT headquarter record, including address, bank details,
 // and any associated branch information.
 type SwiftCode struct {
-	Address       string        `json:"address"`
-	BankName      string        `json:"bankName"`
-	CountryISO2   string        `json:"countryISO2"`
-	CountryName   string        `json:"countryName"`
-	IsHeadquarter bool          `json:"isHeadquarter"`
-	SwiftCode     string        `json:"swiftCode"`
-	Branches      []SwiftBranch `json:"branches"`
+	Address       string        `json:"address" bson:"address"`
+	BankName      string        `json:"bankName" bson:"bankName"`
+	CountryISO2   string        `json:"countryISO2" bson:"countryISO2"`
+	CountryName   string        `json:"countryName" bson:"countryName"`
+	IsHeadquarter bool          `json:"isHeadquarter" bson:"isHeadquarter"`
+	SwiftCode     string        `json:"swiftCode" bson:"swiftCode"`
+	Branches      []SwiftBranch `json:"branches" bson:"branches"`
+	// LastModified is when the headquarter document (including its branches) last changed.
+	// It is served as the Last-Modified header rather than in the body.
+	LastModified *time.Time `json:"-" bson:"lastModified,omitempty"`
+	// Version is incremented on every change to the headquarter document and guards conditional updates.
+	Version int64 `json:"-" bson:"version,omitempty"`
+}
+
+// Clone returns a deep copy of the SWIFT code, so its branches can be changed without affecting s.
+func (s *SwiftCode) Clone() *SwiftCode {
+	if s == nil {
+		return nil
+	}
+	clone := *s
+	clone.Branches = slices.Clone(s.Branches)
+	if s.LastModified != nil {
+		lastModified := *s.LastModified
+		clone.LastModified = &lastModified
+	}
+	return &clone
+}
+
+// AddSwiftCodeRequest is the request body for adding a headquarter or branch SWIFT code.
+// Branches of a headquarter are added one by one, so they cannot be submitted here.
+type AddSwiftCodeRequest struct {
+	Address       string `json:"address" binding:"max=256"`
+	BankName      string `json:"bankName" binding:"required,max=256"`
+	CountryISO2   string `json:"countryISO2" binding:"required,len=2,alpha"`
+	CountryName   string `json:"countryName" binding:"required,max=100"`
+	IsHeadquarter bool   `json:"isHeadquarter"`
+	// SwiftCode has 8 characters, or 11 including the branch code.
+	SwiftCode string `json:"swiftCode" binding:"required,swiftlen,alphanum" minLength:"8" maxLength:"11"`
+}
+
+// ToSwiftCode converts the request into the stored model.
+func (r AddSwiftCodeRequest) ToSwiftCode() *SwiftCode {
+	return &SwiftCode{
+		Address:       r.Address,
+		BankName:      r.BankName,
+		CountryISO2:   r.CountryISO2,
+		CountryName:   r.CountryName,
+		IsHeadquarter: r.IsHeadquarter,
+		SwiftCode:     r.SwiftCode,
+	}
 }
 
 // SwiftBranch represents a branch of a SWIFT headquarter.
 type SwiftBranch struct {
-	Address       string `json:"address"`
-	BankName      string `json:"bankName"`
-	CountryISO2   string `json:"countryISO2"`
-	CountryName   string `json:"countryName,omitempty"`
-	IsHeadquarter bool   `json:"isHeadquarter"`
-	SwiftCode     string `json:"swiftCode"`
+	Address       string `json:"address" bson:"address"`
+	BankName      string `json:"bankName" bson:"bankName"`
+	CountryISO2   string `json:"countryISO2" bson:"countryISO2"`
+	CountryName   string `json:"countryName,omitempty" bson:"countryName,omitempty"`
+	IsHeadquarter bool   `json:"isHeadquarter" bson:"isHeadquarter"`
+	SwiftCode     string `json:"swiftCode" bson:"swiftCode"`
 }
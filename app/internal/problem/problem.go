@@ -0,0 +1,115 @@
+// Package problem writes API errors as RFC 7807 problem details (application/problem+json), or as
+// the legacy {"message": "..."} body for clients that have not migrated yet.
+package problem
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"swift-app/internal/errors"
+	"swift-app/internal/models"
+	"swift-app/internal/requestid"
+	"swift-app/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContentType is the media type of problem detail responses.
+const ContentType = "application/problem+json"
+
+// typePrefix namespaces problem types; the suffix is derived from the error code.
+const typePrefix = "urn:swift-app:problem:"
+
+// Format selects how errors are written.
+type Format string
+
+const (
+	// FormatProblem writes RFC 7807 problem details. It is the default.
+	FormatProblem Format = "problem"
+	// FormatLegacy writes {"message": "..."} with application/json, as before problem details were introduced.
+	FormatLegacy Format = "legacy"
+)
+
+// ParseFormat parses an error format name. An empty name selects FormatProblem.
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(name))) {
+	case "", FormatProblem:
+		return FormatProblem, nil
+	case FormatLegacy:
+		return FormatLegacy, nil
+	}
+	return "", fmt.Errorf("unknown error format %q, expected %q or %q", name, FormatProblem, FormatLegacy)
+}
+
+// Middleware makes every error written for the request use the given format.
+func Middleware(format Format) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(utils.ContextKeyErrorFormat, format)
+		c.Next()
+	}
+}
+
+// Respond attaches err to the request, so middleware such as the audit trail can see the outcome,
+// and writes it in the request's error format with the status code carried by the error.
+// Errors caused by an expired deadline are answered with 504 Gateway Timeout.
+func Respond(c *gin.Context, err error) {
+	err = errors.FromDeadline(err)
+	_ = c.Error(err)
+	problem := Details(c, err)
+	if format(c) == FormatLegacy {
+		c.JSON(problem.Status, models.MessageResponse{Message: problem.Detail})
+		return
+	}
+	// Gin keeps a Content-Type that is already set, so the JSON body goes out as problem+json.
+	c.Header("Content-Type", ContentType)
+	c.JSON(problem.Status, problem)
+}
+
+// Abort is Respond for middleware: it also stops the remaining handlers.
+func Abort(c *gin.Context, err error) {
+	Respond(c, err)
+	c.Abort()
+}
+
+// internalDetail is sent for errors that are not an AppError. Their text may come from the store
+// or another dependency, so it only reaches the logs through the error attached to the request.
+const internalDetail = "internal server error"
+
+// Details describes err as problem details for the current request.
+func Details(c *gin.Context, err error) models.ProblemDetails {
+	err = errors.FromDeadline(err)
+	problem := models.ProblemDetails{
+		Status:    errors.GetStatusCode(err),
+		Detail:    internalDetail,
+		Code:      errors.CodeInternal,
+		RequestID: requestid.Get(c),
+	}
+	if c.Request != nil {
+		problem.Instance = c.Request.URL.Path
+	}
+	if appErr, ok := errors.As(err); ok {
+		// The cause is for logs only; clients get the message.
+		problem.Detail = appErr.Message
+		problem.Code = appErr.Code
+		problem.Fields = appErr.Fields
+		for _, violation := range appErr.Violations {
+			problem.Errors = append(problem.Errors, models.FieldError{
+				Field:   violation.Field,
+				Code:    violation.Code,
+				Message: violation.Message,
+			})
+		}
+	}
+	problem.Title = http.StatusText(problem.Status)
+	problem.Type = typePrefix + strings.ReplaceAll(strings.ToLower(problem.Code), "_", "-")
+	return problem
+}
+
+func format(c *gin.Context) Format {
+	if value, ok := c.Get(utils.ContextKeyErrorFormat); ok {
+		if format, ok := value.(Format); ok {
+			return format
+		}
+	}
+	return FormatProblem
+}
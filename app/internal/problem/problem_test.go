@@ -0,0 +1,133 @@
+// problem_test.go contains unit tests for problem details and legacy error responses.
+package problem
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"swift-app/internal/errors"
+	"swift-app/internal/models"
+	"swift-app/internal/requestid"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupRouter(format Format, err error) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(requestid.Middleware(), Middleware(format))
+	r.GET("/codes/:code", func(c *gin.Context) {
+		Respond(c, err)
+	})
+	return r
+}
+
+func perform(r *gin.Engine) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/codes/AB", nil)
+	req.Header.Set(requestid.Header, "req-1")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRespond_ProblemDetails(t *testing.T) {
+	err := errors.Wrap(errors.ErrBadRequest, "SWIFT code must be 8 or 11 characters").
+		WithCode(errors.CodeSwiftInvalidLength).WithFields("/swiftCode")
+	w := perform(setupRouter(FormatProblem, err))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+
+	var problem models.ProblemDetails
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, models.ProblemDetails{
+		Type:      "urn:swift-app:problem:swift-invalid-length",
+		Title:     "Bad Request",
+		Status:    http.StatusBadRequest,
+		Detail:    "SWIFT code must be 8 or 11 characters",
+		Instance:  "/codes/AB",
+		Code:      errors.CodeSwiftInvalidLength,
+		Fields:    []string{"/swiftCode"},
+		RequestID: "req-1",
+	}, problem)
+}
+
+func TestRespond_HidesCause(t *testing.T) {
+	err := errors.Wrap(errors.ErrInternal, "error looking up SWIFT codes").WithCause(assert.AnError)
+	w := perform(setupRouter(FormatProblem, err))
+
+	var problem models.ProblemDetails
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "error looking up SWIFT codes", problem.Detail)
+	assert.Equal(t, errors.CodeInternal, problem.Code)
+	assert.NotContains(t, w.Body.String(), assert.AnError.Error())
+}
+
+func TestRespond_HidesUntypedError(t *testing.T) {
+	err := fmt.Errorf("failed to query SWIFT codes: %w", assert.AnError)
+	var logged error
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Next()
+		logged = c.Errors.Last()
+	})
+	r.GET("/codes/:code", func(c *gin.Context) {
+		Respond(c, err)
+	})
+	w := perform(r)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	var problem models.ProblemDetails
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "internal server error", problem.Detail)
+	assert.Equal(t, errors.CodeInternal, problem.Code)
+	assert.NotContains(t, w.Body.String(), assert.AnError.Error())
+	assert.ErrorIs(t, logged, assert.AnError, "the cause stays attached to the request for logging")
+}
+
+func TestRespond_Timeout(t *testing.T) {
+	err := errors.Wrap(errors.ErrInternal, "error looking up SWIFT codes").WithCause(fmt.Errorf("find: %w", context.DeadlineExceeded))
+	w := perform(setupRouter(FormatProblem, err))
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	var problem models.ProblemDetails
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, errors.CodeTimeout, problem.Code)
+	assert.Equal(t, "Gateway Timeout", problem.Title)
+	assert.Equal(t, "error looking up SWIFT codes: the operation did not finish in time", problem.Detail)
+}
+
+func TestRespond_LegacyFormat(t *testing.T) {
+	err := errors.Wrap(errors.ErrNotFound, "headquarter not found: AAAABBB1XXX").WithCode(errors.CodeHQNotFound)
+	w := perform(setupRouter(FormatLegacy, err))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"message":"headquarter not found: AAAABBB1XXX"}`, w.Body.String())
+}
+
+func TestParseFormat(t *testing.T) {
+	for name, expected := range map[string]Format{"": FormatProblem, "problem": FormatProblem, "LEGACY": FormatLegacy} {
+		format, err := ParseFormat(name)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, format)
+	}
+	_, err := ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestProblemDetails_DocumentsEveryCode(t *testing.T) {
+	field, _ := reflect.TypeOf(models.ProblemDetails{}).FieldByName("Code")
+	documented := strings.Split(field.Tag.Get("enums"), ",")
+	codes := append([]string{}, errors.Codes...)
+	sort.Strings(documented)
+	sort.Strings(codes)
+	assert.Equal(t, codes, documented, "the enums tag of ProblemDetails.Code must list errors.Codes")
+}
@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"swift-app/internal/timeouts"
+	"swift-app/internal/utils"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// quotaRetention is how long daily counters are kept before MongoDB expires them.
+const quotaRetention = 48 * time.Hour
+
+// MongoQuotas keeps daily request counters in MongoDB so quotas survive restarts
+// and are shared between instances.
+type MongoQuotas struct {
+	DB *mongo.Collection
+}
+
+// NewMongoQuotas creates a quota counter backed by the given collection.
+func NewMongoQuotas(db *mongo.Collection) *MongoQuotas {
+	return &MongoQuotas{DB: db}
+}
+
+// CollectionFor returns the quota collection that lives next to the given SWIFT collection.
+func CollectionFor(swiftCollection *mongo.Collection) *mongo.Collection {
+	return swiftCollection.Database().Collection(swiftCollection.Name() + utils.QuotaCollectionSuffix)
+}
+
+// EnsureIndexes creates the TTL index that removes old counters.
+func (q *MongoQuotas) EnsureIndexes(ctx context.Context) error {
+	_, err := q.DB.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: utils.FieldExpireAt, Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create quota index: %v", err)
+	}
+	return nil
+}
+
+// Increment implements QuotaCounter.
+func (q *MongoQuotas) Increment(ctx context.Context, client string, day time.Time) (int64, error) {
+	date := day.UTC().Format(time.DateOnly)
+	ctx, cancel := timeouts.Write(ctx)
+	defer cancel()
+
+	var counter struct {
+		Count int64 `bson:"count"`
+	}
+	err := q.DB.FindOneAndUpdate(ctx,
+		bson.M{"_id": client + "|" + date},
+		bson.M{
+			"$inc":         bson.M{utils.FieldCount: 1},
+			"$setOnInsert": bson.M{utils.FieldClient: client, utils.FieldDay: date, utils.FieldExpireAt: day.UTC().Add(quotaRetention)},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)).Decode(&counter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment quota counter: %v", err)
+	}
+	return counter.Count, nil
+}
@@ -0,0 +1,237 @@
+// Package ratelimit throttles API clients with per-client token buckets, separately for reads,
+// writes and imports, and enforces daily request quotas tracked in MongoDB.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"swift-app/internal/auth"
+	"swift-app/internal/errors"
+	"swift-app/internal/problem"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Class groups routes that share a limit.
+type Class string
+
+// Request classes with separate limits.
+const (
+	ClassRead   Class = "read"
+	ClassWrite  Class = "write"
+	ClassImport Class = "import"
+)
+
+// Standard rate limit response headers.
+const (
+	HeaderLimit     = "RateLimit-Limit"
+	HeaderRemaining = "RateLimit-Remaining"
+	HeaderReset     = "RateLimit-Reset"
+)
+
+// idleBucketTTL is how long an untouched bucket is kept before it is swept.
+const idleBucketTTL = 10 * time.Minute
+
+// Limit is a token bucket refilled at PerMinute tokens per minute holding at most Burst tokens.
+// A zero PerMinute disables limiting; a zero Burst defaults to PerMinute.
+type Limit struct {
+	PerMinute int
+	Burst     int
+}
+
+func (l Limit) burst() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.PerMinute
+}
+
+// Config holds the limits per request class, the limit per IP address applied before
+// authentication and the daily quota per client (0 disables the quota).
+type Config struct {
+	Read       Limit
+	Write      Limit
+	Import     Limit
+	IP         Limit
+	DailyQuota int64
+}
+
+// QuotaCounter counts requests per client and day in durable storage.
+type QuotaCounter interface {
+	// Increment adds one request for the client on the given day and returns the new total.
+	Increment(ctx context.Context, client string, day time.Time) (int64, error)
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Limiter enforces the configured limits. The zero-config Limiter lets every request through.
+type Limiter struct {
+	config    Config
+	quotas    QuotaCounter
+	now       func() time.Time
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// New creates a Limiter. quotas may be nil when no daily quota is configured.
+func New(config Config, quotas QuotaCounter) *Limiter {
+	return &Limiter{
+		config:  config,
+		quotas:  quotas,
+		now:     time.Now,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// limitFor returns the limit of a class for the client. A key's own rate limit can only lower the
+// server-wide read and write limits, never raise them. Imports always use the server-wide limit.
+func (l *Limiter) limitFor(class Class, principal *auth.Principal) Limit {
+	perKey := 0
+	if principal != nil {
+		perKey = principal.RateLimit
+	}
+	switch class {
+	case ClassRead:
+		return capped(l.config.Read, perKey)
+	case ClassWrite:
+		return capped(l.config.Write, perKey)
+	case ClassImport:
+		return l.config.Import
+	}
+	return Limit{}
+}
+
+// capped lowers limit to perKey when that is stricter. A disabled server-wide limit yields perKey.
+func capped(limit Limit, perKey int) Limit {
+	if perKey > 0 && (limit.PerMinute == 0 || perKey < limit.PerMinute) {
+		return Limit{PerMinute: perKey}
+	}
+	return limit
+}
+
+// LimitIP is middleware that applies the per-IP limit. It runs before authentication, so requests
+// with missing or wrong credentials are throttled too and guessing keys cannot flood the key store.
+func (l *Limiter) LimitIP() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !l.allow(c, "ip:"+c.ClientIP()+"|ip", l.config.IP, "requests from this address") {
+			return
+		}
+		c.Next()
+	}
+}
+
+// Limit is middleware that applies the limit of the given class to the calling client.
+// It must run after authentication so clients are keyed by API key rather than by IP.
+func (l *Limiter) Limit(class Class) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := auth.GetPrincipal(c)
+		client := clientKey(c, principal)
+
+		if !l.allow(c, client+"|"+string(class), l.limitFor(class, principal), string(class)+" requests") {
+			return
+		}
+
+		if l.config.DailyQuota > 0 && l.quotas != nil {
+			count, err := l.quotas.Increment(c.Request.Context(), client, l.now())
+			if err != nil {
+				slog.WarnContext(c.Request.Context(), "failed to track daily quota", "client", client, "error", err)
+			} else if count > l.config.DailyQuota {
+				c.Header("Retry-After", strconv.Itoa(secondsUntilMidnight(l.now())))
+				reject(c, fmt.Sprintf("daily quota of %d requests exceeded", l.config.DailyQuota))
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// allow takes a token from the bucket under key, sets the rate limit headers and rejects the
+// request with 429 when the bucket is empty. A disabled limit allows everything.
+func (l *Limiter) allow(c *gin.Context, key string, limit Limit, what string) bool {
+	if limit.PerMinute <= 0 {
+		return true
+	}
+	allowed, remaining, reset := l.take(key, limit)
+	c.Header(HeaderLimit, strconv.Itoa(limit.burst()))
+	c.Header(HeaderRemaining, strconv.Itoa(remaining))
+	c.Header(HeaderReset, strconv.Itoa(reset))
+	if !allowed {
+		c.Header("Retry-After", strconv.Itoa(reset))
+		reject(c, fmt.Sprintf("rate limit exceeded for %s, retry in %d seconds", what, reset))
+	}
+	return allowed
+}
+
+// take removes a token from the bucket and reports whether the request is allowed, how many
+// requests remain and in how many seconds the bucket is full again (or, when empty, when the next token arrives).
+func (l *Limiter) take(key string, limit Limit) (bool, int, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	capacity := float64(limit.burst())
+	ratePerSecond := float64(limit.PerMinute) / 60
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.last).Seconds()*ratePerSecond)
+	b.last = now
+
+	if b.tokens < 1 {
+		wait := int(math.Ceil((1 - b.tokens) / ratePerSecond))
+		return false, 0, wait
+	}
+	b.tokens--
+	reset := int(math.Ceil((capacity - b.tokens) / ratePerSecond))
+	return true, int(b.tokens), reset
+}
+
+// sweep drops buckets that have not been used for a while. Callers must hold l.mu.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < idleBucketTTL {
+		return
+	}
+	for key, b := range l.buckets {
+		if now.Sub(b.last) > idleBucketTTL {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// clientKey identifies the client by API key, authenticated subject or, failing that, IP address.
+func clientKey(c *gin.Context, principal *auth.Principal) string {
+	if principal != nil {
+		if principal.KeyID != "" {
+			return "key:" + principal.KeyID
+		}
+		return "sub:" + principal.Subject
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func secondsUntilMidnight(now time.Time) int {
+	now = now.UTC()
+	midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+	return int(math.Ceil(midnight.Sub(now).Seconds()))
+}
+
+func reject(c *gin.Context, message string) {
+	problem.Abort(c, errors.New(message, http.StatusTooManyRequests))
+}
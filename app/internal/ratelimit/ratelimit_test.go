@@ -0,0 +1,248 @@
+// ratelimit_test.go contains unit tests for the token bucket limiter and daily quotas.
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"swift-app/internal/auth"
+	"swift-app/internal/models"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeQuotas struct {
+	counts map[string]int64
+}
+
+func (f *fakeQuotas) Increment(_ context.Context, client string, day time.Time) (int64, error) {
+	key := client + "|" + day.UTC().Format(time.DateOnly)
+	f.counts[key]++
+	return f.counts[key], nil
+}
+
+type staticProvider struct {
+	principal *auth.Principal
+}
+
+func (p staticProvider) Authenticate(*http.Request) (*auth.Principal, error) {
+	return p.principal, nil
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	return f.now
+}
+
+func setupRouter(limiter *Limiter, principal *auth.Principal) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	guard := auth.NewGuard()
+	if principal != nil {
+		guard = auth.NewGuard(staticProvider{principal: principal})
+	}
+	authenticate := guard.Authenticate()
+	r.GET("/", authenticate, limiter.Limit(ClassRead), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	r.POST("/", authenticate, limiter.Limit(ClassWrite), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+	return r
+}
+
+func newTestLimiter(config Config, quotas QuotaCounter) (*Limiter, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	limiter := New(config, quotas)
+	limiter.now = clock.Now
+	return limiter, clock
+}
+
+func perform(r *gin.Engine, method string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, "/", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestLimit_AllowsBurstThenRejects(t *testing.T) {
+	limiter, _ := newTestLimiter(Config{Read: Limit{PerMinute: 60, Burst: 2}}, nil)
+	r := setupRouter(limiter, nil)
+
+	w := perform(r, "GET")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get(HeaderLimit))
+	assert.Equal(t, "1", w.Header().Get(HeaderRemaining))
+
+	w = perform(r, "GET")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "0", w.Header().Get(HeaderRemaining))
+
+	w = perform(r, "GET")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	var response models.ProblemDetails
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Contains(t, response.Detail, "rate limit exceeded")
+}
+
+func TestLimit_RefillsOverTime(t *testing.T) {
+	limiter, clock := newTestLimiter(Config{Read: Limit{PerMinute: 60, Burst: 1}}, nil)
+	r := setupRouter(limiter, nil)
+
+	assert.Equal(t, http.StatusOK, perform(r, "GET").Code)
+	assert.Equal(t, http.StatusTooManyRequests, perform(r, "GET").Code)
+
+	clock.now = clock.now.Add(time.Second)
+	assert.Equal(t, http.StatusOK, perform(r, "GET").Code)
+}
+
+func TestLimit_SeparatesClasses(t *testing.T) {
+	limiter, _ := newTestLimiter(Config{Read: Limit{PerMinute: 1}, Write: Limit{PerMinute: 1}}, nil)
+	r := setupRouter(limiter, nil)
+
+	assert.Equal(t, http.StatusOK, perform(r, "GET").Code)
+	assert.Equal(t, http.StatusCreated, perform(r, "POST").Code)
+	assert.Equal(t, http.StatusTooManyRequests, perform(r, "GET").Code)
+	assert.Equal(t, http.StatusTooManyRequests, perform(r, "POST").Code)
+}
+
+func TestLimit_KeysClientsByAPIKey(t *testing.T) {
+	limiter, _ := newTestLimiter(Config{Read: Limit{PerMinute: 1}}, nil)
+
+	assert.Equal(t, http.StatusOK, perform(setupRouter(limiter, &auth.Principal{Subject: "alice", KeyID: "k1"}), "GET").Code)
+	assert.Equal(t, http.StatusOK, perform(setupRouter(limiter, &auth.Principal{Subject: "alice", KeyID: "k2"}), "GET").Code)
+	assert.Equal(t, http.StatusOK, perform(setupRouter(limiter, nil), "GET").Code)
+	assert.Equal(t, http.StatusTooManyRequests, perform(setupRouter(limiter, &auth.Principal{Subject: "alice", KeyID: "k1"}), "GET").Code)
+}
+
+func TestLimit_PerKeyOverride(t *testing.T) {
+	limiter, _ := newTestLimiter(Config{Read: Limit{PerMinute: 10}}, nil)
+	r := setupRouter(limiter, &auth.Principal{Subject: "partner", KeyID: "k1", RateLimit: 3})
+
+	for i := 0; i < 3; i++ {
+		w := perform(r, "GET")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "3", w.Header().Get(HeaderLimit))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, perform(r, "GET").Code)
+
+	limiter, _ = newTestLimiter(Config{Read: Limit{PerMinute: 2}}, nil)
+	r = setupRouter(limiter, &auth.Principal{Subject: "partner", KeyID: "k1", RateLimit: 100})
+	for i := 0; i < 2; i++ {
+		w := perform(r, "GET")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2", w.Header().Get(HeaderLimit), "a high per-key limit should not lift the read limit")
+	}
+	assert.Equal(t, http.StatusTooManyRequests, perform(r, "GET").Code)
+}
+
+func TestLimit_PerKeyOverride_CapsWrites(t *testing.T) {
+	limiter, _ := newTestLimiter(Config{Write: Limit{PerMinute: 2}}, nil)
+	r := setupRouter(limiter, &auth.Principal{Subject: "partner", KeyID: "k1", RateLimit: 100})
+
+	for i := 0; i < 2; i++ {
+		w := perform(r, "POST")
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "2", w.Header().Get(HeaderLimit), "a high per-key limit should not lift the write limit")
+	}
+	assert.Equal(t, http.StatusTooManyRequests, perform(r, "POST").Code)
+
+	limiter, _ = newTestLimiter(Config{Write: Limit{PerMinute: 60}}, nil)
+	r = setupRouter(limiter, &auth.Principal{Subject: "partner", KeyID: "k1", RateLimit: 1})
+	assert.Equal(t, http.StatusCreated, perform(r, "POST").Code)
+	assert.Equal(t, http.StatusTooManyRequests, perform(r, "POST").Code, "a lower per-key limit should apply to writes")
+}
+
+func TestLimitIP_ThrottlesUnauthenticated(t *testing.T) {
+	limiter, _ := newTestLimiter(Config{IP: Limit{PerMinute: 2}}, nil)
+	lookups := 0
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(limiter.LimitIP(), func(c *gin.Context) {
+		lookups++
+		c.AbortWithStatus(http.StatusUnauthorized)
+	})
+	r.GET("/", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	assert.Equal(t, http.StatusUnauthorized, perform(r, "GET").Code)
+	assert.Equal(t, http.StatusUnauthorized, perform(r, "GET").Code)
+	w := perform(r, "GET")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code, "failed logins should be throttled by IP")
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	assert.Equal(t, 2, lookups, "a throttled request should not reach authentication")
+}
+
+func TestLimit_DisabledWithoutConfig(t *testing.T) {
+	limiter, _ := newTestLimiter(Config{}, nil)
+	r := setupRouter(limiter, nil)
+
+	for i := 0; i < 100; i++ {
+		w := perform(r, "GET")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get(HeaderLimit))
+	}
+}
+
+func TestLimit_DailyQuota(t *testing.T) {
+	quotas := &fakeQuotas{counts: make(map[string]int64)}
+	limiter, clock := newTestLimiter(Config{DailyQuota: 2}, quotas)
+	r := setupRouter(limiter, nil)
+
+	assert.Equal(t, http.StatusOK, perform(r, "GET").Code)
+	assert.Equal(t, http.StatusCreated, perform(r, "POST").Code)
+
+	w := perform(r, "GET")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "43200", w.Header().Get("Retry-After"))
+
+	clock.now = clock.now.Add(24 * time.Hour)
+	assert.Equal(t, http.StatusOK, perform(r, "GET").Code)
+}
+
+func TestSweep_DropsIdleBuckets(t *testing.T) {
+	limiter, clock := newTestLimiter(Config{Read: Limit{PerMinute: 60}}, nil)
+	r := setupRouter(limiter, nil)
+
+	perform(r, "GET")
+	assert.Len(t, limiter.buckets, 1)
+
+	clock.now = clock.now.Add(2 * idleBucketTTL)
+	limiter.take("other", Limit{PerMinute: 60})
+	assert.Len(t, limiter.buckets, 1)
+	assert.Contains(t, limiter.buckets, "other")
+}
+
+func TestLimit_IgnoresSpoofedForwardedFor(t *testing.T) {
+	limiter, _ := newTestLimiter(Config{Read: Limit{PerMinute: 60, Burst: 1}}, nil)
+	r := setupRouter(limiter, nil)
+	// The server trusts no proxy unless http.trustedProxies is set.
+	assert.NoError(t, r.SetTrustedProxies(nil))
+
+	request := func(forwardedFor string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	assert.Equal(t, http.StatusOK, request("203.0.113.1"))
+	assert.Equal(t, http.StatusTooManyRequests, request("203.0.113.2"), "A new forwarded IP should not get a new bucket")
+
+	// Behind a trusted proxy the forwarded client IP is the key.
+	assert.NoError(t, r.SetTrustedProxies([]string{"192.0.2.1"}))
+	assert.Equal(t, http.StatusOK, request("203.0.113.3"))
+	assert.Equal(t, http.StatusTooManyRequests, request("203.0.113.3"))
+}
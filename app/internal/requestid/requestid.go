@@ -0,0 +1,60 @@
+// Package requestid assigns every HTTP request an identifier, propagating the one sent by the client
+// in the X-Request-ID header or generating a new one, and echoes it back in the response.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Header is the HTTP header carrying the request identifier.
+const Header = "X-Request-ID"
+
+const contextKey = "requestID"
+
+// ctxKey keys the identifier in a request's context.Context, so code that only has the context
+// (such as log handlers) can read it.
+type ctxKey struct{}
+
+// maxLength limits client-supplied identifiers so they cannot bloat logs and audit entries.
+const maxLength = 128
+
+// Middleware assigns the request identifier and stores it in the Gin context.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(Header)
+		if id == "" || len(id) > maxLength {
+			id = New()
+		}
+		c.Set(contextKey, id)
+		c.Request = c.Request.WithContext(NewContext(c.Request.Context(), id))
+		c.Header(Header, id)
+		c.Next()
+	}
+}
+
+// Get returns the identifier assigned to the current request, or an empty string if none was assigned.
+func Get(c *gin.Context) string {
+	return c.GetString(contextKey)
+}
+
+// NewContext returns a copy of ctx carrying the request identifier.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the request identifier carried by ctx, or an empty string.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// New generates a random 128-bit request identifier encoded as hex.
+func New() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
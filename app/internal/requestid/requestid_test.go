@@ -0,0 +1,66 @@
+// requestid_test.go contains unit tests for request ID assignment and propagation.
+package requestid
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware())
+	r.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, Get(c))
+	})
+	return r
+}
+
+func TestMiddleware_GeneratesID(t *testing.T) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/", nil)
+	setupRouter().ServeHTTP(w, req)
+
+	assert.Len(t, w.Body.String(), 32)
+	assert.Equal(t, w.Body.String(), w.Header().Get(Header))
+}
+
+func TestMiddleware_PropagatesID(t *testing.T) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/", nil)
+	req.Header.Set(Header, "client-supplied-id")
+	setupRouter().ServeHTTP(w, req)
+
+	assert.Equal(t, "client-supplied-id", w.Body.String())
+	assert.Equal(t, "client-supplied-id", w.Header().Get(Header))
+}
+
+func TestMiddleware_ReplacesOversizedID(t *testing.T) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/", nil)
+	req.Header.Set(Header, strings.Repeat("a", maxLength+1))
+	setupRouter().ServeHTTP(w, req)
+
+	assert.Len(t, w.Body.String(), 32)
+}
+
+func TestMiddleware_PropagatesIDToRequestContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware())
+	r.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, FromContext(c.Request.Context()))
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/", nil)
+	req.Header.Set(Header, "client-id")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, "client-id", w.Body.String())
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"swift-app/internal/countries"
+	"swift-app/internal/errors"
+	"swift-app/internal/models"
+	"swift-app/internal/timeouts"
+	"swift-app/internal/utils"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CountryService serves the country reference data and keeps its overrides in MongoDB, so they
+// survive restarts. Overrides apply to the registry of this process at once; other processes pick
+// them up when they call LoadOverrides, which the server does on start.
+type CountryService struct {
+	DB       *mongo.Collection
+	Registry *countries.Registry
+}
+
+// NewCountryService creates a CountryService backed by the given country override collection and
+// the default registry.
+func NewCountryService(db *mongo.Collection) *CountryService {
+	return &CountryService{DB: db, Registry: countries.Default()}
+}
+
+// CountryCollectionFor returns the country override collection that lives next to the given SWIFT collection.
+func CountryCollectionFor(swiftCollection *mongo.Collection) *mongo.Collection {
+	return swiftCollection.Database().Collection(swiftCollection.Name() + utils.CountryCollectionSuffix)
+}
+
+// LoadOverrides applies the stored overrides to the registry, replacing the ones applied before.
+func (s *CountryService) LoadOverrides(ctx context.Context) error {
+	ctx, cancel := timeouts.Read(ctx)
+	defer cancel()
+	cursor, err := s.DB.Find(ctx, bson.M{})
+	if err != nil {
+		return errors.Wrap(errors.ErrInternal, "error retrieving country overrides").WithCause(err)
+	}
+	defer cursor.Close(ctx)
+
+	var overrides []models.Country
+	if err := cursor.All(ctx, &overrides); err != nil {
+		return errors.Wrap(errors.ErrInternal, "error decoding country overrides").WithCause(err)
+	}
+	s.Registry.SetOverrides(overrides)
+	return nil
+}
+
+// ListCountries returns every country with the version of the embedded table.
+func (s *CountryService) ListCountries() *models.CountriesResponse {
+	overrides, updatedAt := s.Registry.Overrides()
+	return &models.CountriesResponse{
+		Version:   countries.DatasetVersion,
+		Overrides: overrides,
+		UpdatedAt: updatedAt,
+		Countries: s.Registry.All(),
+	}
+}
+
+// GetCountry returns the country with the given ISO2 code.
+func (s *CountryService) GetCountry(iso2 string) (*models.Country, error) {
+	iso2 = strings.ToUpper(iso2)
+	if err := utils.ValidateCountryISO2(iso2); err != nil {
+		return nil, err
+	}
+	country, ok := s.Registry.Get(iso2)
+	if !ok {
+		return nil, errors.Wrap(errors.ErrNotFound, "country ISO2 '%s' not found", iso2).WithCode(errors.CodeCountryNotFound)
+	}
+	return &country, nil
+}
+
+// OverrideCountry replaces the entry of a country, or adds a country missing from the embedded
+// table, and stores the override. actor is recorded with it.
+func (s *CountryService) OverrideCountry(ctx context.Context, iso2 string, request models.CountryRequest, actor string) (*models.Country, error) {
+	iso2 = strings.ToUpper(iso2)
+	if err := utils.ValidateCountryISO2(iso2); err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	country := models.Country{
+		ISO2:       iso2,
+		ISO3:       strings.ToUpper(request.ISO3),
+		Numeric:    request.Numeric,
+		Name:       strings.ToUpper(strings.TrimSpace(request.Name)),
+		Currency:   strings.ToUpper(request.Currency),
+		IBANLength: request.IBANLength,
+		SEPA:       request.SEPA,
+		UpdatedAt:  &now,
+		UpdatedBy:  actor,
+	}
+
+	ctx, cancel := timeouts.Write(ctx)
+	defer cancel()
+	_, err := s.DB.ReplaceOne(ctx, bson.M{"_id": iso2}, country, options.Replace().SetUpsert(true))
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrInternal, "error storing country override").WithCause(err)
+	}
+	s.Registry.Override(country)
+	return &country, nil
+}
+
+// ResetCountry removes the override of a country and returns its embedded entry, or nil when the
+// country was added through an override and is now removed.
+func (s *CountryService) ResetCountry(ctx context.Context, iso2 string) (*models.Country, error) {
+	iso2 = strings.ToUpper(iso2)
+	if err := utils.ValidateCountryISO2(iso2); err != nil {
+		return nil, err
+	}
+	ctx, cancel := timeouts.Write(ctx)
+	defer cancel()
+	result, err := s.DB.DeleteOne(ctx, bson.M{"_id": iso2})
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrInternal, "error removing country override").WithCause(err)
+	}
+	// Another process may have removed the stored override already; the local one is reset either way.
+	reset := s.Registry.Reset(iso2)
+	if result.DeletedCount == 0 && !reset {
+		return nil, errors.Wrap(errors.ErrNotFound, "country %s has no override", iso2).WithCode(errors.CodeCountryNotOverridden)
+	}
+	if country, ok := s.Registry.Base(iso2); ok {
+		return &country, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,48 @@
+// country_service_test.go contains integration tests for storing and reloading country overrides.
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"swift-app/internal/countries"
+	"swift-app/internal/models"
+	"swift-app/internal/services"
+	testutils "swift-app/internal/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCountryService_Overrides(t *testing.T) {
+	collection := services.CountryCollectionFor(testutils.Collection)
+	_, _ = collection.DeleteMany(context.Background(), bson.M{})
+	registry, err := countries.Parse([]byte("iso2,iso3,numeric,name,currency,ibanLength,sepa\nHR,HRV,191,Croatia,HRK,21,true\n"))
+	require.NoError(t, err)
+	service := &services.CountryService{DB: collection, Registry: registry}
+
+	_, err = service.OverrideCountry(context.Background(), "hr", models.CountryRequest{
+		ISO3: "HRV", Numeric: "191", Name: "Croatia", Currency: "eur", IBANLength: 21, SEPA: true,
+	}, "admin")
+	require.NoError(t, err)
+
+	// A second process starts from the embedded table and loads the stored override.
+	other, err := countries.Parse([]byte("iso2,iso3,numeric,name,currency,ibanLength,sepa\nHR,HRV,191,Croatia,HRK,21,true\n"))
+	require.NoError(t, err)
+	otherService := &services.CountryService{DB: collection, Registry: other}
+	require.NoError(t, otherService.LoadOverrides(context.Background()))
+	country, err := otherService.GetCountry("HR")
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", country.Currency)
+	assert.Equal(t, "admin", country.UpdatedBy)
+	assert.Equal(t, 1, otherService.ListCountries().Overrides)
+
+	restored, err := otherService.ResetCountry(context.Background(), "HR")
+	require.NoError(t, err)
+	assert.Equal(t, "HRK", restored.Currency)
+	require.NoError(t, service.LoadOverrides(context.Background()))
+	country, err = service.GetCountry("HR")
+	require.NoError(t, err)
+	assert.False(t, country.Overridden(), "reloading should drop removed overrides")
+}
@@ -3,41 +3,91 @@ package services
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strings"
+	"swift-app/internal/audit"
+	"swift-app/internal/cache"
 	"swift-app/internal/errors"
+	"swift-app/internal/history"
 	"swift-app/internal/models"
+	"swift-app/internal/timeouts"
+	"swift-app/internal/tracing"
 	"swift-app/internal/utils"
+	"sync"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// MaxLookupCodes is the maximum number of SWIFT codes accepted by a single batch lookup.
+const MaxLookupCodes = 1000
+
+// maxAddBranchAttempts bounds how often adding a branch is retried while other changes to its
+// headquarter keep getting in first. Every miss means another change succeeded, so this is rarely reached.
+const maxAddBranchAttempts = 100
+
 type SwiftCodeService struct {
-	DB *mongo.Collection
+	DB      *mongo.Collection
+	History *history.Recorder
+	// Audit receives the audit entries that are written together with a change, such as a delete.
+	Audit *audit.Store
+	// Cache holds lookups and country listings; mutations through the service invalidate it,
+	// changes made by other processes show up once entries expire.
+	Cache *cache.Cache
+
+	transactionsOnce sync.Once
+	transactions     bool
 }
 
 func NewSwiftCodeService(db *mongo.Collection) *SwiftCodeService {
-	return &SwiftCodeService{DB: db}
+	return &SwiftCodeService{
+		DB:      db,
+		History: history.NewRecorder(history.CollectionFor(db)),
+		Audit:   audit.NewStore(audit.CollectionFor(db)),
+		Cache:   cache.New(cache.Config{Size: cache.DefaultSize}),
+	}
 }
 
 // GetSwiftCodeDetails retrieves details of a specific SWIFT code, including headquarter or branch information.
-func (s *SwiftCodeService) GetSwiftCodeDetails(swiftCode string) (*models.SwiftCode, error) {
+func (s *SwiftCodeService) GetSwiftCodeDetails(ctx context.Context, swiftCode string) (_ *models.SwiftCode, err error) {
 	swiftCode = strings.ToUpper(swiftCode)
+	ctx, span := tracing.Start(ctx, "SwiftCodeService.GetSwiftCodeDetails", tracing.AttrSwiftCode.String(swiftCode))
+	defer tracing.End(span, &err)
+	ctx, cancel := timeouts.Read(ctx)
+	defer cancel()
+
 	if err := utils.ValidateSwiftCode(swiftCode); err != nil {
 		return nil, err
 	}
+	return cache.Load(s.Cache, swiftCodeCacheKey(swiftCode), func() (*models.SwiftCode, error) {
+		return s.findSwiftCode(ctx, swiftCode)
+	})
+}
 
+// findSwiftCode looks up a stored SWIFT code, falling back to a branch embedded in its headquarter.
+func (s *SwiftCodeService) findSwiftCode(ctx context.Context, swiftCode string) (*models.SwiftCode, error) {
 	var swiftCodeDetails models.SwiftCode
-	err := s.DB.FindOne(context.Background(), bson.M{utils.FieldSwiftCode: swiftCode}).Decode(&swiftCodeDetails)
+	err := s.DB.FindOne(ctx, bson.M{utils.FieldSwiftCode: swiftCode}).Decode(&swiftCodeDetails)
 	if err == nil {
 		return &swiftCodeDetails, nil
 	}
 
-	headquarter, err := utils.GetHeadquarterBySwiftCode(s.DB, swiftCode)
+	headquarter, err := utils.GetHeadquarterBySwiftCode(ctx, s.DB, swiftCode)
 	if err != nil {
 		return nil, err
 	}
 
+	if details := branchDetails(headquarter, swiftCode); details != nil {
+		return details, nil
+	}
+	return nil, errors.Wrap(errors.ErrNotFound, "no branch found for SWIFT code %s", swiftCode).WithCode(errors.CodeBranchNotFound)
+}
+
+// branchDetails returns the details of a branch embedded in headquarter, or nil if it has no such branch.
+func branchDetails(headquarter *models.SwiftCode, swiftCode string) *models.SwiftCode {
 	for _, branch := range headquarter.Branches {
 		if branch.SwiftCode == swiftCode {
 			return &models.SwiftCode{
@@ -47,33 +97,199 @@ func (s *SwiftCodeService) GetSwiftCodeDetails(swiftCode string) (*models.SwiftC
 				CountryName:   headquarter.CountryName,
 				IsHeadquarter: false,
 				SwiftCode:     branch.SwiftCode,
+				LastModified:  headquarter.LastModified,
+			}
+		}
+	}
+	return nil
+}
+
+// LookupSwiftCodes resolves a batch of SWIFT codes with a single query, returning one result per
+// requested code in request order. Found codes carry their details (a headquarter with its branches,
+// or a branch); invalid and unknown codes carry the reason instead of failing the whole batch.
+func (s *SwiftCodeService) LookupSwiftCodes(ctx context.Context, swiftCodes []string) (_ []models.LookupResult, err error) {
+	ctx, span := tracing.Start(ctx, "SwiftCodeService.LookupSwiftCodes", attribute.Int("swift.lookup.count", len(swiftCodes)))
+	defer tracing.End(span, &err)
+	ctx, cancel := timeouts.Read(ctx)
+	defer cancel()
+
+	if len(swiftCodes) == 0 {
+		return nil, errors.Wrap(errors.ErrBadRequest, "at least one SWIFT code is required").
+			WithCode(errors.CodeLookupEmpty).WithFields("/swiftCodes")
+	}
+	if len(swiftCodes) > MaxLookupCodes {
+		return nil, errors.Wrap(errors.ErrBadRequest, "at most %d SWIFT codes can be looked up at once", MaxLookupCodes).
+			WithCode(errors.CodeLookupTooLarge).WithFields("/swiftCodes")
+	}
+
+	results := make([]models.LookupResult, len(swiftCodes))
+	queryCodes := make(map[string]bool)
+	generation := s.Cache.Generation()
+	for i, swiftCode := range swiftCodes {
+		swiftCode = strings.ToUpper(strings.TrimSpace(swiftCode))
+		results[i].SwiftCode = swiftCode
+		if err := utils.ValidateSwiftCode(swiftCode); err != nil {
+			results[i].Status = models.LookupStatusInvalid
+			results[i].Message = err.Error()
+			continue
+		}
+		if details, ok := cache.Get[*models.SwiftCode](s.Cache, swiftCodeCacheKey(swiftCode)); ok {
+			setLookupDetails(&results[i], details)
+			continue
+		}
+		queryCodes[swiftCode] = true
+		queryCodes[swiftCode[:8]+"XXX"] = true
+	}
+	if len(queryCodes) == 0 {
+		return results, nil
+	}
+
+	codes := make([]string, 0, len(queryCodes))
+	for code := range queryCodes {
+		codes = append(codes, code)
+	}
+	cursor, err := s.DB.Find(ctx, bson.M{utils.FieldSwiftCode: bson.M{"$in": codes}})
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrInternal, "error looking up SWIFT codes").WithCause(err)
+	}
+	defer cursor.Close(ctx)
+
+	var documents []models.SwiftCode
+	if err := cursor.All(ctx, &documents); err != nil {
+		return nil, errors.Wrap(errors.ErrInternal, "error decoding SWIFT codes").WithCause(err)
+	}
+	bySwiftCode := make(map[string]*models.SwiftCode, len(documents))
+	for i := range documents {
+		bySwiftCode[documents[i].SwiftCode] = &documents[i]
+	}
+
+	for i := range results {
+		if results[i].Status != "" {
+			continue
+		}
+		details, err := resolveSwiftCode(results[i].SwiftCode, bySwiftCode)
+		if err != nil {
+			results[i].Status = models.LookupStatusNotFound
+			results[i].Message = err.Error()
+			continue
+		}
+		setLookupDetails(&results[i], details)
+		cache.Set(s.Cache, swiftCodeCacheKey(results[i].SwiftCode), details, generation)
+	}
+	return results, nil
+}
+
+func setLookupDetails(result *models.LookupResult, details *models.SwiftCode) {
+	result.Status = models.LookupStatusBranch
+	if details.IsHeadquarter {
+		result.Status = models.LookupStatusHeadquarter
+	}
+	result.Details = details
+}
+
+// resolveSwiftCode finds a SWIFT code among prefetched documents the same way GetSwiftCodeDetails
+// does: a stored document first, then a branch embedded in its headquarter.
+func resolveSwiftCode(swiftCode string, bySwiftCode map[string]*models.SwiftCode) (*models.SwiftCode, error) {
+	if document, ok := bySwiftCode[swiftCode]; ok {
+		return document, nil
+	}
+
+	headquarterCode := swiftCode[:8] + "XXX"
+	headquarter, ok := bySwiftCode[headquarterCode]
+	if !ok || !headquarter.IsHeadquarter {
+		if strings.HasSuffix(swiftCode, "XXX") {
+			return nil, errors.Wrap(errors.ErrNotFound, "headquarter not found: %s", swiftCode).WithCode(errors.CodeHQNotFound)
+		}
+		return nil, errors.Wrap(errors.ErrNotFound, "cannot perform action with branch '%s' because its headquarter '%s' is missing", swiftCode, headquarterCode).
+			WithCode(errors.CodeHQNotFound)
+	}
+	for _, branch := range headquarter.Branches {
+		if branch.SwiftCode == swiftCode {
+			return &models.SwiftCode{
+				Address:       branch.Address,
+				BankName:      branch.BankName,
+				CountryISO2:   branch.CountryISO2,
+				CountryName:   headquarter.CountryName,
+				IsHeadquarter: false,
+				SwiftCode:     branch.SwiftCode,
+				LastModified:  headquarter.LastModified,
 			}, nil
 		}
 	}
+	return nil, errors.Wrap(errors.ErrNotFound, "no branch found for SWIFT code %s", swiftCode).WithCode(errors.CodeBranchNotFound)
+}
+
+// GetSwiftCodeDetailsAsOf reconstructs a SWIFT code (headquarter with branches, or branch) as it was at the given time.
+func (s *SwiftCodeService) GetSwiftCodeDetailsAsOf(ctx context.Context, swiftCode string, at time.Time) (_ *models.SwiftCode, err error) {
+	swiftCode = strings.ToUpper(swiftCode)
+	ctx, span := tracing.Start(ctx, "SwiftCodeService.GetSwiftCodeDetailsAsOf", tracing.AttrSwiftCode.String(swiftCode))
+	defer tracing.End(span, &err)
+	ctx, cancel := timeouts.Read(ctx)
+	defer cancel()
 
-	return nil, errors.Wrap(errors.ErrNotFound, "no branch found for SWIFT code %s", swiftCode)
+	if err := utils.ValidateSwiftCode(swiftCode); err != nil {
+		return nil, err
+	}
+	return s.History.AsOf(ctx, swiftCode, at)
+}
+
+// GetSwiftCodeHistory returns the recorded changes affecting a SWIFT code, oldest first.
+func (s *SwiftCodeService) GetSwiftCodeHistory(ctx context.Context, swiftCode string) (_ *models.SwiftCodeHistoryResponse, err error) {
+	swiftCode = strings.ToUpper(swiftCode)
+	ctx, span := tracing.Start(ctx, "SwiftCodeService.GetSwiftCodeHistory", tracing.AttrSwiftCode.String(swiftCode))
+	defer tracing.End(span, &err)
+	ctx, cancel := timeouts.Read(ctx)
+	defer cancel()
+
+	if err := utils.ValidateSwiftCode(swiftCode); err != nil {
+		return nil, err
+	}
+	entries, err := s.History.List(ctx, swiftCode)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, errors.Wrap(errors.ErrNotFound, "no history found for SWIFT code %s", swiftCode).WithCode(errors.CodeHistoryNotFound)
+	}
+	return &models.SwiftCodeHistoryResponse{SwiftCode: swiftCode, Entries: entries}, nil
 }
 
 // GetSwiftCodeByCountry retrieves all SWIFT codes and branches associated with a specified country ISO2 code.
-func (s *SwiftCodeService) GetSwiftCodesByCountry(countryISO2 string) (*models.CountrySwiftCodesResponse, error) {
+func (s *SwiftCodeService) GetSwiftCodesByCountry(ctx context.Context, countryISO2 string) (_ *models.CountrySwiftCodesResponse, err error) {
 	countryISO2 = strings.ToUpper(countryISO2)
-	_, err := utils.LoadAndValidateCountry(countryISO2)
-	if err != nil {
+	ctx, span := tracing.Start(ctx, "SwiftCodeService.GetSwiftCodesByCountry", tracing.AttrCountryISO2.String(countryISO2))
+	defer tracing.End(span, &err)
+	ctx, cancel := timeouts.Read(ctx)
+	defer cancel()
+
+	if _, err := utils.LoadAndValidateCountry(countryISO2); err != nil {
 		return nil, err
 	}
+	return cache.Load(s.Cache, countryCacheKey(countryISO2), func() (*models.CountrySwiftCodesResponse, error) {
+		return s.findSwiftCodesByCountry(ctx, countryISO2)
+	})
+}
 
-	cursor, err := s.DB.Find(context.Background(), bson.M{utils.FieldCountryISO2: countryISO2})
+// findSwiftCodesByCountry lists the headquarters of a country followed by their branches.
+func (s *SwiftCodeService) findSwiftCodesByCountry(ctx context.Context, countryISO2 string) (*models.CountrySwiftCodesResponse, error) {
+	cursor, err := s.DB.Find(ctx, bson.M{utils.FieldCountryISO2: countryISO2},
+		options.Find().SetSort(bson.D{{Key: utils.FieldSwiftCode, Value: 1}}))
 	if err != nil {
-		return nil, errors.Wrap(errors.ErrInternal, "error retrieving SWIFT codes for country %s", countryISO2)
+		return nil, errors.Wrap(errors.ErrInternal, "error retrieving SWIFT codes for country %s", countryISO2).WithCause(err)
 	}
-	defer cursor.Close(context.Background())
+	defer cursor.Close(ctx)
 
 	var swiftCodes []models.SwiftCode
-	if err = cursor.All(context.Background(), &swiftCodes); err != nil {
-		return nil, errors.Wrap(errors.ErrInternal, "error decoding SWIFT codes for country %s", countryISO2)
+	if err = cursor.All(ctx, &swiftCodes); err != nil {
+		return nil, errors.Wrap(errors.ErrInternal, "error decoding SWIFT codes for country %s", countryISO2).WithCause(err)
 	}
 	if len(swiftCodes) == 0 {
-		return nil, errors.Wrap(errors.ErrNotFound, "no SWIFT codes found for country %s", countryISO2)
+		return nil, errors.Wrap(errors.ErrNotFound, "no SWIFT codes found for country %s", countryISO2).WithCode(errors.CodeCountryNoCodes)
+	}
+
+	lastModified, err := s.History.LastDelete(ctx, countryISO2)
+	if err != nil {
+		return nil, err
 	}
 
 	var countryName = swiftCodes[0].CountryName
@@ -82,6 +298,9 @@ func (s *SwiftCodeService) GetSwiftCodesByCountry(countryISO2 string) (*models.C
 	swiftCodeSet := make(map[string]bool)
 
 	for _, swiftCode := range swiftCodes {
+		if swiftCode.LastModified != nil && (lastModified == nil || swiftCode.LastModified.After(*lastModified)) {
+			lastModified = swiftCode.LastModified
+		}
 		if swiftCode.IsHeadquarter && !swiftCodeSet[swiftCode.SwiftCode] {
 			allSwiftCodes = append(allSwiftCodes, models.SwiftBranch{
 				Address:       swiftCode.Address,
@@ -110,28 +329,31 @@ func (s *SwiftCodeService) GetSwiftCodesByCountry(countryISO2 string) (*models.C
 	allSwiftCodes = append(allSwiftCodes, allBranchCodes...)
 
 	return &models.CountrySwiftCodesResponse{
-		CountryISO2: countryISO2,
-		CountryName: countryName,
-		SwiftCodes:  allSwiftCodes,
+		CountryISO2:  countryISO2,
+		CountryName:  countryName,
+		SwiftCodes:   allSwiftCodes,
+		LastModified: lastModified,
 	}, nil
 }
 
 // AddSwiftCode adds a new SWIFT code (headquarter or branch) to the database with proper validation.
-func (s *SwiftCodeService) AddSwiftCode(request *models.SwiftCode) (string, error) {
+func (s *SwiftCodeService) AddSwiftCode(ctx context.Context, request *models.SwiftCode) (_ string, err error) {
 	request.SwiftCode = strings.ToUpper(request.SwiftCode)
 	request.CountryISO2 = strings.ToUpper(request.CountryISO2)
 	request.CountryName = strings.ToUpper(request.CountryName)
+	ctx, span := tracing.Start(ctx, "SwiftCodeService.AddSwiftCode", tracing.AttrSwiftCode.String(request.SwiftCode),
+		attribute.Bool("swift.headquarter", request.IsHeadquarter))
+	defer tracing.End(span, &err)
+	ctx, cancel := timeouts.Write(ctx)
+	defer cancel()
 
-	if err := utils.ValidateSwiftCode(request.SwiftCode); err != nil {
-		return "", err
-	}
-	if err := utils.ValidateSwiftCodeSuffix(request.SwiftCode, request.IsHeadquarter); err != nil {
-		return "", err
-	}
-	_, err := utils.LoadAndValidateCountryWithName(request.CountryISO2, request.CountryName)
+	countryName, err := utils.ValidateSwiftCodeInput(request.SwiftCode, request.CountryISO2, request.CountryName, request.IsHeadquarter)
 	if err != nil {
 		return "", err
 	}
+	// Aliases such as "DEUTSCHLAND" are stored as the name of the country.
+	request.CountryName = countryName
+	now := time.Now().UTC().Truncate(time.Millisecond)
 	doc := bson.M{
 		utils.FieldSwiftCode:     request.SwiftCode,
 		utils.FieldBankName:      request.BankName,
@@ -140,31 +362,49 @@ func (s *SwiftCodeService) AddSwiftCode(request *models.SwiftCode) (string, erro
 		utils.FieldCountryName:   request.CountryName,
 		utils.FieldIsHeadquarter: true,
 		utils.FieldBranches:      request.Branches,
+		utils.FieldLastModified:  now,
+		utils.FieldVersion:       int64(1),
 	}
 	if request.IsHeadquarter && request.Branches == nil {
 		doc[utils.FieldBranches] = []bson.M{}
 	}
 
 	if request.IsHeadquarter {
-		if err := s.DB.FindOne(context.Background(), bson.M{utils.FieldSwiftCode: request.SwiftCode}).Err(); err == nil {
-			return "", errors.Wrap(errors.ErrBadRequest, "headquarter SWIFT code already exists")
-		}
-		if _, err := s.DB.InsertOne(context.Background(), doc); err != nil {
-			return "", errors.Wrap(errors.ErrInternal, "error inserting SWIFT code into the database")
+		// The unique index on swiftCode decides between concurrent inserts of the same headquarter.
+		if _, err := s.DB.InsertOne(ctx, doc); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				return "", errors.Wrap(errors.ErrConflict, "headquarter SWIFT code already exists").
+					WithCode(errors.CodeHQExists).WithFields("/swiftCode")
+			}
+			return "", errors.Wrap(errors.ErrInternal, "error inserting SWIFT code into the database").WithCause(err)
 		}
+		s.invalidate(request.CountryISO2, request.SwiftCode)
+		s.recordHistory(ctx, request.SwiftCode, models.HistoryOperationCreate, nil, &models.SwiftCode{
+			Address:       request.Address,
+			BankName:      request.BankName,
+			CountryISO2:   request.CountryISO2,
+			CountryName:   request.CountryName,
+			IsHeadquarter: true,
+			SwiftCode:     request.SwiftCode,
+			Branches:      request.Branches,
+			LastModified:  &now,
+			Version:       1,
+		})
 		return "headquarter SWIFT code added successfully", nil
 	}
 
-	headquarter, err := utils.GetHeadquarterBySwiftCode(s.DB, request.SwiftCode)
+	headquarter, err := utils.GetHeadquarterBySwiftCode(ctx, s.DB, request.SwiftCode)
 	if err != nil {
 		return "", err
 	}
 	if request.CountryISO2 != headquarter.CountryISO2 {
-		return "", errors.Wrap(errors.ErrBadRequest, "branch countryISO does not match headquarter countryISO")
+		return "", errors.Wrap(errors.ErrBadRequest, "branch countryISO does not match headquarter countryISO").
+			WithCode(errors.CodeBranchCountryMismatch).WithFields("/countryISO2")
 	}
 	for _, branch := range headquarter.Branches {
 		if branch.SwiftCode == request.SwiftCode {
-			return "", errors.Wrap(errors.ErrBadRequest, "branch SWIFT code already exists")
+			return "", errors.Wrap(errors.ErrConflict, "branch SWIFT code already exists").
+				WithCode(errors.CodeBranchExists).WithFields("/swiftCode")
 		}
 	}
 
@@ -175,71 +415,394 @@ func (s *SwiftCodeService) AddSwiftCode(request *models.SwiftCode) (string, erro
 		utils.FieldCountryISO2:   request.CountryISO2,
 		utils.FieldIsHeadquarter: false,
 	}
-	if _, err := s.DB.UpdateOne(context.Background(),
-		bson.M{utils.FieldSwiftCode: headquarter.SwiftCode},
-		bson.M{"$push": bson.M{utils.FieldBranches: branch}}); err != nil {
-		return "", errors.Wrap(errors.ErrInternal, "error updating headquarter with branch")
+	// A concurrent change to the headquarter makes the push miss; the headquarter is then read again
+	// and the push retried, so a concurrent add of the same branch ends as a conflict.
+	for attempt := 1; ; attempt++ {
+		updated, err := s.pushBranch(ctx, headquarter, branch)
+		if err != nil {
+			return "", err
+		}
+		if updated != nil {
+			s.invalidate(headquarter.CountryISO2, headquarter.SwiftCode, request.SwiftCode)
+			s.recordHistory(ctx, request.SwiftCode, models.HistoryOperationCreate, headquarter, updated)
+			break
+		}
+		if attempt == maxAddBranchAttempts {
+			return "", errors.Wrap(errors.ErrConflict, "headquarter %s was modified concurrently, retry adding branch %s", headquarter.SwiftCode, request.SwiftCode).
+				WithCode(errors.CodeConcurrentModification)
+		}
+		if headquarter, err = utils.GetHeadquarterBySwiftCode(ctx, s.DB, request.SwiftCode); err != nil {
+			return "", err
+		}
+		if hasBranch(headquarter, request.SwiftCode) {
+			return "", errors.Wrap(errors.ErrConflict, "branch SWIFT code already exists").
+				WithCode(errors.CodeBranchExists).WithFields("/swiftCode")
+		}
 	}
 
 	return "branch SWIFT code added to headquarter successfully", nil
 }
 
+// DeleteOptions tunes DeleteSwiftCode.
+type DeleteOptions struct {
+	// DryRun only reports the codes that would be deleted.
+	DryRun bool
+	// Precondition, if set, is called with the current details of the code before anything is
+	// deleted; an error aborts the delete.
+	Precondition func(current *models.SwiftCode) error
+	// Audit, if set, is completed with the deleted codes and written together with the delete.
+	Audit *models.AuditEntry
+}
+
 // DeleteSwiftCode deletes an existing SWIFT code (headquarter and its branches, or single branch) from the database.
-func (s *SwiftCodeService) DeleteSwiftCode(swiftCode string) (string, error) {
+//
+// The delete, its history entries and the audit entry are written in one transaction when the
+// deployment supports transactions (replica set or sharded cluster). On a standalone server they
+// are written one after another, and when one of them fails the ones already written are undone.
+func (s *SwiftCodeService) DeleteSwiftCode(ctx context.Context, swiftCode string, opts DeleteOptions) (_ *models.DeleteSwiftCodeResponse, err error) {
 	swiftCode = strings.ToUpper(swiftCode)
+	ctx, span := tracing.Start(ctx, "SwiftCodeService.DeleteSwiftCode", tracing.AttrSwiftCode.String(swiftCode),
+		attribute.Bool("swift.dry_run", opts.DryRun))
+	defer tracing.End(span, &err)
+	ctx, cancel := timeouts.Write(ctx)
+	defer cancel()
+
 	if err := utils.ValidateSwiftCode(swiftCode); err != nil {
-		return "", err
+		return nil, err
 	}
 	isHeadquarter := strings.HasSuffix(swiftCode, "XXX")
 	if err := utils.ValidateSwiftCodeSuffix(swiftCode, isHeadquarter); err != nil {
-		return "", err
+		return nil, err
 	}
 
+	headquarterCode := swiftCode[:8] + "XXX"
+	var headquarter models.SwiftCode
+	err = s.DB.FindOne(ctx, bson.M{utils.FieldSwiftCode: headquarterCode, utils.FieldIsHeadquarter: true}).Decode(&headquarter)
+	if err == mongo.ErrNoDocuments {
+		if isHeadquarter {
+			return nil, errors.Wrap(errors.ErrNotFound, "headquarter %s not found, cannot delete", swiftCode).WithCode(errors.CodeHQNotFound)
+		}
+		return nil, errors.Wrap(errors.ErrNotFound, "branch %s not found and its headquarter %s does not exist", swiftCode, headquarterCode).
+			WithCode(errors.CodeHQNotFound)
+	}
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrInternal, "error while checking headquarter %s", headquarterCode).WithCause(err)
+	}
+	if !isHeadquarter && !hasBranch(&headquarter, swiftCode) {
+		return nil, errors.Wrap(errors.ErrNotFound, "branch %s not found under headquarter %s", swiftCode, headquarterCode).
+			WithCode(errors.CodeBranchNotFound)
+	}
+
+	if opts.Precondition != nil {
+		current := &headquarter
+		if !isHeadquarter {
+			current = branchDetails(&headquarter, swiftCode)
+		}
+		if err := opts.Precondition(current); err != nil {
+			return nil, err
+		}
+	}
+
+	deleted := []string{swiftCode}
 	if isHeadquarter {
-		var headquarter models.SwiftCode
-		err := s.DB.FindOne(context.Background(), bson.M{utils.FieldSwiftCode: swiftCode, utils.FieldIsHeadquarter: true}).Decode(&headquarter)
-		if err == mongo.ErrNoDocuments {
-			return "", errors.Wrap(errors.ErrNotFound, "headquarter %s not found, cannot delete", swiftCode)
+		for _, branch := range headquarter.Branches {
+			deleted = append(deleted, branch.SwiftCode)
 		}
-		if err != nil {
-			return "", errors.Wrap(errors.ErrInternal, "error while checking headquarter %s", swiftCode)
+	}
+	if opts.DryRun {
+		message := fmt.Sprintf("dry run: would delete branch %s", swiftCode)
+		if isHeadquarter {
+			message = fmt.Sprintf("dry run: would delete headquarter %s and %d branches", swiftCode, len(headquarter.Branches))
 		}
+		return &models.DeleteSwiftCodeResponse{Message: message, DryRun: true, DeletedCodes: deleted}, nil
+	}
 
-		result, err := s.DB.DeleteMany(context.Background(), bson.M{
-			"$or": []bson.M{
-				{utils.FieldSwiftCode: swiftCode},
-				{utils.FieldSwiftCode: bson.M{"$regex": fmt.Sprintf("^%s", swiftCode[:8])}},
-			},
-		})
-		if err != nil {
-			return "", errors.Wrap(errors.ErrInternal, "error deleting headquarter %s and its branches", swiftCode)
+	err = s.withTransaction(ctx, func(ctx context.Context, transactional bool) (err error) {
+		// Without a transaction every step that was written is undone, newest first, when a later one fails.
+		var undo []func(context.Context) error
+		if !transactional {
+			defer func() {
+				if err != nil {
+					s.undo(ctx, undo)
+				}
+			}()
+		}
+
+		var after *models.SwiftCode
+		if isHeadquarter {
+			stored, err := s.deleteHeadquarter(ctx, &headquarter)
+			if err != nil {
+				return err
+			}
+			undo = append(undo, func(ctx context.Context) error {
+				_, err := s.DB.InsertOne(ctx, stored)
+				return err
+			})
+		} else {
+			updated, err := s.deleteBranch(ctx, &headquarter, swiftCode)
+			if err != nil {
+				return err
+			}
+			after = updated
+			undo = append(undo, func(ctx context.Context) error {
+				return s.restoreBranches(ctx, &headquarter, updated)
+			})
+		}
+
+		for _, code := range deleted {
+			remove, err := s.History.RecordReversible(ctx, code, models.HistoryOperationDelete, models.HistorySourceAPI, &headquarter, after)
+			if err != nil {
+				return errors.Wrap(errors.ErrInternal, "error recording history of deleted code %s", code).WithCause(err)
+			}
+			undo = append(undo, remove)
 		}
-		if result.DeletedCount == 0 {
-			return "", errors.Wrap(errors.ErrInternal, "headquarter %s was not deleted", swiftCode)
+		if opts.Audit != nil {
+			opts.Audit.SwiftCodes = deleted
+			if err := s.Audit.Record(ctx, *opts.Audit); err != nil {
+				return errors.Wrap(errors.ErrInternal, "error recording audit entry of deleting %s", swiftCode).WithCause(err)
+			}
 		}
-		return fmt.Sprintf("deleted hadquarter %s and its branches", swiftCode), nil
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	s.invalidate(headquarter.CountryISO2, append([]string{headquarterCode}, deleted...)...)
 
-	headquarterCode := swiftCode[:8] + "XXX"
-	var headquarter models.SwiftCode
-	err := s.DB.FindOne(context.Background(), bson.M{utils.FieldSwiftCode: headquarterCode, utils.FieldIsHeadquarter: true}).Decode(&headquarter)
+	message := fmt.Sprintf("branch %s deleted successfully", swiftCode)
+	if isHeadquarter {
+		message = fmt.Sprintf("deleted hadquarter %s and its branches", swiftCode)
+	}
+	return &models.DeleteSwiftCodeResponse{Message: message, DeletedCodes: deleted}, nil
+}
+
+// deleteHeadquarter removes exactly the headquarter version that was read, together with its embedded
+// branches. It returns the removed document as it was stored.
+func (s *SwiftCodeService) deleteHeadquarter(ctx context.Context, headquarter *models.SwiftCode) (bson.Raw, error) {
+	stored, err := s.DB.FindOneAndDelete(ctx, bson.M{
+		utils.FieldSwiftCode:     headquarter.SwiftCode,
+		utils.FieldIsHeadquarter: true,
+		utils.FieldVersion:       versionFilter(headquarter.Version),
+	}).Raw()
 	if err == mongo.ErrNoDocuments {
-		return "", errors.Wrap(errors.ErrNotFound, "branch %s not found and its headquarter %s does not exist", swiftCode, headquarterCode)
+		return nil, errors.Wrap(errors.ErrConflict, "headquarter %s was modified concurrently, retry deleting it", headquarter.SwiftCode).
+			WithCode(errors.CodeConcurrentModification)
 	}
 	if err != nil {
-		return "", errors.Wrap(errors.ErrInternal, "error checking headquarter for branch %s", swiftCode)
+		return nil, errors.Wrap(errors.ErrInternal, "error deleting headquarter %s and its branches", headquarter.SwiftCode).WithCause(err)
 	}
+	return stored, nil
+}
+
+// deleteBranch pulls a branch from the headquarter version that was read, so the history entry's
+// before snapshot is exactly the document that was changed. It returns the updated headquarter.
+func (s *SwiftCodeService) deleteBranch(ctx context.Context, headquarter *models.SwiftCode, swiftCode string) (*models.SwiftCode, error) {
+	var updated models.SwiftCode
+	err := s.DB.FindOneAndUpdate(
+		ctx,
+		bson.M{
+			utils.FieldSwiftCode:                             headquarter.SwiftCode,
+			utils.FieldBranches + "." + utils.FieldSwiftCode: swiftCode,
+			utils.FieldVersion:                               versionFilter(headquarter.Version),
+		},
+		bson.M{
+			"$pull": bson.M{utils.FieldBranches: bson.M{utils.FieldSwiftCode: swiftCode}},
+			"$set":  bson.M{utils.FieldLastModified: time.Now().UTC().Truncate(time.Millisecond)},
+			"$inc":  bson.M{utils.FieldVersion: 1},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err == mongo.ErrNoDocuments {
+		return nil, errors.Wrap(errors.ErrConflict, "headquarter %s was modified concurrently, retry deleting branch %s", headquarter.SwiftCode, swiftCode).
+			WithCode(errors.CodeConcurrentModification)
+	}
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrInternal, "error deleting branch %s", swiftCode).WithCause(err)
+	}
+	return &updated, nil
+}
+
+// pushBranch adds a branch to the headquarter version that was read, so the history entry's before
+// snapshot is exactly the document that was changed. It returns nil when the headquarter changed since.
+func (s *SwiftCodeService) pushBranch(ctx context.Context, headquarter *models.SwiftCode, branch bson.M) (*models.SwiftCode, error) {
+	var updated models.SwiftCode
+	err := s.DB.FindOneAndUpdate(
+		ctx,
+		bson.M{
+			utils.FieldSwiftCode:     headquarter.SwiftCode,
+			utils.FieldIsHeadquarter: true,
+			utils.FieldVersion:       versionFilter(headquarter.Version),
+		},
+		bson.M{
+			"$push": bson.M{utils.FieldBranches: branch},
+			"$set":  bson.M{utils.FieldLastModified: time.Now().UTC().Truncate(time.Millisecond)},
+			"$inc":  bson.M{utils.FieldVersion: 1},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrInternal, "error updating headquarter with branch").WithCause(err)
+	}
+	return &updated, nil
+}
 
-	update, err := s.DB.UpdateOne(
-		context.Background(),
-		bson.M{utils.FieldSwiftCode: headquarterCode},
-		bson.M{"$pull": bson.M{utils.FieldBranches: bson.M{utils.FieldSwiftCode: swiftCode}}},
+// withTransaction runs fn inside a session transaction when the deployment supports transactions,
+// and directly otherwise. fn is told which of the two it got.
+func (s *SwiftCodeService) withTransaction(ctx context.Context, fn func(ctx context.Context, transactional bool) error) error {
+	if !s.supportsTransactions(ctx) {
+		return fn(ctx, false)
+	}
+
+	session, err := s.DB.Database().Client().StartSession()
+	if err != nil {
+		return errors.Wrap(errors.ErrInternal, "error starting database session").WithCause(err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessionCtx, true)
+	})
+	if err != nil {
+		if _, ok := errors.As(err); ok {
+			return err
+		}
+		return errors.Wrap(errors.ErrInternal, "error committing transaction").WithCause(err)
+	}
+	return nil
+}
+
+// supportsTransactions reports whether the server is a replica set member or a mongos router.
+// Standalone servers reject transactions. The answer is cached for the lifetime of the service.
+func (s *SwiftCodeService) supportsTransactions(ctx context.Context) bool {
+	s.transactionsOnce.Do(func() {
+		var hello bson.M
+		if err := s.DB.Database().RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+			slog.Warn("could not detect transaction support", "error", err)
+			return
+		}
+		_, replicaSet := hello["setName"]
+		s.transactions = replicaSet || hello["msg"] == "isdbgrid"
+	})
+	return s.transactions
+}
+
+// restoreBranches puts back the branches of headquarter after deleteBranch returned updated. The
+// version is raised once more, so clients holding the version without the branch see a change.
+func (s *SwiftCodeService) restoreBranches(ctx context.Context, headquarter, updated *models.SwiftCode) error {
+	result, err := s.DB.UpdateOne(
+		ctx,
+		bson.M{
+			utils.FieldSwiftCode:     headquarter.SwiftCode,
+			utils.FieldIsHeadquarter: true,
+			utils.FieldVersion:       versionFilter(updated.Version),
+		},
+		bson.M{
+			"$set": bson.M{utils.FieldBranches: headquarter.Branches, utils.FieldLastModified: time.Now().UTC().Truncate(time.Millisecond)},
+			"$inc": bson.M{utils.FieldVersion: 1},
+		},
 	)
 	if err != nil {
-		return "", errors.Wrap(errors.ErrInternal, "error deleting branch %s", swiftCode)
+		return err
 	}
-	if update.ModifiedCount == 0 {
-		return "", errors.Wrap(errors.ErrNotFound, "branch %s not found under headquarter %s", swiftCode, headquarterCode)
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("headquarter %s was modified concurrently", headquarter.SwiftCode)
+	}
+	return nil
+}
+
+// undo runs the undo steps of a delete that failed without a transaction, newest first. The client
+// may have gone away, so the steps are not cancelled with its request. Steps that fail are logged,
+// as the delete has already failed.
+func (s *SwiftCodeService) undo(ctx context.Context, steps []func(context.Context) error) {
+	ctx, cancel := timeouts.Write(context.WithoutCancel(ctx))
+	defer cancel()
+	for i := len(steps) - 1; i >= 0; i-- {
+		if err := steps[i](ctx); err != nil {
+			slog.Error("failed to undo part of a failed delete", "error", err)
+		}
+	}
+}
+
+// CountryTotals counts the stored headquarters and branches of every country, ordered by country code.
+func (s *SwiftCodeService) CountryTotals(ctx context.Context) (_ []models.CountryTotals, err error) {
+	ctx, span := tracing.Start(ctx, "SwiftCodeService.CountryTotals")
+	defer tracing.End(span, &err)
+	ctx, cancel := timeouts.Read(ctx)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id":          "$" + utils.FieldCountryISO2,
+			"headquarters": bson.M{"$sum": 1},
+			"branches":     bson.M{"$sum": bson.M{"$size": bson.M{"$ifNull": bson.A{"$" + utils.FieldBranches, bson.A{}}}}},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+	cursor, err := s.DB.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrInternal, "error counting SWIFT codes").WithCause(err)
+	}
+	defer cursor.Close(ctx)
+
+	totals := []models.CountryTotals{}
+	if err := cursor.All(ctx, &totals); err != nil {
+		return nil, errors.Wrap(errors.ErrInternal, "error decoding SWIFT code totals").WithCause(err)
+	}
+	return totals, nil
+}
+
+// InvalidateCache drops every cached lookup and country listing, e.g. after a bulk import.
+func (s *SwiftCodeService) InvalidateCache() {
+	s.Cache.Purge()
+}
+
+// CacheStats returns the hit and miss counters of the lookup cache.
+func (s *SwiftCodeService) CacheStats() models.CacheStats {
+	return s.Cache.Stats()
+}
+
+// invalidate drops the cached SWIFT codes and the country listing touched by a mutation.
+func (s *SwiftCodeService) invalidate(countryISO2 string, swiftCodes ...string) {
+	keys := []string{countryCacheKey(countryISO2)}
+	for _, swiftCode := range swiftCodes {
+		keys = append(keys, swiftCodeCacheKey(swiftCode))
+	}
+	s.Cache.Invalidate(keys...)
+}
+
+// versionFilter matches the headquarter version that was read. Documents stored before
+// versioning was introduced have no version field and are read as version 0.
+func versionFilter(version int64) interface{} {
+	if version == 0 {
+		return bson.M{"$exists": false}
+	}
+	return version
+}
+
+func hasBranch(headquarter *models.SwiftCode, swiftCode string) bool {
+	for _, branch := range headquarter.Branches {
+		if branch.SwiftCode == swiftCode {
+			return true
+		}
+	}
+	return false
+}
+
+func swiftCodeCacheKey(swiftCode string) string {
+	return "swift:" + swiftCode
+}
+
+func countryCacheKey(countryISO2 string) string {
+	return "country:" + countryISO2
+}
+
+// recordHistory stores a history entry for an API change. Failures are logged and do not undo the change.
+func (s *SwiftCodeService) recordHistory(ctx context.Context, swiftCode, operation string, before, after *models.SwiftCode) {
+	// The change is already stored, so a client that went away must not cancel its history entry.
+	if err := s.History.Record(context.WithoutCancel(ctx), swiftCode, operation, models.HistorySourceAPI, before, after); err != nil {
+		slog.Warn("failed to record history", "swift_code", swiftCode, "error", err)
 	}
-	return fmt.Sprintf("branch %s deleted successfully", swiftCode), nil
 }
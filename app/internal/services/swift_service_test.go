@@ -3,14 +3,23 @@ package services_test
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"sync"
 	"testing"
+	"time"
 
+	"swift-app/internal/audit"
+	"swift-app/internal/errors"
 	"swift-app/internal/models"
 	"swift-app/internal/services"
 	testutils "swift-app/internal/testutils"
+	"swift-app/internal/timeouts"
 
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func TestAddSwiftCode(t *testing.T) {
@@ -27,13 +36,22 @@ func TestAddSwiftCode(t *testing.T) {
 		IsHeadquarter: true,
 	}
 
-	msg, err := service.AddSwiftCode(swiftCode)
+	msg, err := service.AddSwiftCode(context.Background(), swiftCode)
 	assert.NoError(t, err, "Adding a SWIFT code should not return an error")
 	assert.Equal(t, "headquarter SWIFT code added successfully", msg)
 
 	var result models.SwiftCode
 	err = service.DB.FindOne(context.Background(), bson.M{"swiftCode": "AAAABBB1XXX"}).Decode(&result)
 	assert.NoError(t, err, "SWIFT code should exist in the database")
+
+	_, err = service.AddSwiftCode(context.Background(), &models.SwiftCode{
+		SwiftCode: "AAAADEFFXXX", BankName: "Test Bank", CountryISO2: "DE", CountryName: "Bundesrepublik Deutschland",
+		Address: "1 Teststraße", IsHeadquarter: true,
+	})
+	assert.NoError(t, err, "A localized name of the country should be accepted")
+	err = service.DB.FindOne(context.Background(), bson.M{"swiftCode": "AAAADEFFXXX"}).Decode(&result)
+	assert.NoError(t, err)
+	assert.Equal(t, "GERMANY", result.CountryName, "The name of the country should be stored")
 }
 
 func TestGetSwiftCodeDetails(t *testing.T) {
@@ -52,11 +70,23 @@ func TestGetSwiftCodeDetails(t *testing.T) {
 	_, err := service.DB.InsertOne(context.Background(), swiftCode)
 	assert.NoError(t, err, "Inserting SWIFT code into MongoDB should not return an error")
 
-	result, err := service.GetSwiftCodeDetails("AAAABBB1XXX")
+	result, err := service.GetSwiftCodeDetails(context.Background(), "AAAABBB1XXX")
 	assert.NoError(t, err, "Retrieving SWIFT code should not return an error")
 	assert.Equal(t, "Test Bank", result.BankName)
 }
 
+func TestGetSwiftCodeDetails_Timeout(t *testing.T) {
+	service := services.NewSwiftCodeService(testutils.Collection)
+
+	defaults := timeouts.Current()
+	timeouts.Configure(timeouts.Config{Read: time.Nanosecond})
+	defer timeouts.Configure(defaults)
+
+	_, err := service.GetSwiftCodeDetails(context.Background(), "AAAABBB1XXX")
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "A read past its timeout should fail with the deadline")
+	assert.Equal(t, http.StatusGatewayTimeout, errors.GetStatusCode(errors.FromDeadline(err)))
+}
+
 func TestGetSwiftCodesByCountry(t *testing.T) {
 	_, _ = testutils.Collection.DeleteMany(context.Background(), bson.M{})
 
@@ -82,11 +112,64 @@ func TestGetSwiftCodesByCountry(t *testing.T) {
 	_, err := service.DB.InsertMany(context.Background(), swiftCodes)
 	assert.NoError(t, err, "Inserting SWIFT codes into MongoDB should not return an error")
 
-	result, err := service.GetSwiftCodesByCountry("US")
+	result, err := service.GetSwiftCodesByCountry(context.Background(), "US")
 	assert.NoError(t, err, "Retrieving SWIFT codes for the country should not return an error")
 	assert.Equal(t, 2, len(result.SwiftCodes))
 }
 
+func TestGetSwiftCodesByCountry_LastModifiedCountsDeletes(t *testing.T) {
+	_, _ = testutils.Collection.DeleteMany(context.Background(), bson.M{})
+
+	service := services.NewSwiftCodeService(testutils.Collection)
+	_, _ = service.History.DB.DeleteMany(context.Background(), bson.M{})
+	for _, code := range []string{"LMODPLPWXXX", "LMODPLPKXXX"} {
+		_, err := service.AddSwiftCode(context.Background(), &models.SwiftCode{
+			SwiftCode: code, BankName: "Last Modified Bank", CountryISO2: "PL", CountryName: "POLAND", Address: "1 Main St", IsHeadquarter: true,
+		})
+		assert.NoError(t, err)
+	}
+	listing, err := service.GetSwiftCodesByCountry(context.Background(), "PL")
+	assert.NoError(t, err)
+	if !assert.NotNil(t, listing.LastModified) {
+		return
+	}
+	before := *listing.LastModified
+
+	// Deleting the older headquarter leaves no newer document behind.
+	time.Sleep(10 * time.Millisecond)
+	_, err = service.DeleteSwiftCode(context.Background(), "LMODPLPWXXX", services.DeleteOptions{})
+	assert.NoError(t, err)
+
+	listing, err = service.GetSwiftCodesByCountry(context.Background(), "PL")
+	assert.NoError(t, err)
+	assert.Len(t, listing.SwiftCodes, 1)
+	if assert.NotNil(t, listing.LastModified) {
+		assert.True(t, listing.LastModified.After(before), "a delete must move the listing's Last-Modified forward")
+	}
+}
+
+func TestCountryTotals(t *testing.T) {
+	_, _ = testutils.Collection.DeleteMany(context.Background(), bson.M{})
+
+	service := services.NewSwiftCodeService(testutils.Collection)
+	_, err := service.DB.InsertMany(context.Background(), []interface{}{
+		bson.M{"swiftCode": "AAAAPLP1XXX", "countryISO2": "PL", "isHeadquarter": true, "branches": bson.A{
+			bson.M{"swiftCode": "AAAAPLP1ABC", "countryISO2": "PL"},
+			bson.M{"swiftCode": "AAAAPLP1DEF", "countryISO2": "PL"},
+		}},
+		bson.M{"swiftCode": "BBBBPLP1XXX", "countryISO2": "PL", "isHeadquarter": true},
+		bson.M{"swiftCode": "CCCCUSU1XXX", "countryISO2": "US", "isHeadquarter": true, "branches": bson.A{}},
+	})
+	assert.NoError(t, err)
+
+	totals, err := service.CountryTotals(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []models.CountryTotals{
+		{CountryISO2: "PL", Headquarters: 2, Branches: 2},
+		{CountryISO2: "US", Headquarters: 1, Branches: 0},
+	}, totals)
+}
+
 func TestDeleteSwiftCode(t *testing.T) {
 	service := services.NewSwiftCodeService(testutils.Collection)
 
@@ -101,10 +184,403 @@ func TestDeleteSwiftCode(t *testing.T) {
 	_, err := service.DB.InsertOne(context.Background(), swiftCode)
 	assert.NoError(t, err, "Inserting SWIFT code should not return an error")
 
-	response, err := service.DeleteSwiftCode("XYZBANK1XXX")
+	response, err := service.DeleteSwiftCode(context.Background(), "XYZBANK1XXX", services.DeleteOptions{})
 	assert.NoError(t, err, "Deleting SWIFT code should not return an error")
-	assert.Equal(t, "deleted hadquarter XYZBANK1XXX and its branches", response, "Expected deletion message")
+	assert.Equal(t, "deleted hadquarter XYZBANK1XXX and its branches", response.Message, "Expected deletion message")
+	assert.Equal(t, []string{"XYZBANK1XXX"}, response.DeletedCodes)
 
 	err = service.DB.FindOne(context.Background(), bson.M{"swiftCode": "XYZBANK1XXX"}).Decode(&swiftCode)
 	assert.Error(t, err, "SWIFT code should be removed from the database")
 }
+
+func TestDeleteSwiftCode_DryRunAndPrefixIsolation(t *testing.T) {
+	_, _ = testutils.Collection.DeleteMany(context.Background(), bson.M{})
+
+	service := services.NewSwiftCodeService(testutils.Collection)
+	_, _ = service.Audit.DB.DeleteMany(context.Background(), bson.M{})
+
+	for _, code := range []*models.SwiftCode{
+		{SwiftCode: "DRYRPLPWXXX", BankName: "Dry Bank", CountryISO2: "PL", CountryName: "Poland", IsHeadquarter: true},
+		{SwiftCode: "DRYRPLPW001", BankName: "Dry Branch", CountryISO2: "PL", CountryName: "Poland"},
+		{SwiftCode: "DRYRPLPW002", BankName: "Dry Branch", CountryISO2: "PL", CountryName: "Poland"},
+	} {
+		_, err := service.AddSwiftCode(context.Background(), code)
+		assert.NoError(t, err)
+	}
+	// A document sharing the 8-character prefix that is not this headquarter must survive the delete.
+	_, err := testutils.Collection.InsertOne(context.Background(), bson.M{
+		"swiftCode": "DRYRPLPWABC", "bankName": "Other", "countryISO2": "PL", "countryName": "POLAND", "isHeadquarter": false,
+	})
+	assert.NoError(t, err)
+
+	preview, err := service.DeleteSwiftCode(context.Background(), "DRYRPLPWXXX", services.DeleteOptions{DryRun: true})
+	assert.NoError(t, err)
+	assert.True(t, preview.DryRun)
+	assert.Equal(t, []string{"DRYRPLPWXXX", "DRYRPLPW001", "DRYRPLPW002"}, preview.DeletedCodes)
+	_, err = service.GetSwiftCodeDetails(context.Background(), "DRYRPLPWXXX")
+	assert.NoError(t, err, "a dry run must not delete anything")
+
+	entry := models.AuditEntry{Actor: "tester", Method: http.MethodDelete, Status: http.StatusOK, Outcome: models.AuditOutcomeSuccess}
+	response, err := service.DeleteSwiftCode(context.Background(), "DRYRPLPWXXX", services.DeleteOptions{Audit: &entry})
+	assert.NoError(t, err)
+	assert.False(t, response.DryRun)
+	assert.Equal(t, preview.DeletedCodes, response.DeletedCodes)
+
+	count, err := testutils.Collection.CountDocuments(context.Background(), bson.M{"swiftCode": "DRYRPLPWABC"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	var audited models.AuditEntry
+	err = service.Audit.DB.FindOne(context.Background(), bson.M{}).Decode(&audited)
+	assert.NoError(t, err)
+	assert.Equal(t, response.DeletedCodes, audited.SwiftCodes)
+}
+
+func TestDeleteSwiftCode_PreconditionFailure(t *testing.T) {
+	_, _ = testutils.Collection.DeleteMany(context.Background(), bson.M{})
+
+	service := services.NewSwiftCodeService(testutils.Collection)
+	_, err := service.AddSwiftCode(context.Background(), &models.SwiftCode{
+		SwiftCode: "PRECPLPWXXX", BankName: "Prec Bank", CountryISO2: "PL", CountryName: "Poland", IsHeadquarter: true,
+	})
+	assert.NoError(t, err)
+
+	_, err = service.DeleteSwiftCode(context.Background(), "PRECPLPWXXX", services.DeleteOptions{
+		Precondition: func(current *models.SwiftCode) error {
+			assert.Equal(t, "PRECPLPWXXX", current.SwiftCode)
+			return errors.ErrPreconditionFailed
+		},
+	})
+	assert.ErrorIs(t, err, errors.ErrPreconditionFailed)
+	_, err = service.GetSwiftCodeDetails(context.Background(), "PRECPLPWXXX")
+	assert.NoError(t, err, "a failed precondition must not delete anything")
+}
+
+func TestDeleteSwiftCode_FailedAuditUndoesDelete(t *testing.T) {
+	_, _ = testutils.Collection.DeleteMany(context.Background(), bson.M{})
+
+	service := services.NewSwiftCodeService(testutils.Collection)
+	_, _ = service.History.DB.DeleteMany(context.Background(), bson.M{})
+	// An audit collection whose validator rejects every entry makes the last step of the delete fail.
+	rejecting := testutils.Collection.Database().Collection("swift_codes_rejecting_audit")
+	_ = rejecting.Drop(context.Background())
+	err := testutils.Collection.Database().CreateCollection(context.Background(), rejecting.Name(),
+		options.CreateCollection().SetValidator(bson.M{"rejected": bson.M{"$exists": true}}))
+	assert.NoError(t, err)
+	defer func() { _ = rejecting.Drop(context.Background()) }()
+	service.Audit = audit.NewStore(rejecting)
+
+	for _, code := range []*models.SwiftCode{
+		{SwiftCode: "UNDOPLPWXXX", BankName: "Undo Bank", CountryISO2: "PL", CountryName: "Poland", IsHeadquarter: true},
+		{SwiftCode: "UNDOPLPW001", BankName: "Undo Branch", CountryISO2: "PL", CountryName: "Poland"},
+		{SwiftCode: "UNDOPLPW002", BankName: "Undo Branch", CountryISO2: "PL", CountryName: "Poland"},
+	} {
+		_, err := service.AddSwiftCode(context.Background(), code)
+		assert.NoError(t, err)
+	}
+	historyBefore, err := service.History.DB.CountDocuments(context.Background(), bson.M{})
+	assert.NoError(t, err)
+
+	for _, code := range []string{"UNDOPLPW001", "UNDOPLPWXXX"} {
+		entry := models.AuditEntry{Actor: "tester", Method: http.MethodDelete, Status: http.StatusOK, Outcome: models.AuditOutcomeSuccess}
+		_, err = service.DeleteSwiftCode(context.Background(), code, services.DeleteOptions{Audit: &entry})
+		assert.ErrorIs(t, err, errors.ErrInternal, "deleting %s must fail when its audit entry cannot be written", code)
+
+		service.InvalidateCache()
+		hq, err := service.GetSwiftCodeDetails(context.Background(), "UNDOPLPWXXX")
+		assert.NoError(t, err, "the headquarter must be back after deleting %s failed", code)
+		if assert.NotNil(t, hq) {
+			assert.Len(t, hq.Branches, 2, "the branches must be back after deleting %s failed", code)
+		}
+		historyAfter, err := service.History.DB.CountDocuments(context.Background(), bson.M{})
+		assert.NoError(t, err)
+		assert.Equal(t, historyBefore, historyAfter, "no delete of %s may remain in the history", code)
+	}
+}
+
+func TestSwiftCodeHistoryAndAsOf(t *testing.T) {
+	_, _ = testutils.Collection.DeleteMany(context.Background(), bson.M{})
+
+	service := services.NewSwiftCodeService(testutils.Collection)
+	_, _ = service.History.DB.DeleteMany(context.Background(), bson.M{})
+
+	_, err := service.AddSwiftCode(context.Background(), &models.SwiftCode{
+		SwiftCode: "HISTPLPWXXX", BankName: "Hist Bank", CountryISO2: "PL", CountryName: "Poland", IsHeadquarter: true,
+	})
+	assert.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+	beforeBranch := time.Now().UTC()
+	time.Sleep(10 * time.Millisecond)
+
+	_, err = service.AddSwiftCode(context.Background(), &models.SwiftCode{
+		SwiftCode: "HISTPLPW001", BankName: "Hist Branch", CountryISO2: "PL", CountryName: "Poland", IsHeadquarter: false,
+	})
+	assert.NoError(t, err)
+
+	_, err = service.DeleteSwiftCode(context.Background(), "HISTPLPWXXX", services.DeleteOptions{})
+	assert.NoError(t, err)
+
+	historyResponse, err := service.GetSwiftCodeHistory(context.Background(), "HISTPLPWXXX")
+	assert.NoError(t, err)
+	assert.Len(t, historyResponse.Entries, 3, "expected HQ create, branch create and HQ delete")
+	assert.Equal(t, models.HistoryOperationDelete, historyResponse.Entries[2].Operation)
+
+	asOf, err := service.GetSwiftCodeDetailsAsOf(context.Background(), "HISTPLPWXXX", beforeBranch)
+	assert.NoError(t, err)
+	assert.Equal(t, "Hist Bank", asOf.BankName)
+	assert.Empty(t, asOf.Branches)
+
+	_, err = service.GetSwiftCodeDetailsAsOf(context.Background(), "HISTPLPW001", beforeBranch)
+	assert.Error(t, err, "branch should not exist before it was added")
+}
+
+func TestLookupSwiftCodes(t *testing.T) {
+	_, _ = testutils.Collection.DeleteMany(context.Background(), bson.M{})
+
+	service := services.NewSwiftCodeService(testutils.Collection)
+
+	_, err := service.DB.InsertOne(context.Background(), bson.M{
+		"swiftCode":     "AAAABBB1XXX",
+		"bankName":      "Test Bank",
+		"countryName":   "UNITED STATES",
+		"address":       "123 Test St",
+		"countryISO2":   "US",
+		"isHeadquarter": true,
+		"branches": []bson.M{
+			{"swiftCode": "AAAABBB1001", "bankName": "Test Bank Branch", "address": "1 Branch St", "countryISO2": "US", "isHeadquarter": false},
+		},
+	})
+	assert.NoError(t, err)
+
+	results, err := service.LookupSwiftCodes(context.Background(), []string{"aaaabbb1001", "AAAABBB1XXX", "bad", "AAAABBB1002", "CCCCDDD1XXX"})
+	assert.NoError(t, err)
+	assert.Len(t, results, 5)
+
+	assert.Equal(t, "AAAABBB1001", results[0].SwiftCode)
+	assert.Equal(t, models.LookupStatusBranch, results[0].Status)
+	branch := results[0].Details.(*models.SwiftCode)
+	assert.Equal(t, "UNITED STATES", branch.CountryName)
+
+	assert.Equal(t, models.LookupStatusHeadquarter, results[1].Status)
+	assert.Len(t, results[1].Details.(*models.SwiftCode).Branches, 1)
+
+	assert.Equal(t, models.LookupStatusInvalid, results[2].Status)
+	assert.NotEmpty(t, results[2].Message)
+
+	assert.Equal(t, models.LookupStatusNotFound, results[3].Status)
+	assert.Equal(t, models.LookupStatusNotFound, results[4].Status)
+	assert.Nil(t, results[4].Details)
+
+	_, err = service.LookupSwiftCodes(context.Background(), nil)
+	assert.Error(t, err)
+
+	_, err = service.LookupSwiftCodes(context.Background(), make([]string, services.MaxLookupCodes+1))
+	assert.Error(t, err)
+}
+
+func TestSwiftCodeCacheInvalidation(t *testing.T) {
+	_, _ = testutils.Collection.DeleteMany(context.Background(), bson.M{})
+
+	service := services.NewSwiftCodeService(testutils.Collection)
+
+	_, err := service.AddSwiftCode(context.Background(), &models.SwiftCode{
+		SwiftCode:     "CACHPLPWXXX",
+		BankName:      "Cache Bank",
+		CountryISO2:   "PL",
+		CountryName:   "POLAND",
+		Address:       "1 Cache St",
+		IsHeadquarter: true,
+	})
+	assert.NoError(t, err)
+
+	headquarter, err := service.GetSwiftCodeDetails(context.Background(), "CACHPLPWXXX")
+	assert.NoError(t, err)
+	assert.Empty(t, headquarter.Branches)
+	_, err = service.GetSwiftCodeDetails(context.Background(), "CACHPLPWXXX")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), service.CacheStats().Hits)
+
+	country, err := service.GetSwiftCodesByCountry(context.Background(), "PL")
+	assert.NoError(t, err)
+	assert.Len(t, country.SwiftCodes, 1)
+
+	_, err = service.AddSwiftCode(context.Background(), &models.SwiftCode{
+		SwiftCode:   "CACHPLPW001",
+		BankName:    "Cache Bank Branch",
+		CountryISO2: "PL",
+		CountryName: "POLAND",
+		Address:     "2 Cache St",
+	})
+	assert.NoError(t, err)
+
+	headquarter, err = service.GetSwiftCodeDetails(context.Background(), "CACHPLPWXXX")
+	assert.NoError(t, err)
+	assert.Len(t, headquarter.Branches, 1, "adding a branch should invalidate the cached headquarter")
+	country, err = service.GetSwiftCodesByCountry(context.Background(), "PL")
+	assert.NoError(t, err)
+	assert.Len(t, country.SwiftCodes, 2, "adding a branch should invalidate the cached country listing")
+
+	_, err = service.DeleteSwiftCode(context.Background(), "CACHPLPWXXX", services.DeleteOptions{})
+	assert.NoError(t, err)
+
+	_, err = service.GetSwiftCodeDetails(context.Background(), "CACHPLPW001")
+	assert.Error(t, err, "deleting a headquarter should invalidate its cached branches")
+	_, err = service.GetSwiftCodesByCountry(context.Background(), "PL")
+	assert.Error(t, err)
+}
+
+// addConcurrently runs AddSwiftCode for every request at the same time and returns the errors.
+func addConcurrently(service *services.SwiftCodeService, requests []*models.SwiftCode) []error {
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	errs := make([]error, len(requests))
+	for i, request := range requests {
+		wg.Add(1)
+		go func(i int, request *models.SwiftCode) {
+			defer wg.Done()
+			<-start
+			_, errs[i] = service.AddSwiftCode(context.Background(), request)
+		}(i, request)
+	}
+	close(start)
+	wg.Wait()
+	return errs
+}
+
+func countOutcomes(t *testing.T, errs []error) (succeeded, conflicts int) {
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.GetStatusCode(err) == http.StatusConflict:
+			conflicts++
+		default:
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	return succeeded, conflicts
+}
+
+func TestAddSwiftCode_ConcurrentHeadquarter(t *testing.T) {
+	_, _ = testutils.Collection.DeleteMany(context.Background(), bson.M{})
+
+	service := services.NewSwiftCodeService(testutils.Collection)
+
+	// The production unique index on swiftCode (see database.InitMongoDB) arbitrates concurrent inserts.
+	_, err := service.DB.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.M{"swiftCode": 1},
+		Options: options.Index().SetUnique(true),
+	})
+	assert.NoError(t, err)
+
+	const attempts = 20
+	requests := make([]*models.SwiftCode, attempts)
+	for i := range requests {
+		requests[i] = &models.SwiftCode{
+			SwiftCode:     "RACEPLPWXXX",
+			BankName:      "Race Bank",
+			CountryISO2:   "PL",
+			CountryName:   "POLAND",
+			Address:       "1 Race St",
+			IsHeadquarter: true,
+		}
+	}
+
+	succeeded, conflicts := countOutcomes(t, addConcurrently(service, requests))
+	assert.Equal(t, 1, succeeded)
+	assert.Equal(t, attempts-1, conflicts)
+
+	count, err := service.DB.CountDocuments(context.Background(), bson.M{"swiftCode": "RACEPLPWXXX"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestAddSwiftCode_ConcurrentSameBranch(t *testing.T) {
+	_, _ = testutils.Collection.DeleteMany(context.Background(), bson.M{})
+
+	service := services.NewSwiftCodeService(testutils.Collection)
+	_, err := service.AddSwiftCode(context.Background(), &models.SwiftCode{
+		SwiftCode:     "RACEPLPWXXX",
+		BankName:      "Race Bank",
+		CountryISO2:   "PL",
+		CountryName:   "POLAND",
+		Address:       "1 Race St",
+		IsHeadquarter: true,
+	})
+	assert.NoError(t, err)
+
+	const attempts = 20
+	requests := make([]*models.SwiftCode, attempts)
+	for i := range requests {
+		requests[i] = &models.SwiftCode{
+			SwiftCode:   "RACEPLPW001",
+			BankName:    "Race Bank Branch",
+			CountryISO2: "PL",
+			CountryName: "POLAND",
+			Address:     "2 Race St",
+		}
+	}
+
+	succeeded, conflicts := countOutcomes(t, addConcurrently(service, requests))
+	assert.Equal(t, 1, succeeded)
+	assert.Equal(t, attempts-1, conflicts)
+
+	var headquarter models.SwiftCode
+	err = service.DB.FindOne(context.Background(), bson.M{"swiftCode": "RACEPLPWXXX"}).Decode(&headquarter)
+	assert.NoError(t, err)
+	assert.Len(t, headquarter.Branches, 1, "the branch must be stored exactly once")
+	assert.Equal(t, int64(2), headquarter.Version)
+}
+
+func TestAddSwiftCode_ConcurrentDistinctBranches(t *testing.T) {
+	_, _ = testutils.Collection.DeleteMany(context.Background(), bson.M{})
+
+	service := services.NewSwiftCodeService(testutils.Collection)
+	_, _ = service.History.DB.DeleteMany(context.Background(), bson.M{})
+	_, _ = service.History.Counters.DeleteMany(context.Background(), bson.M{})
+	_, err := service.AddSwiftCode(context.Background(), &models.SwiftCode{
+		SwiftCode:     "RACEPLPWXXX",
+		BankName:      "Race Bank",
+		CountryISO2:   "PL",
+		CountryName:   "POLAND",
+		Address:       "1 Race St",
+		IsHeadquarter: true,
+	})
+	assert.NoError(t, err)
+
+	const attempts = 20
+	requests := make([]*models.SwiftCode, attempts)
+	for i := range requests {
+		requests[i] = &models.SwiftCode{
+			SwiftCode:   fmt.Sprintf("RACEPLPW%03d", i+1),
+			BankName:    "Race Bank Branch",
+			CountryISO2: "PL",
+			CountryName: "POLAND",
+			Address:     "2 Race St",
+		}
+	}
+
+	succeeded, conflicts := countOutcomes(t, addConcurrently(service, requests))
+	assert.Equal(t, attempts, succeeded)
+	assert.Equal(t, 0, conflicts)
+
+	var headquarter models.SwiftCode
+	err = service.DB.FindOne(context.Background(), bson.M{"swiftCode": "RACEPLPWXXX"}).Decode(&headquarter)
+	assert.NoError(t, err)
+	assert.Len(t, headquarter.Branches, attempts)
+	assert.Equal(t, int64(1+attempts), headquarter.Version)
+
+	// Each branch was pushed onto the version its history entry records as before.
+	history, err := service.GetSwiftCodeHistory(context.Background(), "RACEPLPWXXX")
+	assert.NoError(t, err)
+	assert.Len(t, history.Entries, 1+attempts)
+	seen := make(map[int64]bool)
+	for _, entry := range history.Entries[1:] {
+		if assert.NotNil(t, entry.Before) && assert.NotNil(t, entry.After) {
+			assert.Equal(t, entry.Before.Version+1, entry.After.Version, entry.SwiftCode)
+			assert.Len(t, entry.After.Branches, len(entry.Before.Branches)+1, entry.SwiftCode)
+			assert.False(t, seen[entry.Before.Version], "two branches were pushed onto version %d", entry.Before.Version)
+			seen[entry.Before.Version] = true
+		}
+	}
+}
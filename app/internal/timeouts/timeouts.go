@@ -0,0 +1,94 @@
+// Package timeouts bounds how long database operations may take. Operations are grouped into
+// classes (reads, writes and import batches) that share a configurable timeout, so a slow database
+// fails requests with a timeout instead of hanging them.
+package timeouts
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Class groups operations that share a timeout.
+type Class string
+
+// Operation classes.
+const (
+	ClassRead        Class = "read"
+	ClassWrite       Class = "write"
+	ClassImportBatch Class = "import_batch"
+)
+
+// Defaults used when no timeout configuration is given.
+const (
+	DefaultRead        = 5 * time.Second
+	DefaultWrite       = 10 * time.Second
+	DefaultImportBatch = time.Minute
+)
+
+// Config holds the timeout of each operation class. A zero duration leaves its class unbounded.
+type Config struct {
+	Read        time.Duration
+	Write       time.Duration
+	ImportBatch time.Duration
+}
+
+// DefaultConfig returns the default timeout of every class.
+func DefaultConfig() Config {
+	return Config{Read: DefaultRead, Write: DefaultWrite, ImportBatch: DefaultImportBatch}
+}
+
+// For returns the timeout of the given class.
+func (c Config) For(class Class) time.Duration {
+	switch class {
+	case ClassRead:
+		return c.Read
+	case ClassWrite:
+		return c.Write
+	case ClassImportBatch:
+		return c.ImportBatch
+	}
+	return 0
+}
+
+var current atomic.Pointer[Config]
+
+func init() {
+	config := DefaultConfig()
+	current.Store(&config)
+}
+
+// Configure sets the timeouts used by every operation started afterwards.
+func Configure(config Config) {
+	current.Store(&config)
+}
+
+// Current returns the configured timeouts.
+func Current() Config {
+	return *current.Load()
+}
+
+// With returns a copy of ctx that expires after the configured timeout of class, or when ctx does.
+// The returned cancel function must be called once the operation has finished.
+func With(ctx context.Context, class Class) (context.Context, context.CancelFunc) {
+	timeout := Current().For(class)
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// Read bounds ctx by the read timeout.
+func Read(ctx context.Context) (context.Context, context.CancelFunc) {
+	return With(ctx, ClassRead)
+}
+
+// Write bounds ctx by the write timeout.
+func Write(ctx context.Context) (context.Context, context.CancelFunc) {
+	return With(ctx, ClassWrite)
+}
+
+// ImportBatch bounds ctx by the import batch timeout.
+func ImportBatch(ctx context.Context) (context.Context, context.CancelFunc) {
+	return With(ctx, ClassImportBatch)
+}
@@ -0,0 +1,75 @@
+// timeouts_test.go contains unit tests for the per-class operation timeouts.
+package timeouts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func configure(t *testing.T, config Config) {
+	previous := Current()
+	Configure(config)
+	t.Cleanup(func() {
+		Configure(previous)
+	})
+}
+
+func TestDefaultConfig(t *testing.T) {
+	config := DefaultConfig()
+	assert.Equal(t, DefaultRead, config.For(ClassRead))
+	assert.Equal(t, DefaultWrite, config.For(ClassWrite))
+	assert.Equal(t, DefaultImportBatch, config.For(ClassImportBatch))
+	assert.Zero(t, config.For(Class("unknown")))
+}
+
+func TestWith_AppliesClassTimeout(t *testing.T) {
+	configure(t, Config{Read: time.Second, Write: time.Hour})
+
+	ctx, cancel := Read(context.Background())
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok, "A read should have a deadline")
+	assert.WithinDuration(t, time.Now().Add(time.Second), deadline, 100*time.Millisecond)
+
+	ctx, cancel = Write(context.Background())
+	defer cancel()
+	deadline, ok = ctx.Deadline()
+	assert.True(t, ok, "A write should have a deadline")
+	assert.WithinDuration(t, time.Now().Add(time.Hour), deadline, 100*time.Millisecond)
+}
+
+func TestWith_ZeroTimeoutLeavesClassUnbounded(t *testing.T) {
+	configure(t, Config{})
+
+	ctx, cancel := ImportBatch(context.Background())
+	_, ok := ctx.Deadline()
+	assert.False(t, ok, "A zero timeout should not set a deadline")
+
+	cancel()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled, "The context should still be cancellable")
+}
+
+func TestWith_KeepsEarlierParentDeadline(t *testing.T) {
+	configure(t, Config{Read: time.Hour})
+
+	parent, cancelParent := context.WithTimeout(context.Background(), time.Second)
+	defer cancelParent()
+	ctx, cancel := Read(parent)
+	defer cancel()
+
+	parentDeadline, _ := parent.Deadline()
+	deadline, _ := ctx.Deadline()
+	assert.Equal(t, parentDeadline, deadline)
+}
+
+func TestWith_Expires(t *testing.T) {
+	configure(t, Config{Write: time.Millisecond})
+
+	ctx, cancel := Write(context.Background())
+	defer cancel()
+	<-ctx.Done()
+	assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+}
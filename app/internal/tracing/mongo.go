@@ -0,0 +1,65 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CommandMonitor returns a MongoDB command monitor that records a client span for every command,
+// as a child of the span in the command's context. Install it with options.Client().SetMonitor.
+func CommandMonitor() *event.CommandMonitor {
+	var mu sync.Mutex
+	spans := make(map[string]trace.Span)
+	key := func(connectionID string, requestID int64) string {
+		return fmt.Sprintf("%s/%d", connectionID, requestID)
+	}
+	finish := func(e event.CommandFinishedEvent) trace.Span {
+		mu.Lock()
+		defer mu.Unlock()
+		k := key(e.ConnectionID, e.RequestID)
+		span := spans[k]
+		delete(spans, k)
+		return span
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			name := e.CommandName
+			attrs := []attribute.KeyValue{
+				semconv.DBSystemMongoDB,
+				semconv.DBNamespace(e.DatabaseName),
+				semconv.DBOperationName(e.CommandName),
+			}
+			// The first element of a command names the collection it runs on, e.g. {"find": "swiftCodes"}.
+			if element, err := e.Command.IndexErr(0); err == nil {
+				if collection, ok := element.Value().StringValueOK(); ok {
+					name += " " + collection
+					attrs = append(attrs, semconv.DBCollectionName(collection))
+				}
+			}
+			_, span := tracer().Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+
+			mu.Lock()
+			spans[key(e.ConnectionID, e.RequestID)] = span
+			mu.Unlock()
+		},
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			if span := finish(e.CommandFinishedEvent); span != nil {
+				span.End()
+			}
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			if span := finish(e.CommandFinishedEvent); span != nil {
+				span.SetStatus(codes.Error, e.Failure)
+				span.End()
+			}
+		},
+	}
+}
@@ -0,0 +1,121 @@
+// Package tracing configures OpenTelemetry tracing and provides the spans recorded for HTTP requests,
+// service methods, MongoDB commands and import phases. Trace context is propagated with W3C
+// traceparent and baggage headers.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"swift-app/internal/errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Supported span exporters.
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// DefaultServiceName identifies the application in exported spans unless OTEL_SERVICE_NAME is set.
+const DefaultServiceName = "swift-app"
+
+// instrumentationName names the tracer that creates the application's spans.
+const instrumentationName = "swift-app"
+
+// AttrSwiftCode is the span attribute holding the SWIFT code an operation works on.
+const AttrSwiftCode = attribute.Key("swift.code")
+
+// AttrCountryISO2 is the span attribute holding the country an operation works on.
+const AttrCountryISO2 = attribute.Key("swift.country_iso2")
+
+// Config selects where spans are exported.
+type Config struct {
+	// Exporter is none, stdout or otlp. The OTLP exporter is configured with the standard
+	// OTEL_EXPORTER_OTLP_* environment variables and sends spans over HTTP.
+	Exporter string
+	// ServiceName is reported as the service.name resource attribute.
+	ServiceName string
+	// Output receives spans from the stdout exporter.
+	Output io.Writer
+}
+
+// Setup installs the W3C trace context propagator and, unless the exporter is none, a tracer
+// provider exporting spans in batches. The returned function flushes and stops the provider.
+func Setup(ctx context.Context, config Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch strings.ToLower(config.Exporter) {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout, "console":
+		var options []stdouttrace.Option
+		if config.Output != nil {
+			options = append(options, stdouttrace.WithWriter(config.Output))
+		}
+		exporter, err = stdouttrace.New(options...)
+	case ExporterOTLP:
+		exporter, err = otlptracehttp.New(ctx)
+	default:
+		return nil, fmt.Errorf("OTEL_TRACES_EXPORTER must be %s, %s or %s, got %q", ExporterNone, ExporterStdout, ExporterOTLP, config.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s span exporter: %v", config.Exporter, err)
+	}
+
+	serviceName := config.ServiceName
+	if serviceName == "" {
+		serviceName = DefaultServiceName
+	}
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(serviceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK())
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe tracing resource: %v", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start starts an internal span as a child of the span in ctx.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End ends span, recording *err if it is set. Only server errors mark the span as failed; client
+// errors such as an unknown SWIFT code are expected outcomes and are only recorded as events.
+// It is meant to be deferred with a pointer to a named error result.
+func End(span trace.Span, err *error) {
+	if err != nil && *err != nil {
+		span.RecordError(*err)
+		if appErr, ok := errors.As(*err); ok {
+			span.SetAttributes(attribute.String("error.code", appErr.Code))
+		}
+		if errors.GetStatusCode(*err) >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, (*err).Error())
+		}
+	}
+	span.End()
+}
+
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
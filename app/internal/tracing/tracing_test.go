@@ -0,0 +1,161 @@
+// tracing_test.go contains unit tests for tracer setup, HTTP request spans, service span errors and MongoDB command spans.
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"swift-app/internal/errors"
+	"swift-app/internal/requestid"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordSpans installs a tracer provider that keeps finished spans in memory for the duration of the test.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func attributeValue(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, attr := range span.Attributes() {
+		if attr.Key == key {
+			return attr.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestSetup(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Config{})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	_, err = Setup(context.Background(), Config{Exporter: "zipkin"})
+	assert.Error(t, err)
+
+	previous := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previous)
+	var buf bytes.Buffer
+	shutdown, err = Setup(context.Background(), Config{Exporter: ExporterStdout, ServiceName: "swift-test", Output: &buf})
+	require.NoError(t, err)
+	_, span := Start(context.Background(), "exported")
+	span.End()
+	require.NoError(t, shutdown(context.Background()))
+	assert.Contains(t, buf.String(), `"Name":"exported"`)
+	assert.Contains(t, buf.String(), "swift-test")
+}
+
+func TestMiddleware_ContinuesIncomingTrace(t *testing.T) {
+	recorder := recordSpans(t)
+	_, err := Setup(context.Background(), Config{})
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(requestid.Middleware(), Middleware())
+	r.GET("/items/:id", func(c *gin.Context) {
+		_, span := Start(c.Request.Context(), "child")
+		span.End()
+		c.Status(http.StatusNoContent)
+	})
+	r.GET("/fail", func(c *gin.Context) {
+		c.Status(http.StatusInternalServerError)
+	})
+
+	req := httptest.NewRequest("GET", "/items/42", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	req.Header.Set(requestid.Header, "req-1")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/fail", nil))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	child, server, failed := spans[0], spans[1], spans[2]
+
+	assert.Equal(t, "GET /items/:id", server.Name())
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+	assert.Equal(t, "/items/:id", attributeValue(server, "http.route").AsString())
+	assert.Equal(t, int64(http.StatusNoContent), attributeValue(server, "http.response.status_code").AsInt64())
+	assert.Equal(t, "req-1", attributeValue(server, "request_id").AsString())
+	assert.Equal(t, server.SpanContext().SpanID(), child.Parent().SpanID())
+
+	assert.Equal(t, "GET /fail", failed.Name())
+	assert.Equal(t, codes.Error, failed.Status().Code)
+	assert.False(t, failed.Parent().IsValid())
+}
+
+func TestEnd(t *testing.T) {
+	recorder := recordSpans(t)
+
+	for _, err := range []error{nil, errors.ErrNotFound, errors.ErrInternal} {
+		_, span := Start(context.Background(), "operation")
+		End(span, &err)
+	}
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Empty(t, spans[0].Events())
+
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+	assert.Len(t, spans[1].Events(), 1)
+	assert.Equal(t, errors.CodeNotFound, attributeValue(spans[1], "error.code").AsString())
+
+	assert.Equal(t, codes.Error, spans[2].Status().Code)
+}
+
+func TestCommandMonitor(t *testing.T) {
+	recorder := recordSpans(t)
+	monitor := CommandMonitor()
+
+	ctx, parent := Start(context.Background(), "parent")
+	command, err := bson.Marshal(bson.D{{Key: "find", Value: "swiftCodes"}, {Key: "filter", Value: bson.D{}}})
+	require.NoError(t, err)
+	monitor.Started(ctx, &event.CommandStartedEvent{
+		Command: command, DatabaseName: "swiftDB", CommandName: "find", RequestID: 1, ConnectionID: "conn-1",
+	})
+	monitor.Started(ctx, &event.CommandStartedEvent{
+		Command: command, DatabaseName: "swiftDB", CommandName: "find", RequestID: 2, ConnectionID: "conn-1",
+	})
+	monitor.Failed(ctx, &event.CommandFailedEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "find", RequestID: 2, ConnectionID: "conn-1"},
+		Failure:              "timeout",
+	})
+	monitor.Succeeded(ctx, &event.CommandSucceededEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "find", RequestID: 1, ConnectionID: "conn-1"},
+	})
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	failed, succeeded := spans[0], spans[1]
+
+	assert.Equal(t, "find swiftCodes", succeeded.Name())
+	assert.Equal(t, trace.SpanKindClient, succeeded.SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), succeeded.Parent().SpanID())
+	assert.Equal(t, "mongodb", attributeValue(succeeded, "db.system").AsString())
+	assert.Equal(t, "swiftDB", attributeValue(succeeded, "db.namespace").AsString())
+	assert.Equal(t, "swiftCodes", attributeValue(succeeded, "db.collection.name").AsString())
+	assert.Equal(t, codes.Unset, succeeded.Status().Code)
+
+	assert.Equal(t, codes.Error, failed.Status().Code)
+	assert.Equal(t, "timeout", failed.Status().Description)
+}
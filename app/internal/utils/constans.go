@@ -4,6 +4,24 @@ const (
 	// Route parameter names
 	ParamSwiftCode   = "swift-code"
 	ParamCountryISO2 = "countryISO2code"
+	ParamAPIKeyID    = "id"
+	ParamISO2        = "iso2"
+
+	// Query parameter names
+	QueryAsOf    = "asOf"
+	QueryFrom    = "from"
+	QueryTo      = "to"
+	QueryActor   = "actor"
+	QueryLimit   = "limit"
+	QueryFormat  = "format"
+	QueryDryRun  = "dryRun"
+	QueryCountry = "country"
+
+	// Gin context keys shared between middleware and handlers
+	ContextKeyActor         = "actor"
+	ContextKeyAffectedCodes = "affectedCodes"
+	ContextKeyAuditSkip     = "auditSkip"
+	ContextKeyErrorFormat   = "errorFormat"
 
 	// MongoDB field names
 	FieldSwiftCode     = "swiftCode"
@@ -13,4 +31,38 @@ const (
 	FieldCountryName   = "countryName"
 	FieldIsHeadquarter = "isHeadquarter"
 	FieldBranches      = "branches"
+	FieldLastModified  = "lastModified"
+
+	// MongoDB history field names
+	FieldHeadquarterCode = "headquarterCode"
+	FieldVersion         = "version"
+	FieldTimestamp       = "timestamp"
+	FieldOperation       = "operation"
+	FieldBefore          = "before"
+
+	// MongoDB audit field names
+	FieldActor = "actor"
+
+	// MongoDB API key field names
+	FieldKeyHash    = "keyHash"
+	FieldPrefix     = "prefix"
+	FieldCreatedAt  = "createdAt"
+	FieldLastUsedAt = "lastUsedAt"
+	FieldRotatedAt  = "rotatedAt"
+	FieldRevokedAt  = "revokedAt"
+
+	// MongoDB quota field names
+	FieldClient   = "client"
+	FieldDay      = "day"
+	FieldCount    = "count"
+	FieldExpireAt = "expireAt"
+
+	// Suffixes appended to the SWIFT collection name for related collections
+	HistoryCollectionSuffix = "_history"
+	CounterCollectionSuffix = "_counters"
+	AuditCollectionSuffix   = "_audit"
+	APIKeyCollectionSuffix  = "_apiKeys"
+	QuotaCollectionSuffix   = "_quotas"
+	CountryCollectionSuffix = "_countries"
+	StagingCollectionSuffix = "_staging"
 )
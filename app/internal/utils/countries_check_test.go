@@ -1,4 +1,4 @@
-// countries_check_test.go contains unit tests for country data loading and validation from CSV file.
+// countries_check_test.go contains unit tests for loading the country reference data.
 package utils
 
 import (
@@ -15,15 +15,5 @@ func TestLoadCountries(t *testing.T) {
 	poland, exists := countries["PL"]
 	assert.True(t, exists, "Poland (PL) should exist in countries")
 	assert.Equal(t, "POLAND", poland.Name, "Country name should be POLAND")
-}
-
-func TestLoadCountries_MissingFile(t *testing.T) {
-	originalPath := getCountriesCSVPath
-	getCountriesCSVPath = func() string {
-		return "nonexistent.csv"
-	}
-	defer func() { getCountriesCSVPath = originalPath }()
-
-	_, err := LoadCountries()
-	assert.Error(t, err, "Should return error when CSV file does not exist")
+	assert.Equal(t, "POL", poland.ISO3, "The embedded table should hold ISO3 codes")
 }
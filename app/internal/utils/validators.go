@@ -3,8 +3,10 @@ package utils
 import (
 	"context"
 	"strings"
+	"swift-app/internal/countries"
 	"swift-app/internal/errors"
 	"swift-app/internal/models"
+	"time"
 	"unicode"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -14,11 +16,13 @@ import (
 // ValidateCountryISO2 ensures the ISO2 country code has exactly two uppercase letters.
 func ValidateCountryISO2(iso2 string) error {
 	if len(iso2) != 2 {
-		return errors.Wrap(errors.ErrBadRequest, "country ISO2 must be 2 characters")
+		return errors.Wrap(errors.ErrBadRequest, "country ISO2 must be 2 characters").
+			WithCode(errors.CodeCountryInvalidISO2).WithFields("/countryISO2")
 	}
 	for _, r := range iso2 {
 		if r < 'A' || r > 'Z' {
-			return errors.Wrap(errors.ErrBadRequest, "country ISO2 must contain only letters")
+			return errors.Wrap(errors.ErrBadRequest, "country ISO2 must contain only letters").
+				WithCode(errors.CodeCountryInvalidISO2).WithFields("/countryISO2")
 		}
 	}
 	return nil
@@ -27,12 +31,13 @@ func ValidateCountryISO2(iso2 string) error {
 // ValidateCountryExistence verifies if the provided ISO2 code exists in the given countries map.
 func ValidateCountryExistence(iso2 string, countries map[string]models.Country) error {
 	if _, ok := countries[iso2]; !ok {
-		return errors.Wrap(errors.ErrBadRequest, "country ISO2 '%s' not found", iso2)
+		return errors.Wrap(errors.ErrBadRequest, "country ISO2 '%s' not found", iso2).
+			WithCode(errors.CodeCountryNotFound).WithFields("/countryISO2")
 	}
 	return nil
 }
 
-// LoadAndValidateCountry loads countries from file and checks if provided ISO2 is valid.
+// LoadAndValidateCountry checks that the provided ISO2 code is valid and in the country reference data.
 func LoadAndValidateCountry(iso2 string) (map[string]models.Country, error) {
 	if err := ValidateCountryISO2(iso2); err != nil {
 		return nil, err
@@ -40,7 +45,7 @@ func LoadAndValidateCountry(iso2 string) (map[string]models.Country, error) {
 
 	countries, err := LoadCountries()
 	if err != nil {
-		return nil, errors.Wrap(errors.ErrInternal, "error loading country data")
+		return nil, errors.Wrap(errors.ErrInternal, "error loading country data").WithCause(err)
 	}
 
 	if err := ValidateCountryExistence(iso2, countries); err != nil {
@@ -50,36 +55,47 @@ func LoadAndValidateCountry(iso2 string) (map[string]models.Country, error) {
 	return countries, nil
 }
 
+// ValidSwiftCodeLength reports whether n is the length of a SWIFT code: 8 characters, or 11 with a branch code.
+func ValidSwiftCodeLength(n int) bool {
+	return n == 8 || n == 11
+}
+
 // ValidateSwiftCode validates the length of the provided SWIFT code.
 func ValidateSwiftCode(swiftCode string) error {
 	if len(swiftCode) == 0 {
-		return errors.Wrap(errors.ErrBadRequest, "missing SWIFT code")
+		return errors.Wrap(errors.ErrBadRequest, "missing SWIFT code").
+			WithCode(errors.CodeSwiftMissing).WithFields("/swiftCode")
 	}
-	if len(swiftCode) != 8 && len(swiftCode) != 11 {
-		return errors.Wrap(errors.ErrBadRequest, "SWIFT code must be 8 or 11 characters")
+	if !ValidSwiftCodeLength(len(swiftCode)) {
+		return errors.Wrap(errors.ErrBadRequest, "SWIFT code must be 8 or 11 characters").
+			WithCode(errors.CodeSwiftInvalidLength).WithFields("/swiftCode")
 	}
 	for _, r := range swiftCode {
 		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
-			return errors.Wrap(errors.ErrBadRequest, "SWIFT code can only contain letters and digits (no spaces or special characters)")
+			return errors.Wrap(errors.ErrBadRequest, "SWIFT code can only contain letters and digits (no spaces or special characters)").
+				WithCode(errors.CodeSwiftInvalidCharacters).WithFields("/swiftCode")
 		}
 	}
 	return nil
 }
 
-// ValidateCountryNameMatch checks if the provided country name matches the expected name from ISO2 code.
-func ValidateCountryNameMatch(iso2 string, inputName string, countries map[string]models.Country) error {
-	expected := countries[iso2].Name
-	if !strings.EqualFold(inputName, expected) {
-		return errors.Wrap(errors.ErrBadRequest, "country name '%s' does not match ISO2 '%s'", inputName, iso2)
+// ValidateCountryNameMatch checks that the provided country name is a name of the country with the
+// ISO2 code: its name in the reference data or one of its official, short, common or localized
+// aliases, ignoring case, diacritics and punctuation. The match carries the name to store.
+func ValidateCountryNameMatch(iso2 string, inputName string) (countries.NameMatch, error) {
+	match, ok := countries.Default().MatchName(iso2, inputName)
+	if !ok {
+		return match, errors.Wrap(errors.ErrBadRequest, "country name '%s' does not match ISO2 '%s'", inputName, iso2).
+			WithCode(errors.CodeCountryNameMismatch).WithFields("/countryName", "/countryISO2")
 	}
-	return nil
+	return match, nil
 }
 
 // GetHeadquarterBySwiftCode retrieves the headquarter SWIFT entry for a given SWIFT code.
-func GetHeadquarterBySwiftCode(db *mongo.Collection, swiftCode string) (*models.SwiftCode, error) {
+func GetHeadquarterBySwiftCode(ctx context.Context, db *mongo.Collection, swiftCode string) (*models.SwiftCode, error) {
 	headquarterCode := swiftCode[:8] + "XXX"
 	var headquarter models.SwiftCode
-	err := db.FindOne(context.Background(), bson.M{
+	err := db.FindOne(ctx, bson.M{
 		FieldSwiftCode:     headquarterCode,
 		FieldIsHeadquarter: true,
 	}).Decode(&headquarter)
@@ -87,35 +103,77 @@ func GetHeadquarterBySwiftCode(db *mongo.Collection, swiftCode string) (*models.
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			if strings.HasSuffix(swiftCode, "XXX") {
-				return nil, errors.Wrap(errors.ErrNotFound, "headquarter not found: %s", swiftCode)
+				return nil, errors.Wrap(errors.ErrNotFound, "headquarter not found: %s", swiftCode).
+					WithCode(errors.CodeHQNotFound)
 			}
-			return nil, errors.Wrap(errors.ErrNotFound, "cannot perform action with branch '%s' because its headquarter '%s' is missing", swiftCode, headquarterCode)
+			return nil, errors.Wrap(errors.ErrNotFound, "cannot perform action with branch '%s' because its headquarter '%s' is missing", swiftCode, headquarterCode).
+				WithCode(errors.CodeHQNotFound)
 		}
-		return nil, errors.Wrap(errors.ErrInternal, "database error while searching for headquarter")
+		return nil, errors.Wrap(errors.ErrInternal, "database error while searching for headquarter").WithCause(err)
 	}
 
 	return &headquarter, nil
 }
 
-// LoadAndValidateCountryWithName loads and validates a country by ISO2 and verifies the provided country name.
-func LoadAndValidateCountryWithName(iso2, inputName string) (map[string]models.Country, error) {
-	countries, err := LoadAndValidateCountry(iso2)
-	if err != nil {
-		return nil, err
+// LoadAndValidateCountryWithName validates a country by ISO2 and matches the provided country name against its names.
+func LoadAndValidateCountryWithName(iso2, inputName string) (countries.NameMatch, error) {
+	if _, err := LoadAndValidateCountry(iso2); err != nil {
+		return countries.NameMatch{}, err
 	}
-	if err := ValidateCountryNameMatch(iso2, inputName, countries); err != nil {
-		return nil, err
-	}
-	return countries, nil
+	return ValidateCountryNameMatch(iso2, inputName)
 }
 
 // ValidateSwiftCodeSuffix checks whether SWIFT code suffix matches expected format for HQ or branch.
 func ValidateSwiftCodeSuffix(swiftCode string, isHeadquarter bool) error {
 	if isHeadquarter && !strings.HasSuffix(swiftCode, "XXX") {
-		return errors.Wrap(errors.ErrBadRequest, "HQ SWIFT code must end with 'XXX'")
+		return errors.Wrap(errors.ErrBadRequest, "HQ SWIFT code must end with 'XXX'").
+			WithCode(errors.CodeSwiftHQSuffixRequired).WithFields("/swiftCode", "/isHeadquarter")
 	}
 	if !isHeadquarter && strings.HasSuffix(swiftCode, "XXX") {
-		return errors.Wrap(errors.ErrBadRequest, "branch SWIFT code cannot end with 'XXX'")
+		return errors.Wrap(errors.ErrBadRequest, "branch SWIFT code cannot end with 'XXX'").
+			WithCode(errors.CodeSwiftBranchSuffixReserved).WithFields("/swiftCode", "/isHeadquarter")
 	}
 	return nil
 }
+
+// ValidateSwiftCodeInput runs every domain check on a new SWIFT code (format, HQ/branch suffix,
+// country and country name) and reports all failures together; see errors.Collect. It returns the
+// name of the country to store, which may differ from countryName when that is an alias.
+// Values are expected in upper case.
+func ValidateSwiftCodeInput(swiftCode, countryISO2, countryName string, isHeadquarter bool) (string, error) {
+	codeErr := ValidateSwiftCode(swiftCode)
+	var suffixErr error
+	if codeErr == nil {
+		suffixErr = ValidateSwiftCodeSuffix(swiftCode, isHeadquarter)
+	}
+	match, countryErr := LoadAndValidateCountryWithName(countryISO2, countryName)
+	return match.Name, errors.Collect(codeErr, suffixErr, countryErr)
+}
+
+// ParseAsOf parses a point-in-time query value given either as an RFC 3339 timestamp
+// or as a date (YYYY-MM-DD), which is interpreted as the end of that day in UTC.
+func ParseAsOf(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	day, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, errors.Wrap(errors.ErrBadRequest, "invalid asOf value '%s', expected RFC 3339 timestamp or YYYY-MM-DD date", value).
+			WithCode(errors.CodeInvalidTime)
+	}
+	return day.Add(24*time.Hour - time.Millisecond), nil
+}
+
+// ParseRangeStart parses the start of a time range given either as an RFC 3339 timestamp
+// or as a date (YYYY-MM-DD), which is interpreted as the start of that day in UTC.
+func ParseRangeStart(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	day, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, errors.Wrap(errors.ErrBadRequest, "invalid time value '%s', expected RFC 3339 timestamp or YYYY-MM-DD date", value).
+			WithCode(errors.CodeInvalidTime)
+	}
+	return day, nil
+}
@@ -43,16 +43,38 @@ func TestValidateCountryExistence(t *testing.T) {
 }
 
 func TestValidateCountryNameMatch(t *testing.T) {
-	countries := map[string]models.Country{
-		"PL": {ISO2: "PL", Name: "POLAND"},
+	match, err := ValidateCountryNameMatch("PL", "poland")
+	assert.NoError(t, err)
+	assert.Equal(t, "POLAND", match.Name)
+	assert.Nil(t, match.Alias, "the name of the country is not an alias")
+
+	match, err = ValidateCountryNameMatch("CI", "COTE D'IVOIRE")
+	assert.NoError(t, err, "diacritics and apostrophes should be ignored")
+	assert.Equal(t, "CÔTE D’IVOIRE", match.Name)
+	assert.Nil(t, match.Alias)
+
+	for name, want := range map[string]string{
+		"United States of America": "UNITED STATES",
+		"CZECHIA":                  "CZECH REPUBLIC",
+		"Deutschland":              "GERMANY",
+		"Turkiye":                  "TURKEY",
+		"ÖSTERREICH":               "AUSTRIA",
+		"osterreich":               "AUSTRIA",
+		"Korea, Republic of":       "SOUTH KOREA",
+	} {
+		iso2 := map[string]string{"UNITED STATES": "US", "CZECH REPUBLIC": "CZ", "GERMANY": "DE", "TURKEY": "TR",
+			"AUSTRIA": "AT", "SOUTH KOREA": "KR"}[want]
+		match, err := ValidateCountryNameMatch(iso2, name)
+		if assert.NoError(t, err, name) && assert.NotNil(t, match.Alias, name) {
+			assert.Equal(t, want, match.Name, name)
+		}
 	}
 
-	assert.NoError(t, ValidateCountryNameMatch("PL", "POLAND", countries))
-	assert.NoError(t, ValidateCountryNameMatch("PL", "poland", countries))
-
-	err := ValidateCountryNameMatch("PL", "Germany", countries)
+	_, err = ValidateCountryNameMatch("PL", "Germany")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "country name 'Germany' does not match ISO2 'PL'")
+	_, err = ValidateCountryNameMatch("AT", "Deutschland")
+	assert.Error(t, err, "aliases only match their own country")
 }
 
 func TestLoadAndValidateCountry(t *testing.T) {
@@ -65,11 +87,25 @@ func TestLoadAndValidateCountry(t *testing.T) {
 }
 
 func TestLoadAndValidateCountryWithName(t *testing.T) {
-	countries, err := LoadAndValidateCountryWithName("PL", "POLAND")
+	match, err := LoadAndValidateCountryWithName("PL", "POLSKA")
 	assert.NoError(t, err)
-	assert.NotEmpty(t, countries)
+	assert.Equal(t, "POLAND", match.Name)
 
 	_, err = LoadAndValidateCountryWithName("PL", "GERMANY")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "country name 'GERMANY' does not match ISO2 'PL'")
 }
+
+func TestParseAsOf(t *testing.T) {
+	at, err := ParseAsOf("2026-01-31")
+	assert.NoError(t, err)
+	assert.Equal(t, 31, at.Day())
+	assert.Equal(t, 23, at.Hour(), "a date should be interpreted as the end of that day")
+
+	at, err = ParseAsOf("2026-01-31T10:00:00+02:00")
+	assert.NoError(t, err)
+	assert.Equal(t, 8, at.Hour(), "timestamps should be normalized to UTC")
+
+	_, err = ParseAsOf("31/01/2026")
+	assert.Error(t, err)
+}
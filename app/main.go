@@ -1,76 +1,37 @@
 // Package main is the entry point of the Swift App API.
-// It initializes the environment, database, imports data from CSV, and starts the server.
+// It runs the subcommands of the cli package: serving the API, importing and exporting SWIFT data, and more.
 package main
 
 import (
-	"fmt"
-	"log"
+	"context"
 	"os"
 	"os/signal"
-	"swift-app/cmd/server"
-	"swift-app/database"
+	"swift-app/cmd/cli"
 	_ "swift-app/docs"
-	"swift-app/initialization"
 	"syscall"
-
-	"github.com/joho/godotenv"
 )
 
-// main initializes environment variables, connects to the database, imports SWIFT data, and starts the HTTP server.
+// main runs the command given on the command line, such as serve or import, until it finishes or the process is signalled.
 // @title Swift App API
 // @version 1.0
 // @description This is a Swift Code management API.
+// @description Routes require the swift:read, swift:write or swift:admin scope, granted by an API key or a JWT.
+// @description Errors are RFC 7807 problem details (application/problem+json) with a stable machine-readable code; ERROR_FORMAT=legacy restores {"message": "..."} bodies.
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description API key with swift:read, swift:write or swift:admin scopes.
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description HS256 or RS256 JWT sent as "Bearer <token>"; scopes are read from the scope or scp claim.
 func main() {
-	err := godotenv.Load()
-	if err != nil {
-		log.Println("No .env file found, using default values.")
-	}
-
-	mongoURI := os.Getenv("MONGO_URI")
-	mongoDB := os.Getenv("MONGO_DB")
-	mongoCollection := os.Getenv("MONGO_COLLECTION")
-	csvPath := os.Getenv("CSV_PATH")
-
-	err = initialization.InitializeDatabase(mongoURI, mongoDB, mongoCollection)
-	if err != nil {
-		log.Fatalf("Failed to initialize database: %v", err)
-	}
-
-	summary, err := initialization.ImportData(csvPath)
-	if err != nil {
-		log.Fatalf("Failed to import data: %v", err)
-	}
-
-	log.Printf(`
-Data import complete.
-Headquarters added: %d
-Skipped HQs (duplicates): %d
-
-Branches added: %d
-Duplicate branches: %d
-Branches with missing HQ: %d
-All skipped branches: %d
-`, summary.HQAdded, summary.HQSkipped, summary.BranchesAdded, summary.BranchesDuplicate, summary.BranchesMissingHQ, summary.BranchesSkipped)
-
-	handleShutdown()
-	fmt.Println("Starting application...")
-	server.StartServer()
-}
-
-// handleShutdown listens for termination signals and gracefully closes the MongoDB connection.
-func handleShutdown() {
-	sigs := make(chan os.Signal, 1)
-	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
-
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	go func() {
-		<-sigs
-		fmt.Println("\nShutdown requested, closing database connection...")
-		err := database.CloseMongoDB()
-		if err != nil {
-			log.Println("Error closing database:", err)
-		} else {
-			fmt.Println("Database connection closed.")
-		}
-		os.Exit(0)
+		// A second signal stops the process without waiting for the drain.
+		<-ctx.Done()
+		stop()
 	}()
+
+	os.Exit(cli.Run(ctx, os.Args[1:], os.Stdout, os.Stderr, os.LookupEnv))
 }
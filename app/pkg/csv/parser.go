@@ -1,42 +1,116 @@
 package csv
 
 import (
-	"encoding/csv"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"strings"
+	"swift-app/internal/countries"
 	"swift-app/internal/models"
 	"swift-app/internal/utils"
 )
 
 // LoadSwiftCodes loads and parses a CSV file containing SWIFT code data, validates each record, and returns a list of unique, validated SWIFT codes.
 func LoadSwiftCodes(filePath string) ([]models.SwiftCode, error) {
-	file, err := os.Open(filePath)
+	records, err := readFile(filePath)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
+	return LoadRecords(records)
+}
+
+// Record is one SWIFT code as read from an import file, before it is validated. Row is the line
+// of the record in its file, counting a header as row 1.
+type Record struct {
+	Row         int
+	SwiftCode   string
+	CountryISO2 string
+	BankName    string
+	Address     string
+	CountryName string
+}
+
+// RecordError describes a record rejected while parsing. Row counts the header as row 1.
+type RecordError struct {
+	Row       int    `json:"row"`
+	SwiftCode string `json:"swiftCode"`
+	Message   string `json:"message"`
+}
+
+// RecordWarning describes a record accepted with a change, such as a country name given as an alias
+// and stored as the name of the country. Row counts the header as row 1.
+type RecordWarning struct {
+	Row       int    `json:"row"`
+	SwiftCode string `json:"swiftCode"`
+	Message   string `json:"message"`
+}
+
+// Report summarizes the validation of a CSV file.
+type Report struct {
+	Records    int             `json:"records"`
+	Valid      int             `json:"valid"`
+	Duplicates int             `json:"duplicates"`
+	Errors     []RecordError   `json:"errors"`
+	Warnings   []RecordWarning `json:"warnings"`
+}
 
-	reader := csv.NewReader(file)
-	reader.LazyQuotes = true
-	records, err := reader.ReadAll()
+// ValidateFile parses and validates a CSV file the way LoadSwiftCodes does, without importing it,
+// and reports every rejected record. An error is returned only when the file cannot be read at all.
+func ValidateFile(filePath string) (*Report, error) {
+	records, err := readFile(filePath)
 	if err != nil {
 		return nil, err
 	}
+	return ValidateRecords(records)
+}
 
-	header := SanitizeHeader(records[0])
-	fieldIndexes := GetFieldIndexes(header)
-
-	if fieldIndexes["SWIFT CODE"] == -1 {
-		return nil, fmt.Errorf("missing required field: SWIFT CODE")
+// LoadRecords validates records read from a file of any format and returns the unique, valid SWIFT
+// codes. Invalid records are logged and skipped, and so are the warnings of valid ones.
+func LoadRecords(records []Record) ([]models.SwiftCode, error) {
+	countries, err := utils.LoadCountries()
+	if err != nil {
+		return nil, fmt.Errorf("error loading country data: %v", err)
 	}
+	return buildSwiftCodes(records, countries, func(record Record, err error) {
+		slog.Warn("skipping invalid record", "row", record.Row, "swift_code", record.SwiftCode, "error", err)
+	}, func(record Record, message string) {
+		slog.Warn("importing record with a warning", "row", record.Row, "swift_code", record.SwiftCode, "warning", message)
+	}), nil
+}
 
+// ValidateRecords validates records read from a file of any format the way LoadRecords does and
+// reports every rejected record.
+func ValidateRecords(records []Record) (*Report, error) {
 	countries, err := utils.LoadCountries()
 	if err != nil {
 		return nil, fmt.Errorf("error loading country data: %v", err)
 	}
+	report := &Report{Records: len(records), Errors: []RecordError{}, Warnings: []RecordWarning{}}
+	codes := buildSwiftCodes(records, countries, func(record Record, err error) {
+		report.Errors = append(report.Errors, RecordError{Row: record.Row, SwiftCode: record.SwiftCode, Message: err.Error()})
+	}, func(record Record, message string) {
+		report.Warnings = append(report.Warnings, RecordWarning{Row: record.Row, SwiftCode: record.SwiftCode, Message: message})
+	})
+	report.Valid = len(codes)
+	report.Duplicates = report.Records - report.Valid - len(report.Errors)
+	return report, nil
+}
+
+// readFile reads the records of a CSV file.
+func readFile(filePath string) ([]Record, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadRecords(file)
+}
 
-	return ProcessRecords(records[1:], fieldIndexes, countries)
+// ReadRecords reads the records of a CSV file with a header row, mapping its columns with DefaultProfile.
+// Values are normalized but not validated.
+func ReadRecords(r io.Reader) ([]Record, error) {
+	return DefaultProfile().ReadRecords(r)
 }
 
 // SanitizeHeader converts all header fields to uppercase and trims whitespace to ensure consistent field matching.
@@ -47,49 +121,19 @@ func SanitizeHeader(header []string) []string {
 	return header
 }
 
-// GetFieldIndexes maps column names (including supported aliases) to their respective indexes in the CSV header row.
-
-func GetFieldIndexes(header []string) map[string]int {
-	fieldIndexes := map[string]int{
-		"SWIFT CODE":        -1,
-		"COUNTRY ISO2 CODE": -1,
-		"NAME":              -1,
-		"ADDRESS":           -1,
-		"COUNTRY NAME":      -1,
-	}
-
-	aliases := map[string]string{
-		"SWIFTCODE":   "SWIFT CODE",
-		"SWIFT CODES": "SWIFT CODE",
-		"SWIFT_CODE":  "SWIFT CODE",
-		"SWIFT C0DE":  "SWIFT CODE",
-	}
-
-	for i, field := range header {
-		upperField := strings.ToUpper(strings.TrimSpace(field))
-
-		if alias, ok := aliases[upperField]; ok {
-			upperField = alias
-		}
-
-		if _, exists := fieldIndexes[upperField]; exists {
-			fieldIndexes[upperField] = i
-		}
-	}
-
-	return fieldIndexes
-}
-
-// ProcessRecords processes all rows from the CSV file, validates them, and constructs SwiftCode structs while skipping duplicates or invalid entries.
-func ProcessRecords(records [][]string, fieldIndexes map[string]int, countries map[string]models.Country) ([]models.SwiftCode, error) {
+// buildSwiftCodes builds the unique, valid SWIFT codes of records, calls reject for every invalid one
+// and warn for every valid one stored with a change. Duplicates are skipped silently.
+func buildSwiftCodes(records []Record, countries map[string]models.Country, reject func(record Record, err error),
+	warn func(record Record, message string)) []models.SwiftCode {
 	swiftCodes := []models.SwiftCode{}
 	uniqueCodes := make(map[string]bool)
 
 	for _, record := range records {
-		swiftCode, countryISO2, bankName, address, countryName := ExtractRecordData(record, fieldIndexes)
+		swiftCode := record.SwiftCode
 
-		if err := ValidateRecord(swiftCode, countryISO2, countryName, countries); err != nil {
-			fmt.Printf("Warning: %s - %v\n", swiftCode, err)
+		match, err := ValidateRecord(swiftCode, record.CountryISO2, record.CountryName, countries)
+		if err != nil {
+			reject(record, err)
 			continue
 		}
 
@@ -100,60 +144,55 @@ func ProcessRecords(records [][]string, fieldIndexes map[string]int, countries m
 
 		isHeadquarter := strings.HasSuffix(swiftCode, "XXX")
 		if err := utils.ValidateSwiftCodeSuffix(swiftCode, isHeadquarter); err != nil {
-			fmt.Printf("Warning: %s - %v\n", swiftCode, err)
+			reject(record, err)
 			continue
 		}
+		if match.Alias != nil {
+			warn(record, fmt.Sprintf("country name '%s' is the %s %s name of %s and is stored as '%s'",
+				record.CountryName, match.Alias.Language, match.Alias.Kind, record.CountryISO2, match.Name))
+		}
+		record.CountryName = match.Name
 
 		if isHeadquarter {
 			swiftCodes = append(swiftCodes, models.SwiftCode{
 				SwiftCode:     swiftCode,
-				CountryISO2:   countryISO2,
-				BankName:      bankName,
-				Address:       address,
-				CountryName:   countryName,
+				CountryISO2:   record.CountryISO2,
+				BankName:      record.BankName,
+				Address:       record.Address,
+				CountryName:   record.CountryName,
 				IsHeadquarter: true,
 				Branches:      []models.SwiftBranch{},
 			})
 		} else {
 			swiftCodes = append(swiftCodes, models.SwiftCode{
 				SwiftCode:     swiftCode,
-				CountryISO2:   countryISO2,
-				BankName:      bankName,
-				Address:       address,
-				CountryName:   countryName,
+				CountryISO2:   record.CountryISO2,
+				BankName:      record.BankName,
+				Address:       record.Address,
+				CountryName:   record.CountryName,
 				IsHeadquarter: false,
 			})
 		}
 	}
 
-	return swiftCodes, nil
+	return swiftCodes
 }
 
-// ExtractRecordData extracts and normalizes (uppercase/trim) the values for SWIFT code, ISO2, bank name, address, and country name from a CSV row.
-func ExtractRecordData(record []string, fieldIndexes map[string]int) (string, string, string, string, string) {
-	swiftCode := strings.TrimSpace(strings.ToUpper(record[fieldIndexes["SWIFT CODE"]]))
-	countryISO2 := strings.TrimSpace(strings.ToUpper(record[fieldIndexes["COUNTRY ISO2 CODE"]]))
-	bankName := strings.ToUpper(record[fieldIndexes["NAME"]])
-	address := strings.ToUpper(record[fieldIndexes["ADDRESS"]])
-	countryName := strings.ToUpper(record[fieldIndexes["COUNTRY NAME"]])
-
-	return swiftCode, countryISO2, bankName, address, countryName
-}
-
-// ValidateRecord validates the extracted data from a record against SWIFT code rules and the provided country map.
-func ValidateRecord(swiftCode, countryISO2, countryName string, countries map[string]models.Country) error {
+// ValidateRecord validates the extracted data from a record against SWIFT code rules and the provided
+// country map, and returns the match of its country name, which carries the name to store.
+func ValidateRecord(swiftCode, countryISO2, countryName string, known map[string]models.Country) (countries.NameMatch, error) {
 	if err := utils.ValidateSwiftCode(swiftCode); err != nil {
-		return fmt.Errorf("invalid SWIFT code: %v", err)
+		return countries.NameMatch{}, fmt.Errorf("invalid SWIFT code: %v", err)
 	}
 	if err := utils.ValidateCountryISO2(countryISO2); err != nil {
-		return fmt.Errorf("invalid ISO2 country code: %v", err)
+		return countries.NameMatch{}, fmt.Errorf("invalid ISO2 country code: %v", err)
 	}
-	if err := utils.ValidateCountryExistence(countryISO2, countries); err != nil {
-		return fmt.Errorf("invalid country: %v", err)
+	if err := utils.ValidateCountryExistence(countryISO2, known); err != nil {
+		return countries.NameMatch{}, fmt.Errorf("invalid country: %v", err)
 	}
-	if err := utils.ValidateCountryNameMatch(countryISO2, countryName, countries); err != nil {
-		return fmt.Errorf("country name mismatch: %v", err)
+	match, err := utils.ValidateCountryNameMatch(countryISO2, countryName)
+	if err != nil {
+		return match, fmt.Errorf("country name mismatch: %v", err)
 	}
-
-	return nil
+	return match, nil
 }
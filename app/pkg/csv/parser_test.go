@@ -4,7 +4,6 @@ package csv
 import (
 	"os"
 	"path/filepath"
-	"runtime"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -102,8 +101,8 @@ func TestLoadSwiftCodesFileNotFound(t *testing.T) {
 }
 
 func TestLoadSwiftCodesInvalidFormat(t *testing.T) {
-	const invalidCSV = `COUNTRY ISO2 CODE;SWIFT CODE;TYPE;NAME;ADDRESS;TOWN NAME;COUNTRY NAME;TIMEZONE
-PL;TPEOPLPWKOP;BIC11;PEKAO;"FOREST ZUBRA 1";WARSZAWA;POLAND;Europe/Warsaw`
+	const invalidCSV = `COUNTRY ISO2 CODE,SWIFT CODE,TYPE,NAME,ADDRESS,TOWN NAME,COUNTRY NAME,TIMEZONE
+PL,TPEOPLPWKOP,BIC11,PEKAO,"FOREST ZUBRA 1",WARSZAWA,POLAND,Europe/Warsaw,EXTRA`
 
 	tmpFile, err := os.CreateTemp("", "invalid_format.csv")
 	if err != nil {
@@ -204,19 +203,6 @@ func TestLoadSwiftCodesFromFile(t *testing.T) {
 }
 
 func TestLoadSwiftCodes_HeaderAliases(t *testing.T) {
-	_, currentFilePath, _, _ := runtime.Caller(0)
-	projectRoot := filepath.Join(filepath.Dir(currentFilePath), "../..")
-	resourceDir := filepath.Join(projectRoot, "internal", "resources")
-	_ = os.MkdirAll(resourceDir, os.ModePerm)
-
-	countriesCSVPath := filepath.Join(resourceDir, "countries_test.csv")
-	err := os.WriteFile(countriesCSVPath, []byte("ISO2,NAME\nUS,UNITED STATES"), 0644)
-	assert.NoError(t, err)
-
-	t.Cleanup(func() {
-		_ = os.Remove(countriesCSVPath)
-	})
-
 	testCases := map[string]string{
 		"SWIFT CODE":  "SWIFT CODE,COUNTRY ISO2 CODE,NAME,ADDRESS,COUNTRY NAME\nAAAABBB1XXX,US,First Bank,123 First St,UNITED STATES",
 		"SWIFTCODE":   "SWIFTCODE,COUNTRY ISO2 CODE,NAME,ADDRESS,COUNTRY NAME\nAAAABBB1XXX,US,First Bank,123 First St,UNITED STATES",
@@ -242,3 +228,56 @@ func TestLoadSwiftCodes_HeaderAliases(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "validate.csv")
+	data := `SWIFT CODE,COUNTRY ISO2 CODE,NAME,ADDRESS,COUNTRY NAME
+AAAABBB1XXX,US,First Bank,123 First St,United States
+AAAABBB1XXX,US,First Bank,123 First St,United States
+AAAABBB1123,U1,Second Bank,456 Second St,United States
+SHORT,US,Third Bank,789 Third St,United States
+AAAABBB1456,US,Fourth Bank,1 Fourth St,United States
+`
+	assert.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+
+	report, err := ValidateFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, report.Records)
+	assert.Equal(t, 2, report.Valid)
+	assert.Equal(t, 1, report.Duplicates)
+	if assert.Len(t, report.Errors, 2) {
+		assert.Equal(t, 4, report.Errors[0].Row)
+		assert.Equal(t, "AAAABBB1123", report.Errors[0].SwiftCode)
+		assert.Contains(t, report.Errors[0].Message, "ISO2")
+		assert.Equal(t, 5, report.Errors[1].Row)
+		assert.Equal(t, "SHORT", report.Errors[1].SwiftCode)
+	}
+
+	assert.NoError(t, os.WriteFile(path, nil, 0o600))
+	_, err = ValidateFile(path)
+	assert.ErrorContains(t, err, "file is empty")
+}
+
+func TestLoadRecords_CountryAliases(t *testing.T) {
+	records := []Record{
+		{Row: 2, SwiftCode: "AAAADEFFXXX", CountryISO2: "DE", BankName: "FIRST BANK", CountryName: "DEUTSCHLAND"},
+		{Row: 3, SwiftCode: "AAAAUS33XXX", CountryISO2: "US", BankName: "SECOND BANK", CountryName: "UNITED STATES OF AMERICA"},
+		{Row: 4, SwiftCode: "AAAAATWWXXX", CountryISO2: "AT", BankName: "THIRD BANK", CountryName: "DEUTSCHLAND"},
+	}
+
+	codes, err := LoadRecords(records)
+	assert.NoError(t, err)
+	if assert.Len(t, codes, 2, "an alias of another country should be rejected") {
+		assert.Equal(t, "GERMANY", codes[0].CountryName, "aliases should be stored as the name of the country")
+		assert.Equal(t, "UNITED STATES", codes[1].CountryName)
+	}
+
+	report, err := ValidateRecords(records)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.Valid)
+	assert.Len(t, report.Errors, 1)
+	if assert.Len(t, report.Warnings, 2) {
+		assert.Equal(t, RecordWarning{Row: 3, SwiftCode: "AAAAUS33XXX",
+			Message: "country name 'UNITED STATES OF AMERICA' is the en official name of US and is stored as 'UNITED STATES'"}, report.Warnings[1])
+	}
+}
@@ -0,0 +1,325 @@
+package csv
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/language"
+	"golang.org/x/text/transform"
+	"gopkg.in/yaml.v3"
+)
+
+// Fields a profile maps columns to.
+const (
+	FieldSwiftCode   = "swiftCode"
+	FieldCountryISO2 = "countryISO2"
+	FieldBankName    = "bankName"
+	FieldAddress     = "address"
+	FieldCountryName = "countryName"
+)
+
+// Fields lists the fields of a record in the order they are reported.
+var Fields = []string{FieldSwiftCode, FieldCountryISO2, FieldBankName, FieldAddress, FieldCountryName}
+
+// Transforms a field mapping can apply to its values, in the order they are listed.
+const (
+	// TransformTrim removes leading and trailing white space.
+	TransformTrim = "trim"
+	// TransformUppercase converts a value to upper case.
+	TransformUppercase = "uppercase"
+	// TransformTitleCase capitalizes the first letter of every word and lowers the others.
+	TransformTitleCase = "title-case"
+	// TransformSplitAddress splits an address on line breaks, commas, semicolons and pipes and joins
+	// its non-empty parts with ", ", the way addresses are stored.
+	TransformSplitAddress = "split-address"
+)
+
+var transforms = map[string]func(string) string{
+	TransformTrim:         strings.TrimSpace,
+	TransformUppercase:    strings.ToUpper,
+	TransformTitleCase:    titleCase,
+	TransformSplitAddress: splitAddress,
+}
+
+// delimiters are the delimiters detected when a profile sets none, most likely first.
+var delimiters = []rune{',', ';', '\t', '|'}
+
+// Profile maps the columns of a CSV file to the fields of a record. Profiles are written in YAML or
+// JSON, for example:
+//
+//	name: polish-bank
+//	delimiter: ";"
+//	encoding: windows-1250
+//	fields:
+//	  swiftCode:   {headers: [BIC], required: true, transforms: [trim, uppercase]}
+//	  countryISO2: {headers: [KRAJ], required: true, transforms: [trim, uppercase]}
+//	  bankName:    {headers: [NAZWA], transforms: [trim]}
+//	  address:     {headers: [ADRES], transforms: [split-address]}
+//	  countryName: {default: POLAND}
+type Profile struct {
+	// Name identifies the profile in logs; it defaults to the file name.
+	Name string `yaml:"name"`
+	// Delimiter separates the columns. When empty it is detected from the header row.
+	Delimiter string `yaml:"delimiter"`
+	// Encoding is the WHATWG name of the character encoding, such as utf-8, utf-16le or
+	// windows-1250. A byte order mark overrides it; the default is UTF-8.
+	Encoding string `yaml:"encoding"`
+	// Fields maps field names (FieldSwiftCode and so on) to the columns they are read from.
+	Fields map[string]FieldMapping `yaml:"fields"`
+}
+
+// FieldMapping reads one field of a record.
+type FieldMapping struct {
+	// Headers are the accepted names of the column, matched case-insensitively. The first one in
+	// the header row is used.
+	Headers []string `yaml:"headers"`
+	// Required fails the file when none of Headers is in the header row.
+	Required bool `yaml:"required"`
+	// Default is the value of the field when its column is missing or the value is empty after
+	// the transforms.
+	Default string `yaml:"default"`
+	// Transforms are applied to every value in order.
+	Transforms []string `yaml:"transforms"`
+}
+
+// DefaultProfile returns the profile of the CSV layout the importer reads when no profile is
+// selected: comma or semicolon separated UTF-8 files with the columns of the SWIFT code file.
+func DefaultProfile() *Profile {
+	return &Profile{
+		Name: "default",
+		Fields: map[string]FieldMapping{
+			FieldSwiftCode: {
+				Headers:    []string{"SWIFT CODE", "SWIFTCODE", "SWIFT CODES", "SWIFT_CODE", "SWIFT C0DE"},
+				Required:   true,
+				Transforms: []string{TransformUppercase, TransformTrim},
+			},
+			FieldCountryISO2: {
+				Headers:    []string{"COUNTRY ISO2 CODE"},
+				Required:   true,
+				Transforms: []string{TransformUppercase, TransformTrim},
+			},
+			FieldBankName:    {Headers: []string{"NAME"}, Required: true, Transforms: []string{TransformUppercase}},
+			FieldAddress:     {Headers: []string{"ADDRESS"}, Transforms: []string{TransformUppercase}},
+			FieldCountryName: {Headers: []string{"COUNTRY NAME"}, Required: true, Transforms: []string{TransformUppercase}},
+		},
+	}
+}
+
+// LoadProfile reads and validates a profile from a YAML or JSON file.
+func LoadProfile(path string) (*Profile, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// JSON is valid YAML, so one decoder reads both.
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	var profile Profile
+	if err := decoder.Decode(&profile); err != nil {
+		return nil, fmt.Errorf("invalid profile %s: %w", path, err)
+	}
+	if profile.Name == "" {
+		profile.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if err := profile.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid profile %s: %w", path, err)
+	}
+	return &profile, nil
+}
+
+// Validate checks that the profile names known fields, transforms and encodings, and that every
+// field can get a value.
+func (p *Profile) Validate() error {
+	if p.Delimiter != "" {
+		if _, err := p.delimiter(); err != nil {
+			return err
+		}
+	}
+	if _, err := p.decoder(); err != nil {
+		return err
+	}
+	if len(p.Fields[FieldSwiftCode].Headers) == 0 {
+		return fmt.Errorf("field %s must name its headers", FieldSwiftCode)
+	}
+	for name, mapping := range p.Fields {
+		if !slices.Contains(Fields, name) {
+			return fmt.Errorf("unknown field %q, expected one of %s", name, strings.Join(Fields, ", "))
+		}
+		if len(mapping.Headers) == 0 && mapping.Default == "" {
+			return fmt.Errorf("field %s needs headers or a default", name)
+		}
+		if mapping.Required && len(mapping.Headers) == 0 {
+			return fmt.Errorf("field %s is required but names no headers", name)
+		}
+		for _, transform := range mapping.Transforms {
+			if _, ok := transforms[transform]; !ok {
+				return fmt.Errorf("field %s: unknown transform %q, expected one of %s, %s, %s or %s",
+					name, transform, TransformTrim, TransformUppercase, TransformTitleCase, TransformSplitAddress)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *Profile) delimiter() (rune, error) {
+	r, size := utf8.DecodeRuneInString(p.Delimiter)
+	if size != len(p.Delimiter) || r == '"' || r == '\r' || r == '\n' || r == utf8.RuneError {
+		return 0, fmt.Errorf("delimiter must be a single character other than a quote or line break, got %q", p.Delimiter)
+	}
+	return r, nil
+}
+
+// decoder returns the transformer decoding the profile's encoding to UTF-8. UTF-8 input is passed
+// through unchanged, as it always was.
+func (p *Profile) decoder() (transform.Transformer, error) {
+	if p.Encoding == "" || strings.EqualFold(p.Encoding, "utf-8") || strings.EqualFold(p.Encoding, "utf8") {
+		return unicode.BOMOverride(transform.Nop), nil
+	}
+	encoding, err := htmlindex.Get(p.Encoding)
+	if err != nil {
+		return nil, fmt.Errorf("unknown encoding %q", p.Encoding)
+	}
+	return unicode.BOMOverride(encoding.NewDecoder()), nil
+}
+
+// ReadRecords reads the records of a CSV file with a header row, mapping its columns with the profile.
+// Values are transformed but not validated.
+func (p *Profile) ReadRecords(r io.Reader) ([]Record, error) {
+	decoder, err := p.decoder()
+	if err != nil {
+		return nil, err
+	}
+	buffered := bufio.NewReader(transform.NewReader(r, decoder))
+
+	delimiter := ','
+	if p.Delimiter != "" {
+		delimiter, _ = p.delimiter()
+	} else {
+		header, err := buffered.Peek(4096)
+		if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+			return nil, err
+		}
+		delimiter = detectDelimiter(header)
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.Comma = delimiter
+	reader.LazyQuotes = true
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("file is empty, expected a header row")
+	}
+
+	columns, err := p.columns(SanitizeHeader(rows[0]))
+	if err != nil {
+		return nil, err
+	}
+	records := make([]Record, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		value := func(field string) string {
+			return p.value(field, row, columns)
+		}
+		records = append(records, Record{
+			Row:         i + 2,
+			SwiftCode:   value(FieldSwiftCode),
+			CountryISO2: value(FieldCountryISO2),
+			BankName:    value(FieldBankName),
+			Address:     value(FieldAddress),
+			CountryName: value(FieldCountryName),
+		})
+	}
+	return records, nil
+}
+
+// columns maps every field to the index of its column in header, or -1 when the file has none.
+// Every missing required column is reported.
+func (p *Profile) columns(header []string) (map[string]int, error) {
+	columns := make(map[string]int, len(Fields))
+	var missing []string
+	for _, field := range Fields {
+		columns[field] = -1
+		mapping := p.Fields[field]
+		for _, name := range mapping.Headers {
+			if i := slices.Index(header, strings.ToUpper(strings.TrimSpace(name))); i >= 0 {
+				columns[field] = i
+				break
+			}
+		}
+		if columns[field] == -1 && mapping.Required {
+			missing = append(missing, fmt.Sprintf("%s (column %s)", field, strings.Join(quoteAll(mapping.Headers), " or ")))
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required columns in profile %s: %s", p.Name, strings.Join(missing, ", "))
+	}
+	return columns, nil
+}
+
+func (p *Profile) value(field string, row []string, columns map[string]int) string {
+	mapping := p.Fields[field]
+	var value string
+	if i := columns[field]; i >= 0 && i < len(row) {
+		value = row[i]
+	}
+	for _, name := range mapping.Transforms {
+		value = transforms[name](value)
+	}
+	if strings.TrimSpace(value) == "" {
+		return mapping.Default
+	}
+	return value
+}
+
+// detectDelimiter returns the delimiter used most in the first line of head, or a comma.
+func detectDelimiter(head []byte) rune {
+	if i := bytes.IndexByte(head, '\n'); i >= 0 {
+		head = head[:i]
+	}
+	best, bestCount := ',', 0
+	for _, delimiter := range delimiters {
+		if count := bytes.Count(head, []byte(string(delimiter))); count > bestCount {
+			best, bestCount = delimiter, count
+		}
+	}
+	return best
+}
+
+// titleCase creates a caser per call, as casers keep state and cannot be shared between goroutines.
+func titleCase(value string) string {
+	return cases.Title(language.Und).String(value)
+}
+
+func splitAddress(value string) string {
+	parts := strings.FieldsFunc(value, func(r rune) bool {
+		return r == '\n' || r == '\r' || r == ',' || r == ';' || r == '|'
+	})
+	nonEmpty := parts[:0]
+	for _, part := range parts {
+		if part = strings.Join(strings.Fields(part), " "); part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, ", ")
+}
+
+func quoteAll(values []string) []string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("%q", value)
+	}
+	return quoted
+}
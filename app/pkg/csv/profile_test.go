@@ -0,0 +1,113 @@
+// profile_test.go contains unit tests for column mapping profiles: loading them from YAML and JSON,
+// transforms, defaults, required columns, delimiters and encodings.
+package csv
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func writeProfile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestProfile_DefaultDelimiters(t *testing.T) {
+	for name, content := range map[string]string{
+		"comma":     "SWIFT CODE,COUNTRY ISO2 CODE,NAME,ADDRESS,COUNTRY NAME\nAAAAPLPWXXX,pl,Bank,\"1 Main St, Warsaw\",Poland\n",
+		"semicolon": "SWIFT CODE;COUNTRY ISO2 CODE;NAME;ADDRESS;COUNTRY NAME\nAAAAPLPWXXX;pl;Bank;\"1 Main St, Warsaw\";Poland\n",
+	} {
+		records, err := ReadRecords(strings.NewReader(content))
+		require.NoError(t, err, name)
+		assert.Equal(t, []Record{{Row: 2, SwiftCode: "AAAAPLPWXXX", CountryISO2: "PL", BankName: "BANK",
+			Address: "1 MAIN ST, WARSAW", CountryName: "POLAND"}}, records, name)
+	}
+}
+
+func TestProfile_MissingRequiredColumns(t *testing.T) {
+	_, err := ReadRecords(strings.NewReader("SWIFT CODE,NAME\nAAAAPLPWXXX,Bank\n"))
+	assert.EqualError(t, err, `missing required columns in profile default: countryISO2 (column "COUNTRY ISO2 CODE"), countryName (column "COUNTRY NAME")`)
+
+	records, err := ReadRecords(strings.NewReader("SWIFT CODE,COUNTRY ISO2 CODE,NAME,COUNTRY NAME\nAAAAPLPWXXX,PL,Bank,Poland\n"))
+	assert.NoError(t, err, "The address is optional")
+	assert.Equal(t, "", records[0].Address)
+}
+
+func TestProfile_UTF16(t *testing.T) {
+	encoded, err := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().String(
+		"SWIFT CODE\tCOUNTRY ISO2 CODE\tNAME\tADDRESS\tCOUNTRY NAME\r\nAAAACZPPXXX\tCZ\tBanka\tNáměstí 1\tCzechia\r\n")
+	require.NoError(t, err)
+
+	records, err := ReadRecords(strings.NewReader(encoded))
+	require.NoError(t, err, "A byte order mark should select UTF-16 without a profile")
+	assert.Equal(t, "NÁMĚSTÍ 1", records[0].Address)
+	assert.Equal(t, "AAAACZPPXXX", records[0].SwiftCode)
+}
+
+func TestLoadProfile(t *testing.T) {
+	yamlProfile := writeProfile(t, "polish-bank.yaml", `
+delimiter: ";"
+encoding: windows-1250
+fields:
+  swiftCode:   {headers: [BIC, Kod SWIFT], required: true, transforms: [trim, uppercase]}
+  countryISO2: {headers: [Kraj], transforms: [trim, uppercase], default: PL}
+  bankName:    {headers: [Nazwa], transforms: [trim, title-case]}
+  address:     {headers: [Adres], transforms: [split-address]}
+  countryName: {default: POLAND}
+`)
+	jsonProfile := writeProfile(t, "polish-bank.json", `{
+  "delimiter": ";",
+  "encoding": "windows-1250",
+  "fields": {
+    "swiftCode": {"headers": ["BIC", "Kod SWIFT"], "required": true, "transforms": ["trim", "uppercase"]},
+    "countryISO2": {"headers": ["Kraj"], "transforms": ["trim", "uppercase"], "default": "PL"},
+    "bankName": {"headers": ["Nazwa"], "transforms": ["trim", "title-case"]},
+    "address": {"headers": ["Adres"], "transforms": ["split-address"]},
+    "countryName": {"default": "POLAND"}
+  }
+}`)
+	data, err := charmap.Windows1250.NewEncoder().String("Kod SWIFT;Nazwa;Adres;Kraj\r\n" +
+		" bpkoplpwxxx ;POWSZECHNA KASA OSZCZĘDNOŚCI;\"ul. Puławska 15\nWarszawa;  02-515\";\r\n")
+	require.NoError(t, err)
+
+	for _, path := range []string{yamlProfile, jsonProfile} {
+		profile, err := LoadProfile(path)
+		require.NoError(t, err, path)
+		assert.Equal(t, "polish-bank", profile.Name, "The name should default to the file name")
+
+		records, err := profile.ReadRecords(strings.NewReader(data))
+		require.NoError(t, err, path)
+		assert.Equal(t, []Record{{
+			Row:         2,
+			SwiftCode:   "BPKOPLPWXXX",
+			CountryISO2: "PL",
+			BankName:    "Powszechna Kasa Oszczędności",
+			Address:     "ul. Puławska 15, Warszawa, 02-515",
+			CountryName: "POLAND",
+		}}, records, path)
+	}
+}
+
+func TestLoadProfile_Invalid(t *testing.T) {
+	for content, message := range map[string]string{
+		"fields: {swiftCode: {headers: [BIC]}, town: {headers: [CITY]}}":                   `unknown field "town"`,
+		"fields: {swiftCode: {headers: [BIC], transforms: [lowercase]}}":                   `unknown transform "lowercase"`,
+		"encoding: klingon\nfields: {swiftCode: {headers: [BIC]}}":                         `unknown encoding "klingon"`,
+		"delimiter: ';;'\nfields: {swiftCode: {headers: [BIC]}}":                           "delimiter must be a single character",
+		"fields: {swiftCode: {default: AAAAPLPWXXX}}":                                      "field swiftCode must name its headers",
+		"fields: {swiftCode: {headers: [BIC]}, bankName: {required: true}}":                "field bankName needs headers or a default",
+		"fields: {swiftCode: {headers: [BIC], requried: true}}":                            "field requried not found",
+		"fields: {swiftCode: {headers: [BIC]}, countryName: {default: x, required: true}}": "required but names no headers",
+	} {
+		_, err := LoadProfile(writeProfile(t, "profile.yaml", content))
+		assert.ErrorContains(t, err, message, content)
+	}
+}
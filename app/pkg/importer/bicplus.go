@@ -0,0 +1,207 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	parser "swift-app/pkg/csv"
+	"unicode/utf8"
+)
+
+// BIC Plus column names of the tab-delimited layout. Other columns are ignored.
+const (
+	bicPlusModificationFlag = "MODIFICATION FLAG"
+	bicPlusBIC8             = "BIC8"
+	bicPlusBranchBIC        = "BRANCH BIC"
+	bicPlusBIC              = "BIC"
+	bicPlusInstitutionName  = "INSTITUTION NAME"
+	bicPlusCity             = "CITY"
+	bicPlusZipCode          = "ZIP CODE"
+	bicPlusCountryName      = "COUNTRY NAME"
+	bicPlusISOCountryCode   = "ISO COUNTRY CODE"
+)
+
+// bicPlusStreetAddresses are the street address columns, joined in this order.
+var bicPlusStreetAddresses = []string{"STREET ADDRESS 1", "STREET ADDRESS 2", "STREET ADDRESS 3", "STREET ADDRESS 4"}
+
+// bicPlusDeleted is the modification flag of records removed from the directory. Delta files list
+// them so that they can be removed; an import skips them.
+const bicPlusDeleted = "D"
+
+// column is a field of a fixed-width record, as character offsets [start, end).
+type column struct{ start, end int }
+
+// The fixed-width BIC Plus layout. Lines shorter than the layout, for example because trailing
+// spaces were stripped, are padded.
+var (
+	fixedModificationFlag = column{0, 1}
+	fixedBIC8             = column{1, 9}
+	fixedBranchBIC        = column{9, 12}
+	fixedInstitutionName  = column{12, 117}
+	fixedStreetAddress    = column{117, 187}
+	fixedCity             = column{187, 222}
+	fixedZipCode          = column{222, 237}
+	fixedCountryName      = column{237, 307}
+	fixedISOCountryCode   = column{307, 309}
+)
+
+// bicPlusParser reads the SWIFTRef BIC Plus directory, either tab-delimited with a header row or
+// fixed-width without one. Records flagged as deleted are skipped, and the country code is taken
+// from the BIC when a record has none.
+type bicPlusParser struct{}
+
+func (bicPlusParser) Format() Format { return FormatBICPlus }
+
+func (bicPlusParser) Detect(head []byte) bool {
+	line := firstLine(head)
+	if bytes.IndexByte(line, '\t') >= 0 {
+		return bytes.Contains(bytes.ToUpper(line), []byte(bicPlusInstitutionName))
+	}
+	return isFixedWidthRecord(string(line))
+}
+
+// isFixedWidthRecord reports whether line starts with a modification flag and an 11 character BIC,
+// and is long enough to hold an institution name.
+func isFixedWidthRecord(line string) bool {
+	if utf8.RuneCountInString(line) <= fixedInstitutionName.start || !strings.ContainsAny(line[:1], "AMDU") {
+		return false
+	}
+	for _, r := range line[fixedBIC8.start:fixedBranchBIC.end] {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func (p bicPlusParser) Records(r io.Reader) ([]parser.Record, error) {
+	reader := bufio.NewReader(r)
+	head, err := reader.Peek(detectSize)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+	var records []parser.Record
+	if bytes.IndexByte(firstLine(head), '\t') >= 0 {
+		records, err = p.tabDelimited(reader)
+	} else {
+		records, err = p.fixedWidth(reader)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return withCountryNames(records)
+}
+
+func (bicPlusParser) tabDelimited(r io.Reader) ([]parser.Record, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = '\t'
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("file is empty, expected a header row")
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if _, duplicate := columns[name]; !duplicate {
+			columns[name] = i
+		}
+	}
+	_, hasBIC8 := columns[bicPlusBIC8]
+	_, hasBIC := columns[bicPlusBIC]
+	if !hasBIC8 && !hasBIC {
+		return nil, fmt.Errorf("missing required column: %s or %s", bicPlusBIC8, bicPlusBIC)
+	}
+	if _, ok := columns[bicPlusInstitutionName]; !ok {
+		return nil, fmt.Errorf("missing required column: %s", bicPlusInstitutionName)
+	}
+
+	var records []parser.Record
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		row, _ := reader.FieldPos(0)
+		value := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(fields) {
+				return fields[i]
+			}
+			return ""
+		}
+		if normalize(value(bicPlusModificationFlag)) == bicPlusDeleted {
+			continue
+		}
+		code := value(bicPlusBIC)
+		if hasBIC8 {
+			code = swiftCode(value(bicPlusBIC8), value(bicPlusBranchBIC))
+		} else if len(strings.TrimSpace(code)) == 8 {
+			code = swiftCode(code, "")
+		}
+		streets := make([]string, len(bicPlusStreetAddresses))
+		for i, name := range bicPlusStreetAddresses {
+			streets[i] = value(name)
+		}
+		address := joinAddress(append(streets, value(bicPlusCity), value(bicPlusZipCode))...)
+		records = append(records, newRecord(row, code, countryCode(value(bicPlusISOCountryCode), code),
+			value(bicPlusInstitutionName), address, value(bicPlusCountryName)))
+	}
+}
+
+func (bicPlusParser) fixedWidth(r io.Reader) ([]parser.Record, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var records []parser.Record
+	for row := 1; scanner.Scan(); row++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		chars := []rune(line)
+		if len(chars) <= fixedInstitutionName.start {
+			return nil, fmt.Errorf("line %d is too short for a fixed-width record", row)
+		}
+		field := func(c column) string {
+			if c.start >= len(chars) {
+				return ""
+			}
+			return string(chars[c.start:min(c.end, len(chars))])
+		}
+		if normalize(field(fixedModificationFlag)) == bicPlusDeleted {
+			continue
+		}
+		code := swiftCode(field(fixedBIC8), field(fixedBranchBIC))
+		address := joinAddress(field(fixedStreetAddress), field(fixedCity), field(fixedZipCode))
+		records = append(records, newRecord(row, code, countryCode(field(fixedISOCountryCode), code),
+			field(fixedInstitutionName), address, field(fixedCountryName)))
+	}
+	return records, scanner.Err()
+}
+
+// countryCode returns iso2, or the country code of swiftCode (its 5th and 6th characters) when iso2 is empty.
+func countryCode(iso2, swiftCode string) string {
+	if iso2 = strings.TrimSpace(iso2); iso2 == "" && len(swiftCode) >= 6 {
+		return swiftCode[4:6]
+	}
+	return iso2
+}
+
+// firstLine returns the first line of head without its line ending.
+func firstLine(head []byte) []byte {
+	if i := bytes.IndexByte(head, '\n'); i >= 0 {
+		head = head[:i]
+	}
+	return bytes.TrimRight(head, "\r")
+}
@@ -0,0 +1,268 @@
+// Package importer reads SWIFT code import files in every supported format: the CSV layout of
+// pkg/csv and the vendor BIC directory formats (BIC Plus and ISO 20022 XML). Each format is a
+// Parser; the format of a file is detected from its first bytes unless it is given.
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"swift-app/internal/models"
+	"swift-app/internal/utils"
+	parser "swift-app/pkg/csv"
+	"sync"
+)
+
+// Format names an import file format.
+type Format string
+
+// Supported formats. FormatAuto detects the format of each file.
+const (
+	FormatAuto     Format = "auto"
+	FormatCSV      Format = "csv"
+	FormatBICPlus  Format = "bicplus"
+	FormatISO20022 Format = "iso20022"
+)
+
+// detectSize is how much of a file Detect is shown.
+const detectSize = 4096
+
+// byteOrderMark may start UTF-8 files written on Windows. Read removes it before parsing.
+const byteOrderMark = "\ufeff"
+
+// Parser reads the SWIFT code records of one file format.
+type Parser interface {
+	// Format is the name the format is selected by.
+	Format() Format
+	// Detect reports whether head, the first bytes of a file, is in this format.
+	Detect(head []byte) bool
+	// Records reads every record of r. Values are normalized but not validated; records the file
+	// marks as deleted are left out.
+	Records(r io.Reader) ([]parser.Record, error)
+}
+
+var registry = struct {
+	sync.RWMutex
+	parsers []Parser
+}{parsers: []Parser{iso20022Parser{}, bicPlusParser{}, csvParser{}}}
+
+// Register adds a parser for another format. Registered parsers are tried before the built-in ones
+// when detecting a format, and replace a built-in parser of the same format.
+func Register(p Parser) {
+	registry.Lock()
+	defer registry.Unlock()
+	parsers := []Parser{p}
+	for _, existing := range registry.parsers {
+		if existing.Format() != p.Format() {
+			parsers = append(parsers, existing)
+		}
+	}
+	registry.parsers = parsers
+}
+
+func parsers() []Parser {
+	registry.RLock()
+	defer registry.RUnlock()
+	return registry.parsers
+}
+
+// Formats lists the formats that can be selected, FormatAuto first.
+func Formats() []Format {
+	formats := []Format{FormatAuto}
+	for _, p := range parsers() {
+		formats = append(formats, p.Format())
+	}
+	return formats
+}
+
+// ParseFormat parses a format name, defaulting to FormatAuto when it is empty.
+func ParseFormat(value string) (Format, error) {
+	if value == "" {
+		return FormatAuto, nil
+	}
+	formats := Formats()
+	names := make([]string, len(formats))
+	for i, format := range formats {
+		if strings.EqualFold(value, string(format)) {
+			return format, nil
+		}
+		names[i] = string(format)
+	}
+	return "", fmt.Errorf("unknown import format %q, expected one of %s", value, strings.Join(names, ", "))
+}
+
+// Detect returns the parser of the first format head, the first bytes of a file, is in.
+func Detect(head []byte) (Parser, error) {
+	for _, p := range parsers() {
+		if p.Detect(head) {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("unrecognized file format, expected one of %s", strings.Join(formatNames(), ", "))
+}
+
+func formatNames() []string {
+	var names []string
+	for _, p := range parsers() {
+		names = append(names, string(p.Format()))
+	}
+	return names
+}
+
+// Options select how a file is read.
+type Options struct {
+	// Format is the format of the file; FormatAuto, or empty, detects it.
+	Format Format
+	// Profile maps the columns of a CSV file. Selecting a profile selects FormatCSV; nil reads the
+	// default layout of pkg/csv.
+	Profile *parser.Profile
+}
+
+// ReadFile reads the records of the file at path and returns the format that was read.
+func ReadFile(path string, opts Options) ([]parser.Record, Format, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+	defer file.Close()
+	return Read(file, opts)
+}
+
+// Read reads the records of r and returns the format that was read. A UTF-8 byte order mark is skipped.
+func Read(r io.Reader, opts Options) ([]parser.Record, Format, error) {
+	reader := bufio.NewReaderSize(r, detectSize)
+	if bom, _ := reader.Peek(len(byteOrderMark)); string(bom) == byteOrderMark {
+		_, _ = reader.Discard(len(byteOrderMark))
+	}
+	p, err := parserFor(reader, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	records, err := p.Records(reader)
+	if err != nil {
+		return nil, p.Format(), fmt.Errorf("invalid %s file: %w", p.Format(), err)
+	}
+	return records, p.Format(), nil
+}
+
+func parserFor(reader *bufio.Reader, opts Options) (Parser, error) {
+	format := opts.Format
+	if opts.Profile != nil {
+		if format != "" && format != FormatAuto && format != FormatCSV {
+			return nil, fmt.Errorf("profile %s maps CSV columns and cannot read %s files", opts.Profile.Name, format)
+		}
+		return csvParser{profile: opts.Profile}, nil
+	}
+	if format == "" || format == FormatAuto {
+		head, err := reader.Peek(detectSize)
+		if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+			return nil, err
+		}
+		if len(head) == 0 {
+			return nil, fmt.Errorf("file is empty")
+		}
+		return Detect(head)
+	}
+	for _, p := range parsers() {
+		if p.Format() == format {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown import format %q", format)
+}
+
+// LoadFile reads the file at path and returns its unique, valid SWIFT codes. Invalid records are
+// logged and skipped, as in pkg/csv.LoadSwiftCodes.
+func LoadFile(path string, opts Options) ([]models.SwiftCode, Format, error) {
+	records, format, err := ReadFile(path, opts)
+	if err != nil {
+		return nil, format, err
+	}
+	codes, err := parser.LoadRecords(records)
+	return codes, format, err
+}
+
+// ValidateFile reads the file at path and reports every invalid record without importing it.
+func ValidateFile(path string, opts Options) (*parser.Report, Format, error) {
+	records, format, err := ReadFile(path, opts)
+	if err != nil {
+		return nil, format, err
+	}
+	report, err := parser.ValidateRecords(records)
+	return report, format, err
+}
+
+// csvParser reads the CSV layout of pkg/csv, or the layout of a profile. It accepts any file the
+// other formats do not, so it is detected last.
+type csvParser struct {
+	profile *parser.Profile
+}
+
+func (csvParser) Format() Format { return FormatCSV }
+
+func (csvParser) Detect(head []byte) bool {
+	return len(bytes.TrimSpace(head)) > 0
+}
+
+func (p csvParser) Records(r io.Reader) ([]parser.Record, error) {
+	if p.profile != nil {
+		return p.profile.ReadRecords(r)
+	}
+	return parser.ReadRecords(r)
+}
+
+// newRecord builds a record from vendor values, normalized the way pkg/csv normalizes CSV values.
+func newRecord(row int, swiftCode, countryISO2, bankName, address, countryName string) parser.Record {
+	return parser.Record{
+		Row:         row,
+		SwiftCode:   normalize(swiftCode),
+		CountryISO2: normalize(countryISO2),
+		BankName:    normalize(bankName),
+		Address:     normalize(address),
+		CountryName: normalize(countryName),
+	}
+}
+
+// withCountryNames gives records without a country name the name of their country, for formats
+// that only carry the ISO2 code. Records of unknown countries are left for validation to reject.
+func withCountryNames(records []parser.Record) ([]parser.Record, error) {
+	countries, err := utils.LoadCountries()
+	if err != nil {
+		return nil, fmt.Errorf("error loading country data: %v", err)
+	}
+	for i, record := range records {
+		if country, ok := countries[record.CountryISO2]; ok && record.CountryName == "" {
+			records[i].CountryName = country.Name
+		}
+	}
+	return records, nil
+}
+
+func normalize(value string) string {
+	return strings.ToUpper(strings.TrimSpace(value))
+}
+
+// joinAddress joins the non-empty parts of an address with commas, the way addresses are written in
+// the CSV files.
+func joinAddress(parts ...string) string {
+	var nonEmpty []string
+	for _, part := range parts {
+		if part = strings.Join(strings.Fields(part), " "); part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, ", ")
+}
+
+// swiftCode joins a BIC8 and a branch code into an 11 character code. Vendor files identify a head
+// office by the branch code XXX or by leaving it empty.
+func swiftCode(bic8, branch string) string {
+	bic8, branch = strings.TrimSpace(bic8), strings.TrimSpace(branch)
+	if branch == "" {
+		branch = "XXX"
+	}
+	return bic8 + branch
+}
@@ -0,0 +1,163 @@
+// importer_test.go contains unit tests for format detection and for reading the vendor formats from
+// the synthetic fixtures in testdata.
+package importer
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"swift-app/internal/models"
+	parser "swift-app/pkg/csv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCSV = `SWIFT CODE,COUNTRY ISO2 CODE,NAME,ADDRESS,COUNTRY NAME
+TESTPLPWXXX,PL,Test Bank Polska S.A.,"UL. PROSTA 1, WARSZAWA, 00-001",Poland
+`
+
+// fixtureCodes are the valid SWIFT codes of every fixture. The deleted and the invalid records are left out.
+func fixtureCodes() []models.SwiftCode {
+	return []models.SwiftCode{
+		{SwiftCode: "TESTPLPWXXX", CountryISO2: "PL", CountryName: "POLAND", BankName: "TEST BANK POLSKA S.A.",
+			Address: "UL. PROSTA 1, WARSZAWA, 00-001", IsHeadquarter: true, Branches: []models.SwiftBranch{}},
+		{SwiftCode: "TESTPLPWKRK", CountryISO2: "PL", CountryName: "POLAND", BankName: "TEST BANK POLSKA S.A.",
+			Address: "UL. DLUGA 2, FLOOR 3, KRAKOW, 31-001"},
+		{SwiftCode: "SAMPDEFFXXX", CountryISO2: "DE", CountryName: "GERMANY", BankName: "SAMPLE BANK AG",
+			Address: "HAUPTSTRASSE 5, FRANKFURT AM MAIN, 60311", IsHeadquarter: true, Branches: []models.SwiftBranch{}},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatAuto, format)
+
+	format, err = ParseFormat("BICPlus")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatBICPlus, format)
+
+	_, err = ParseFormat("swift-mt")
+	assert.ErrorContains(t, err, "unknown import format")
+}
+
+func TestRead_Detect(t *testing.T) {
+	for file, expected := range map[string]Format{
+		"bicplus.txt":       FormatBICPlus,
+		"bicplus_fixed.txt": FormatBICPlus,
+		"iso20022.xml":      FormatISO20022,
+	} {
+		_, format, err := ReadFile(filepath.Join("testdata", file), Options{})
+		assert.NoError(t, err, file)
+		assert.Equal(t, expected, format, file)
+	}
+
+	records, format, err := Read(strings.NewReader(byteOrderMark+testCSV), Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, FormatCSV, format)
+	assert.Len(t, records, 1)
+
+	_, _, err = Read(strings.NewReader(""), Options{})
+	assert.ErrorContains(t, err, "file is empty")
+
+	_, _, err = Read(strings.NewReader(testCSV), Options{Format: FormatISO20022})
+	assert.ErrorContains(t, err, "invalid iso20022 file", "A selected format should not be detected")
+}
+
+func TestLoadFile(t *testing.T) {
+	for _, file := range []string{"bicplus.txt", "bicplus_fixed.txt"} {
+		codes, _, err := LoadFile(filepath.Join("testdata", file), Options{Format: FormatBICPlus})
+		require.NoError(t, err, file)
+		assert.Equal(t, fixtureCodes(), codes, file)
+	}
+
+	codes, _, err := LoadFile(filepath.Join("testdata", "iso20022.xml"), Options{})
+	require.NoError(t, err)
+	expected := fixtureCodes()
+	expected[2].Address = "HAUPTSTRASSE 5, 60311 FRANKFURT AM MAIN"
+	assert.Equal(t, expected, codes, "Country names should come from the countries file")
+}
+
+func TestValidateFile(t *testing.T) {
+	for file, row := range map[string]int{
+		"bicplus.txt":       6,
+		"bicplus_fixed.txt": 5,
+		"iso20022.xml":      42,
+	} {
+		report, _, err := ValidateFile(filepath.Join("testdata", file), Options{})
+		require.NoError(t, err, file)
+		assert.Equal(t, 4, report.Records, file)
+		assert.Equal(t, 3, report.Valid, file)
+		if assert.Len(t, report.Errors, 1, file) {
+			assert.Equal(t, parser.RecordError{Row: row, SwiftCode: "BADCQQ12XXX", Message: report.Errors[0].Message}, report.Errors[0], file)
+			assert.Contains(t, report.Errors[0].Message, "invalid country", file)
+		}
+	}
+}
+
+func TestRead_MissingColumn(t *testing.T) {
+	_, _, err := Read(strings.NewReader("BIC8\tBRANCH BIC\tCITY\nTESTPLPW\tXXX\tWARSZAWA\n"), Options{Format: FormatBICPlus})
+	assert.ErrorContains(t, err, "missing required column: INSTITUTION NAME")
+
+	_, _, err = Read(strings.NewReader("ATESTPLPW\n"), Options{Format: FormatBICPlus})
+	assert.ErrorContains(t, err, "line 1 is too short")
+}
+
+// pipeParser reads pipe separated SWIFT codes and country codes, to test registering a format.
+type pipeParser struct{}
+
+func (pipeParser) Format() Format { return "pipe" }
+
+func (pipeParser) Detect(head []byte) bool { return strings.Contains(string(head), "|") }
+
+func (pipeParser) Records(r io.Reader) ([]parser.Record, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var records []parser.Record
+	for i, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		code, country, _ := strings.Cut(line, "|")
+		records = append(records, newRecord(i+1, code, country, "", "", ""))
+	}
+	return withCountryNames(records)
+}
+
+func TestRegister(t *testing.T) {
+	builtIn := parsers()
+	t.Cleanup(func() { registry.parsers = builtIn })
+
+	Register(pipeParser{})
+	assert.Contains(t, Formats(), Format("pipe"))
+	records, format, err := Read(strings.NewReader("testplpwxxx|pl\n"), Options{})
+	require.NoError(t, err)
+	assert.Equal(t, Format("pipe"), format)
+	assert.Equal(t, []parser.Record{{Row: 1, SwiftCode: "TESTPLPWXXX", CountryISO2: "PL", CountryName: "POLAND"}}, records)
+}
+
+func TestRead_Profile(t *testing.T) {
+	profile := &parser.Profile{
+		Name:      "pipes",
+		Delimiter: "|",
+		Fields: map[string]parser.FieldMapping{
+			parser.FieldSwiftCode:   {Headers: []string{"BIC"}, Required: true, Transforms: []string{parser.TransformUppercase}},
+			parser.FieldCountryISO2: {Default: "PL"},
+			parser.FieldBankName:    {Headers: []string{"BANK"}},
+			parser.FieldCountryName: {Default: "POLAND"},
+		},
+	}
+	require.NoError(t, profile.Validate())
+
+	records, format, err := Read(strings.NewReader("BIC|BANK\ntestplpwxxx|Test Bank\n"), Options{Profile: profile})
+	require.NoError(t, err)
+	assert.Equal(t, FormatCSV, format, "A profile should select the CSV format")
+	assert.Equal(t, []parser.Record{{Row: 2, SwiftCode: "TESTPLPWXXX", CountryISO2: "PL", BankName: "Test Bank", CountryName: "POLAND"}}, records)
+
+	_, _, err = Read(strings.NewReader("BANK\nTest Bank\n"), Options{Profile: profile})
+	assert.ErrorContains(t, err, `missing required columns in profile pipes: swiftCode (column "BIC")`)
+
+	_, _, err = Read(strings.NewReader(testCSV), Options{Format: FormatBICPlus, Profile: profile})
+	assert.ErrorContains(t, err, "cannot read bicplus files")
+}
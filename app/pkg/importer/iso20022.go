@@ -0,0 +1,90 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	parser "swift-app/pkg/csv"
+)
+
+// iso20022Element is the element holding one financial institution.
+const iso20022Element = "FinInstnId"
+
+// finInstnID is an ISO 20022 financial institution identification. Namespaces are ignored, so any
+// message version can be read.
+type finInstnID struct {
+	BICFI string `xml:"BICFI"`
+	// BIC is the element name of older message versions.
+	BIC     string     `xml:"BIC"`
+	Nm      string     `xml:"Nm"`
+	PstlAdr postalAddr `xml:"PstlAdr"`
+}
+
+type postalAddr struct {
+	StrtNm      string   `xml:"StrtNm"`
+	BldgNb      string   `xml:"BldgNb"`
+	BldgNm      string   `xml:"BldgNm"`
+	Flr         string   `xml:"Flr"`
+	PstCd       string   `xml:"PstCd"`
+	TwnNm       string   `xml:"TwnNm"`
+	CtrySubDvsn string   `xml:"CtrySubDvsn"`
+	Ctry        string   `xml:"Ctry"`
+	AdrLine     []string `xml:"AdrLine"`
+}
+
+// address joins the structured address fields, or the unstructured address lines, in the order
+// CSV addresses are written.
+func (a postalAddr) address() string {
+	parts := []string{a.StrtNm + " " + a.BldgNb, a.BldgNm, a.Flr}
+	parts = append(parts, a.AdrLine...)
+	return joinAddress(append(parts, a.TwnNm, a.CtrySubDvsn, a.PstCd)...)
+}
+
+// iso20022Parser reads XML files of ISO 20022 financial institution identifications (FinInstnId
+// elements), wherever they appear in the document. The country name is taken from the countries
+// file, and the country code from the BIC when the address has none.
+type iso20022Parser struct{}
+
+func (iso20022Parser) Format() Format { return FormatISO20022 }
+
+func (iso20022Parser) Detect(head []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(head), []byte("<"))
+}
+
+func (iso20022Parser) Records(r io.Reader) ([]parser.Record, error) {
+	decoder := xml.NewDecoder(r)
+	var records []parser.Record
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != iso20022Element {
+			continue
+		}
+		row, _ := decoder.InputPos()
+		var institution finInstnID
+		if err := decoder.DecodeElement(&institution, &start); err != nil {
+			return nil, fmt.Errorf("line %d: %w", row, err)
+		}
+		code := strings.TrimSpace(institution.BICFI)
+		if code == "" {
+			code = strings.TrimSpace(institution.BIC)
+		}
+		if len(code) == 8 {
+			code = swiftCode(code, "")
+		}
+		records = append(records, newRecord(row, code, countryCode(institution.PstlAdr.Ctry, code),
+			institution.Nm, institution.PstlAdr.address(), ""))
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no %s elements found", iso20022Element)
+	}
+	return withCountryNames(records)
+}